@@ -9,7 +9,8 @@ require (
 	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/lestrrat-go/jwx/v2 v2.1.6
 	github.com/lib/pq v1.10.9
-	github.com/nguyenthenguyen/docx v0.0.0-20230621112118-9c8e795a11db
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/net v0.34.0
 	golang.org/x/text v0.31.0
 )
 
@@ -2,9 +2,22 @@ package config
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"resume-backend-service/internal/events"
 	"resume-backend-service/internal/middleware"
+	"resume-backend-service/internal/outbox"
+	"resume-backend-service/internal/repository"
 	router "resume-backend-service/internal/router"
+	"resume-backend-service/internal/services"
+	"resume-backend-service/internal/workers"
+	"resume-backend-service/pkg/client"
+	"resume-backend-service/pkg/converter"
+	"resume-backend-service/pkg/httpx"
+	"resume-backend-service/pkg/storage"
+	"syscall"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -13,10 +26,15 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// outboxMemoryCapacity es la cantidad de eventos que conserva el publisher en memoria
+const outboxMemoryCapacity = 1000
+
 type Application struct {
-	App    *fiber.App
-	Config *Config
-	DB     *sql.DB
+	App      *fiber.App
+	Config   *Config
+	DB       *sql.DB
+	EventBus *events.Bus
+	Workers  []workers.Worker
 }
 
 func Bootstrap() *Application {
@@ -37,9 +55,7 @@ func Bootstrap() *Application {
 	}
 
 	// Crear instancia de Fiber
-	app := fiber.New(fiber.Config{
-		AppName: "Resume Backend Service",
-	})
+	app := fiber.New(newFiberConfig())
 
 	// Middlewares globales
 	app.Use(cors.New(cors.Config{
@@ -54,20 +70,162 @@ func Bootstrap() *Application {
 	// Inicializar middleware de autenticación
 	authMiddleware := middleware.NewAuthMiddleware(cfg.AuthJWKSURL)
 
+	// Inicializar middleware de firma HMAC para el callback de AWS Lambda
+	callbackMiddleware := middleware.NewCallbackSignatureMiddleware(cfg.CallbackSigningSecrets, cfg.CallbackSignatureTolerance)
+	if cfg.CallbackSigningSecrets[0] == "" {
+		log.Println("⚠️  CALLBACK_HMAC_SECRET no configurado: se rechazarán todos los callbacks de resultados")
+	}
+
+	// Directorio donde se guardan los archivos hasta que la cola de ingesta los procesa
+	if err := os.MkdirAll(cfg.IngestionSpoolDir, 0o700); err != nil {
+		log.Fatalf("❌ Error al crear directorio de spool %s: %v", cfg.IngestionSpoolDir, err)
+	}
+
+	// Conversión de imágenes a PDF (resolución y tamaño de página)
+	if err := converter.SetImageOptions(converter.ImageOptions{MaxDPI: cfg.ImageMaxDPI, PageSize: cfg.ImagePageSize}); err != nil {
+		log.Fatalf("❌ Configuración de imágenes inválida: %v", err)
+	}
+
+	// Almacenamiento de los PDF que procesa la Lambda (S3 o directorio local)
+	store, err := newStorage(cfg)
+	if err != nil {
+		log.Fatalf("❌ Error al inicializar el almacenamiento: %v", err)
+	}
+
+	// Bus de eventos en memoria para notificar cambios de estado (SSE)
+	eventBus := events.NewBus(cfg.EventHistorySize)
+
 	// Registrar rutas (pasar base de datos, configuración y middleware)
-	router.SetupRoutes(app, db, cfg.PresignedURLServiceEndpoint, authMiddleware)
+	router.SetupRoutes(app, db, store, cfg.IngestionSpoolDir, cfg.CallbackReplayPolicy, cfg.MaxFileSize, cfg.MaxPDFPages, cfg.DefaultQuota(), cfg.BatchLimits(), eventBus, cfg.SSEHeartbeatInterval, cfg.WebhookAllowInsecureURLs, authMiddleware, callbackMiddleware)
+
+	// Iniciar workers en segundo plano
+	backgroundWorkers := []workers.Worker{
+		workers.NewIngestionWorker(
+			repository.NewIngestionJobRepository(db),
+			services.NewResumeService(
+				store,
+				repository.NewUnitOfWork(db),
+				repository.NewResumeRequestRepository(db),
+				repository.NewIngestionJobRepository(db),
+				repository.NewProcessedResumeRepository(db),
+				repository.NewResumeVersionRepository(db),
+				services.NewQuotaService(repository.NewQuotaRepository(db), cfg.DefaultQuota(), cfg.MaxFileSize, cfg.MaxPDFPages),
+				cfg.IngestionSpoolDir,
+			),
+			cfg.IngestionWorkers,
+			cfg.IngestionPollInterval,
+			cfg.IngestionJobTimeout,
+			cfg.IngestionMaxAttempts,
+			cfg.BatchMaxConcurrency,
+		),
+		workers.NewStaleRequestReaper(
+			repository.NewResumeRequestRepository(db),
+			cfg.StaleRequestReaperInterval,
+			cfg.StaleRequestTimeout,
+		),
+		workers.NewWebhookDeliveryWorker(
+			repository.NewWebhookRepository(db),
+			client.NewWebhookClient(cfg.WebhookTimeout),
+			cfg.WebhookDeliveryInterval,
+			cfg.WebhookTimeout,
+			cfg.WebhookMaxAttempts,
+			cfg.WebhookDisableAfterFailures,
+		),
+		workers.NewOutboxDispatcher(
+			repository.NewUnitOfWork(db),
+			repository.NewOutboxRepository(db),
+			newOutboxPublisher(cfg, db, eventBus),
+			cfg.OutboxPollInterval,
+		),
+	}
+	for _, worker := range backgroundWorkers {
+		worker.Start()
+	}
 
 	return &Application{
-		App:    app,
-		Config: cfg,
-		DB:     db,
+		App:      app,
+		Config:   cfg,
+		DB:       db,
+		EventBus: eventBus,
+		Workers:  backgroundWorkers,
+	}
+}
+
+// newFiberConfig retorna la configuración de Fiber. El body de las solicitudes se recibe
+// como stream y los formularios multipart se leen recién en el handler, guardando los
+// archivos en disco: la memoria por subida no crece con el tamaño del archivo. Con el
+// body como stream, el límite de tamaño de cada ruta lo aplica middleware.BodyLimit.
+func newFiberConfig() fiber.Config {
+	return fiber.Config{
+		AppName:                      "Resume Backend Service",
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	}
+}
+
+// newStorage crea el driver de almacenamiento de STORAGE_DRIVER
+func newStorage(cfg *Config) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case storage.DriverPresignedS3:
+		presignedURLClient := client.NewPresignedURLClient(cfg.PresignedURLServiceEndpoint, httpx.New("presign", cfg.PresignHTTPOptions()))
+		return storage.NewPresignedS3Storage(presignedURLClient, httpx.New("s3-upload", cfg.UploadHTTPOptions())), nil
+	case storage.DriverLocal:
+		baseURL := cfg.StorageLocalBaseURL
+		if baseURL == "" {
+			baseURL = "http://localhost:" + cfg.Port
+		}
+		log.Printf("💾 Almacenamiento local en %s (descargas en %s%s)", cfg.StorageLocalDir, baseURL, storage.LocalDownloadPath)
+		return storage.NewLocalStorage(cfg.StorageLocalDir, baseURL, cfg.StorageLocalSigningSecret)
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER desconocido: %q (usar %s o %s)", cfg.StorageDriver, storage.DriverPresignedS3, storage.DriverLocal)
 	}
 }
 
+// newOutboxPublisher arma los destinos de los eventos del outbox: el bus SSE y los
+// webhooks siempre, más los publishers opcionales de OUTBOX_PUBLISHERS
+func newOutboxPublisher(cfg *Config, db *sql.DB, eventBus *events.Bus) outbox.Publisher {
+	publishers := []outbox.Publisher{
+		outbox.NewBusPublisher(eventBus),
+		services.NewWebhookService(repository.NewWebhookRepository(db), cfg.WebhookAllowInsecureURLs),
+	}
+
+	for _, name := range cfg.OutboxPublishers {
+		switch name {
+		case "log":
+			publishers = append(publishers, outbox.NewLogPublisher())
+		case "memory":
+			publishers = append(publishers, outbox.NewMemoryPublisher(outboxMemoryCapacity))
+		default:
+			log.Printf("⚠️  Publisher de outbox desconocido ignorado: %s", name)
+		}
+	}
+
+	return outbox.NewMultiPublisher(publishers...)
+}
+
 func (a *Application) Run() {
 	defer a.DB.Close()
 
+	// Apagado ordenado: dejar de aceptar peticiones, detener workers y cerrar la BD
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-shutdown
+		log.Println("🛑 Señal de apagado recibida, deteniendo servidor...")
+		// Cerrar los streams SSE abiertos; si no, Shutdown esperaría a que el cliente corte
+		a.EventBus.Close()
+		if err := a.App.Shutdown(); err != nil {
+			log.Printf("⚠️  Error al detener el servidor: %v", err)
+		}
+	}()
+
 	if err := a.App.Listen(":" + a.Config.Port); err != nil {
 		log.Fatalf("❌ Error al iniciar el servidor: %v", err)
 	}
+
+	for _, worker := range a.Workers {
+		worker.Stop()
+	}
+
+	log.Println("👋 Servidor detenido")
 }
@@ -1,8 +1,15 @@
 package config
 
 import (
+	"log"
 	"os"
+	"path/filepath"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/services"
+	"resume-backend-service/pkg/httpx"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config contiene todos los parámetros esenciales para la aplicación.
@@ -12,16 +19,78 @@ type Config struct {
 
 	// Configuración de Almacenamiento/Archivos
 	MaxFileSize int64
+	MaxPDFPages int
+
+	// Cuotas por defecto de cada usuario (0 = sin límite)
+	MaxRequestsPerDay int
+	MaxStoragePerUser int64
+
+	// Configuración del Almacenamiento de los PDF (driver y opciones del driver local)
+	StorageDriver             string
+	StorageLocalDir           string
+	StorageLocalBaseURL       string
+	StorageLocalSigningSecret string
 
 	// Configuración de Servicios Externos
 	PresignedURLServiceEndpoint string
 
+	// Configuración de las llamadas HTTP salientes (presigned URLs y subidas a S3)
+	PresignTimeout       time.Duration
+	UploadTimeout        time.Duration
+	HTTPMaxAttempts      int
+	HTTPRetryBaseDelay   time.Duration
+	HTTPRetryMaxDelay    time.Duration
+	HTTPBreakerThreshold int
+	HTTPBreakerCooldown  time.Duration
+
 	// Configuración de Autenticación
 	AuthJWKSURL string
 
 	// Configuración de CORS
 	CORSAllowedOrigins string
 
+	// Configuración del Callback de AWS Lambda (firma HMAC)
+	CallbackSigningSecrets     []string
+	CallbackSignatureTolerance time.Duration
+	CallbackReplayPolicy       string
+
+	// Configuración del Reaper de solicitudes sin resultado
+	StaleRequestReaperInterval time.Duration
+	StaleRequestTimeout        time.Duration
+
+	// Configuración del stream de eventos (SSE)
+	SSEHeartbeatInterval time.Duration
+	EventHistorySize     int
+
+	// Configuración de Webhooks salientes
+	WebhookDeliveryInterval     time.Duration
+	WebhookTimeout              time.Duration
+	WebhookMaxAttempts          int
+	WebhookDisableAfterFailures int
+	WebhookAllowInsecureURLs    bool
+
+	// Configuración de la cola de ingesta (conversión y subida a S3)
+	IngestionSpoolDir     string
+	IngestionWorkers      int
+	IngestionPollInterval time.Duration
+	IngestionJobTimeout   time.Duration
+	IngestionMaxAttempts  int
+
+	// Configuración de los lotes de CVs (varios archivos o un ZIP por solicitud)
+	BatchMaxFiles            int
+	BatchMaxUploadSize       int64
+	BatchMaxUncompressedSize int64
+	BatchMaxCompressionRatio int64
+	BatchMaxConcurrency      int
+
+	// Configuración de la conversión de imágenes (CVs fotografiados o escaneados)
+	ImageMaxDPI   int
+	ImagePageSize string
+
+	// Configuración del Outbox de eventos de dominio
+	OutboxPollInterval time.Duration
+	OutboxPublishers   []string
+
 	// Configuración de Base de Datos
 	DatabaseHost     string
 	DatabasePort     string
@@ -44,8 +113,26 @@ func Load() *Config {
 		// 2. Tamaño Máximo de Archivo en MB (se convierte a bytes internamente)
 		MaxFileSize: getEnvAsInt64("MAX_FILE_SIZE_MB", defaultMaxFileSizeMB) * 1024 * 1024,
 
-		// 3. Endpoint del Servicio de Presigned URL (ESENCIAL)
-		// Requerido para que el handler sepa a dónde llamar para obtener la URL de subida.
+		// Cantidad máxima de páginas de los PDF recibidos (0 = sin límite)
+		MaxPDFPages: int(getEnvAsInt64("PDF_MAX_PAGES", 20)),
+
+		// Cuotas por usuario: solicitudes por día (UTC) y almacenamiento total en MB.
+		// Se pueden personalizar por usuario en la tabla user_quotas
+		MaxRequestsPerDay: int(getEnvAsInt64("QUOTA_MAX_REQUESTS_PER_DAY", 50)),
+		MaxStoragePerUser: getEnvAsInt64("QUOTA_MAX_STORAGE_MB", 500) * 1024 * 1024,
+
+		// 3. Almacenamiento de los PDF: "s3presign" (S3 con el servicio de presigned URLs)
+		// o "local" (un directorio, para desarrollo sin servicios externos)
+		StorageDriver: getEnv("STORAGE_DRIVER", "s3presign"),
+
+		// Driver local: directorio, URL pública del servicio para las descargas firmadas
+		// (por defecto http://localhost:<SERVER_PORT>) y secreto de firma
+		StorageLocalDir:           getEnv("STORAGE_LOCAL_DIR", "./storage"),
+		StorageLocalBaseURL:       getEnv("STORAGE_LOCAL_BASE_URL", ""),
+		StorageLocalSigningSecret: getEnv("STORAGE_LOCAL_SIGNING_SECRET", ""),
+
+		// Endpoint del Servicio de Presigned URL (ESENCIAL con el driver s3presign)
+		// Requerido para que el driver sepa a dónde llamar para obtener la URL de subida.
 		PresignedURLServiceEndpoint: getEnv("PRESIGNED_URL_SERVICE_ENDPOINT", "http://localhost:8081/api/v1/s3/presign"),
 
 		// 4. URL del JWKS para validación de tokens JWT
@@ -54,7 +141,78 @@ func Load() *Config {
 		// 5. Orígenes permitidos para CORS (separados por coma)
 		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "*"),
 
-		// 6. Configuración de Base de Datos
+		// 6. Secretos HMAC del callback (actual + anterior para rotación)
+		CallbackSigningSecrets: []string{
+			getEnv("CALLBACK_HMAC_SECRET", ""),
+			getEnv("CALLBACK_HMAC_SECRET_PREVIOUS", ""),
+		},
+		CallbackSignatureTolerance: time.Duration(getEnvAsInt64("CALLBACK_SIGNATURE_TOLERANCE_SECONDS", 300)) * time.Second,
+
+		// Política ante un resultado nuevo para una solicitud ya completada:
+		// "reject" (409) o "new_version" (crea una versión del sistema)
+		CallbackReplayPolicy: getEnv("CALLBACK_REPLAY_POLICY", "reject"),
+
+		// 7. Reaper: cada cuánto revisar y tras cuánto tiempo sin callback se da por fallida
+		// (deben ser positivos: un intervalo de 0 haría fallar el ticker del reaper)
+		StaleRequestReaperInterval: time.Duration(getEnvAsPositiveInt64("STALE_REQUEST_REAPER_INTERVAL_SECONDS", 60)) * time.Second,
+		StaleRequestTimeout:        time.Duration(getEnvAsPositiveInt64("STALE_REQUEST_TIMEOUT_MINUTES", 30)) * time.Minute,
+
+		// 8. Stream SSE: intervalo de heartbeat (positivo, lo usa un ticker) y eventos
+		// recientes guardados para Last-Event-ID
+		SSEHeartbeatInterval: time.Duration(getEnvAsPositiveInt64("SSE_HEARTBEAT_SECONDS", 15)) * time.Second,
+		EventHistorySize:     int(getEnvAsInt64("SSE_EVENT_HISTORY_SIZE", 1000)),
+
+		// 9. Webhooks: frecuencia del worker, timeout por entrega, reintentos y desactivación
+		// (frecuencia y timeout deben ser positivos: un intervalo de 0 haría fallar el ticker
+		// y un timeout de 0 dejaría al cliente HTTP sin límite)
+		WebhookDeliveryInterval:     time.Duration(getEnvAsPositiveInt64("WEBHOOK_DELIVERY_INTERVAL_SECONDS", 5)) * time.Second,
+		WebhookTimeout:              time.Duration(getEnvAsPositiveInt64("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+		WebhookMaxAttempts:          int(getEnvAsInt64("WEBHOOK_MAX_ATTEMPTS", 8)),
+		WebhookDisableAfterFailures: int(getEnvAsInt64("WEBHOOK_DISABLE_AFTER_FAILURES", 15)),
+		// Permitir URLs http:// (solo para desarrollo)
+		WebhookAllowInsecureURLs: getEnvAsBool("WEBHOOK_ALLOW_INSECURE_URLS", false),
+
+		// 10. Cola de ingesta: directorio de archivos pendientes, tamaño del pool,
+		// frecuencia de sondeo, tiempo máximo por intento y reintentos antes del dead-letter
+		// (pool, sondeo y tiempo por intento deben ser positivos: sin workers nada se procesa,
+		// un sondeo de 0 haría fallar el ticker y un tiempo de 0 cancelaría cada intento)
+		IngestionSpoolDir:     getEnv("INGESTION_SPOOL_DIR", filepath.Join(os.TempDir(), "resume-ingestion")),
+		IngestionWorkers:      int(getEnvAsPositiveInt64("INGESTION_WORKERS", 4)),
+		IngestionPollInterval: time.Duration(getEnvAsPositiveInt64("INGESTION_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		IngestionJobTimeout:   time.Duration(getEnvAsPositiveInt64("INGESTION_JOB_TIMEOUT_SECONDS", 300)) * time.Second,
+		IngestionMaxAttempts:  int(getEnvAsInt64("INGESTION_MAX_ATTEMPTS", 5)),
+
+		// 11. Imágenes: resolución máxima en la página (las más grandes se reducen) y
+		// tamaño de página del PDF (A4, Letter o Legal)
+		ImageMaxDPI:   int(getEnvAsInt64("IMAGE_MAX_DPI", 200)),
+		ImagePageSize: getEnv("IMAGE_PAGE_SIZE", "A4"),
+
+		// 12. Outbox: frecuencia del dispatcher (positiva, la usa un ticker) y publishers
+		// adicionales (separados por coma: log, memory)
+		OutboxPollInterval: time.Duration(getEnvAsPositiveInt64("OUTBOX_POLL_INTERVAL_MS", 500)) * time.Millisecond,
+		OutboxPublishers:   getEnvAsList("OUTBOX_PUBLISHERS", "log"),
+
+		// 13. Llamadas HTTP salientes: timeout por intento (presign y subida a S3), intentos
+		// totales con backoff exponencial y jitter ante 5xx o errores de red, y circuit
+		// breaker del servicio de presigned URLs (fallas seguidas que lo abren y enfriamiento)
+		PresignTimeout:       time.Duration(getEnvAsInt64("PRESIGN_TIMEOUT_SECONDS", 10)) * time.Second,
+		UploadTimeout:        time.Duration(getEnvAsInt64("S3_UPLOAD_TIMEOUT_SECONDS", 120)) * time.Second,
+		HTTPMaxAttempts:      int(getEnvAsInt64("HTTP_MAX_ATTEMPTS", 3)),
+		HTTPRetryBaseDelay:   time.Duration(getEnvAsInt64("HTTP_RETRY_BASE_DELAY_MS", 200)) * time.Millisecond,
+		HTTPRetryMaxDelay:    time.Duration(getEnvAsInt64("HTTP_RETRY_MAX_DELAY_MS", 5000)) * time.Millisecond,
+		HTTPBreakerThreshold: int(getEnvAsInt64("HTTP_BREAKER_THRESHOLD", 5)),
+		HTTPBreakerCooldown:  time.Duration(getEnvAsInt64("HTTP_BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+
+		// 14. Lotes: archivos por lote, tamaño total recibido (los archivos o el ZIP) y
+		// descomprimido en MB, tasa de compresión máxima de los archivos del ZIP y
+		// archivos de un mismo lote que la cola de ingesta procesa a la vez (0 = sin límite)
+		BatchMaxFiles:            int(getEnvAsInt64("BATCH_MAX_FILES", 50)),
+		BatchMaxUploadSize:       getEnvAsInt64("BATCH_MAX_UPLOAD_MB", 50) * 1024 * 1024,
+		BatchMaxUncompressedSize: getEnvAsInt64("BATCH_MAX_UNCOMPRESSED_MB", 200) * 1024 * 1024,
+		BatchMaxCompressionRatio: getEnvAsInt64("BATCH_MAX_COMPRESSION_RATIO", 100),
+		BatchMaxConcurrency:      int(getEnvAsInt64("BATCH_MAX_CONCURRENCY", 2)),
+
+		// 15. Configuración de Base de Datos
 		DatabaseHost:     getEnv("DB_HOST", "localhost"),
 		DatabasePort:     getEnv("DB_PORT", "5432"),
 		DatabaseUser:     getEnv("DB_USER", "resume_user"),
@@ -63,9 +221,56 @@ func Load() *Config {
 		DatabaseSSLMode:  getEnv("DB_SSLMODE", "disable"),
 	}
 
+	// Una política desconocida se comportaría como "reject" sin avisar: se detiene el arranque
+	if !domain.CallbackReplayPolicy(cfg.CallbackReplayPolicy).IsValid() {
+		log.Fatalf("❌ CALLBACK_REPLAY_POLICY inválida: %q (valores permitidos: %s, %s)",
+			cfg.CallbackReplayPolicy, domain.ReplayPolicyReject, domain.ReplayPolicyNewVersion)
+	}
+
 	return cfg
 }
 
+// DefaultQuota retorna las cuotas que aplican a los usuarios sin cuota personalizada
+func (c *Config) DefaultQuota() domain.QuotaLimits {
+	return domain.QuotaLimits{
+		MaxRequestsPerDay: c.MaxRequestsPerDay,
+		MaxStorageBytes:   c.MaxStoragePerUser,
+	}
+}
+
+// BatchLimits retorna los límites de las subidas de lotes
+func (c *Config) BatchLimits() services.BatchLimits {
+	return services.BatchLimits{
+		MaxFiles:            c.BatchMaxFiles,
+		MaxUploadSize:       c.BatchMaxUploadSize,
+		MaxUncompressedSize: c.BatchMaxUncompressedSize,
+		MaxCompressionRatio: c.BatchMaxCompressionRatio,
+	}
+}
+
+// PresignHTTPOptions retorna la configuración del cliente del servicio de presigned URLs
+func (c *Config) PresignHTTPOptions() httpx.Options {
+	return httpx.Options{
+		Timeout:          c.PresignTimeout,
+		MaxAttempts:      c.HTTPMaxAttempts,
+		BaseDelay:        c.HTTPRetryBaseDelay,
+		MaxDelay:         c.HTTPRetryMaxDelay,
+		BreakerThreshold: c.HTTPBreakerThreshold,
+		BreakerCooldown:  c.HTTPBreakerCooldown,
+	}
+}
+
+// UploadHTTPOptions retorna la configuración del cliente de subidas a S3. No usa circuit
+// breaker: cada subida va a una URL firmada distinta y sus fallas ya las corta el presign.
+func (c *Config) UploadHTTPOptions() httpx.Options {
+	return httpx.Options{
+		Timeout:     c.UploadTimeout,
+		MaxAttempts: c.HTTPMaxAttempts,
+		BaseDelay:   c.HTTPRetryBaseDelay,
+		MaxDelay:    c.HTTPRetryMaxDelay,
+	}
+}
+
 // --- Funciones de Utilidad ---
 
 func getEnv(key, defaultValue string) string {
@@ -82,3 +287,32 @@ func getEnvAsInt64(key string, defaultValue int64) int64 {
 	}
 	return defaultValue
 }
+
+// getEnvAsPositiveInt64 es como getEnvAsInt64 pero usa el valor por defecto si el
+// configurado es 0 o negativo
+func getEnvAsPositiveInt64(key string, defaultValue int64) int64 {
+	value := getEnvAsInt64(key, defaultValue)
+	if value <= 0 {
+		log.Printf("⚠️  %s debe ser mayor que 0 (valor: %d), se usa %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
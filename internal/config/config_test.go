@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleRequestReaperSettingsFallBackWhenNotPositive(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		timeout  string
+	}{
+		{"Zero", "0", "0"},
+		{"Negative", "-5", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STALE_REQUEST_REAPER_INTERVAL_SECONDS", tt.interval)
+			t.Setenv("STALE_REQUEST_TIMEOUT_MINUTES", tt.timeout)
+
+			cfg := Load()
+			if cfg.StaleRequestReaperInterval != 60*time.Second {
+				t.Errorf("intervalo = %s, se esperaba el valor por defecto", cfg.StaleRequestReaperInterval)
+			}
+			if cfg.StaleRequestTimeout != 30*time.Minute {
+				t.Errorf("timeout = %s, se esperaba el valor por defecto", cfg.StaleRequestTimeout)
+			}
+		})
+	}
+
+	t.Setenv("STALE_REQUEST_REAPER_INTERVAL_SECONDS", "5")
+	if cfg := Load(); cfg.StaleRequestReaperInterval != 5*time.Second {
+		t.Errorf("intervalo = %s, se esperaban 5s", cfg.StaleRequestReaperInterval)
+	}
+}
+
+// Los intervalos y tiempos que usan tickers o timeouts vuelven al valor por defecto
+// si no son positivos
+func TestDurationSettingsFallBackWhenNotPositive(t *testing.T) {
+	tests := []struct {
+		key  string
+		got  func(*Config) time.Duration
+		want time.Duration
+	}{
+		{"SSE_HEARTBEAT_SECONDS", func(c *Config) time.Duration { return c.SSEHeartbeatInterval }, 15 * time.Second},
+		{"INGESTION_WORKERS", func(c *Config) time.Duration { return time.Duration(c.IngestionWorkers) }, 4},
+		{"INGESTION_POLL_INTERVAL_MS", func(c *Config) time.Duration { return c.IngestionPollInterval }, time.Second},
+		{"INGESTION_JOB_TIMEOUT_SECONDS", func(c *Config) time.Duration { return c.IngestionJobTimeout }, 5 * time.Minute},
+		{"OUTBOX_POLL_INTERVAL_MS", func(c *Config) time.Duration { return c.OutboxPollInterval }, 500 * time.Millisecond},
+		{"WEBHOOK_DELIVERY_INTERVAL_SECONDS", func(c *Config) time.Duration { return c.WebhookDeliveryInterval }, 5 * time.Second},
+		{"WEBHOOK_TIMEOUT_SECONDS", func(c *Config) time.Duration { return c.WebhookTimeout }, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for _, value := range []string{"0", "-1"} {
+			t.Run(tt.key+"="+value, func(t *testing.T) {
+				t.Setenv(tt.key, value)
+				if got := tt.got(Load()); got != tt.want {
+					t.Errorf("%s = %s, se esperaba %s", tt.key, got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/handlers"
+	"resume-backend-service/internal/middleware"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/internal/services"
+	"runtime"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// letters genera contenido de texto sin reservarlo en memoria
+type letters struct{}
+
+func (letters) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+// multipartStream arma un formulario con un archivo de size bytes que se genera a medida
+// que se lee, y retorna el body junto con su largo total
+func multipartStream(t *testing.T, filename string, size int64) (io.Reader, int64, string) {
+	t.Helper()
+
+	var head bytes.Buffer
+	writer := multipart.NewWriter(&head)
+	writer.WriteField("language", "esp")
+	if _, err := writer.CreateFormFile("file", filename); err != nil {
+		t.Fatal(err)
+	}
+	prefix := append([]byte(nil), head.Bytes()...)
+	head.Reset()
+	writer.Close()
+	suffix := head.Bytes()
+
+	body := io.MultiReader(bytes.NewReader(prefix), io.LimitReader(letters{}, size), bytes.NewReader(suffix))
+	return body, int64(len(prefix)) + size + int64(len(suffix)), writer.FormDataContentType()
+}
+
+// La subida recorre el handler real: parseo del multipart, validación y copia al spool.
+// La base de datos no está disponible, así que la solicitud falla recién al guardarse.
+func TestLargeUploadThroughHandlerUsesBoundedMemory(t *testing.T) {
+	const size = 64 * 1024 * 1024
+
+	cfg := &Config{MaxFileSize: 2 * size, BatchMaxUploadSize: 2 * size}
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	quotaService := services.NewQuotaService(repository.NewQuotaRepository(db), domain.QuotaLimits{}, cfg.MaxFileSize, 0)
+	resumeService := services.NewResumeService(nil, repository.NewUnitOfWork(db), repository.NewResumeRequestRepository(db), nil, nil, nil, quotaService, t.TempDir())
+
+	fiberConfig := newFiberConfig()
+	fiberConfig.DisableStartupMessage = true
+	app := fiber.New(fiberConfig)
+	app.Use(middleware.BodyLimit(2*cfg.MaxFileSize, services.FileTooLargeMessage(0, cfg.MaxFileSize)))
+	app.Post("/resume", func(c *fiber.Ctx) error {
+		c.Locals("user_subject", "user-1")
+		return c.Next()
+	}, handlers.NewResumeHandler(resumeService).ProcessResumeHandler)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(listener)
+	defer app.Shutdown()
+
+	body, length, contentType := multipartStream(t, "cv.txt", size)
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/resume", listener.Addr()), body)
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	runtime.ReadMemStats(&after)
+
+	if resp.StatusCode != fiber.StatusInternalServerError {
+		t.Fatalf("status = %d, se esperaba 500 al guardar la solicitud", resp.StatusCode)
+	}
+	// TotalAlloc acota el pico: todo lo reservado durante la solicitud
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > size/8 {
+		t.Errorf("se reservaron %d MB para una subida de %d MB", allocated>>20, size>>20)
+	}
+}
+
+func TestBodyLimitRejectsBeforeReadingBody(t *testing.T) {
+	app := fiber.New(newFiberConfig())
+	app.Use(middleware.BodyLimit(1024, services.FileTooLargeMessage(0, 1024)))
+	app.Post("/resume", func(c *fiber.Ctx) error {
+		t.Error("el handler no debe ejecutarse")
+		return nil
+	})
+
+	body, length, contentType := multipartStream(t, "cv.txt", 4096)
+	req, _ := http.NewRequest(http.MethodPost, "/resume", body)
+	req.ContentLength = length
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, se esperaba 413", resp.StatusCode)
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CallbackOutcome representa el resultado de procesar una entrega del callback
+type CallbackOutcome string
+
+const (
+	CallbackOutcomeApplied  CallbackOutcome = "applied"  // El resultado se guardó
+	CallbackOutcomeRejected CallbackOutcome = "rejected" // Rechazado por la política de replays
+	CallbackOutcomeIgnored  CallbackOutcome = "ignored"  // Recibido pero sin efectos
+	CallbackOutcomeError    CallbackOutcome = "error"    // Falló al procesar (se permite reintentar)
+)
+
+// CallbackReplayPolicy define qué hacer con un resultado nuevo para una solicitud ya completada
+type CallbackReplayPolicy string
+
+const (
+	ReplayPolicyReject     CallbackReplayPolicy = "reject"
+	ReplayPolicyNewVersion CallbackReplayPolicy = "new_version"
+)
+
+// IsValid indica si la política es una de las soportadas
+func (p CallbackReplayPolicy) IsValid() bool {
+	return p == ReplayPolicyReject || p == ReplayPolicyNewVersion
+}
+
+// CallbackDelivery representa las entregas de un mismo resultado de AWS Lambda
+type CallbackDelivery struct {
+	ID              int64            `json:"id" db:"id"`
+	RequestID       uuid.UUID        `json:"request_id" db:"request_id"`
+	PayloadHash     string           `json:"payload_hash" db:"payload_hash"`
+	LambdaStatus    string           `json:"lambda_status" db:"lambda_status"`
+	DeliveryCount   int              `json:"delivery_count" db:"delivery_count"`
+	Outcome         *CallbackOutcome `json:"outcome,omitempty" db:"outcome"`
+	FirstReceivedAt time.Time        `json:"first_received_at" db:"first_received_at"`
+	LastReceivedAt  time.Time        `json:"last_received_at" db:"last_received_at"`
+}
+
+// IsDuplicate indica si esta entrega repite un resultado que ya fue resuelto.
+// Las entregas que terminaron en error no cuentan, para que un reintento pueda aplicarlas.
+func (d *CallbackDelivery) IsDuplicate() bool {
+	return d.DeliveryCount > 1 && d.Outcome != nil && *d.Outcome != CallbackOutcomeError
+}
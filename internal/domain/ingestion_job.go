@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IngestionJobStatus representa el estado de un trabajo de ingesta
+type IngestionJobStatus string
+
+const (
+	IngestionJobQueued    IngestionJobStatus = "queued"
+	IngestionJobRunning   IngestionJobStatus = "running"
+	IngestionJobSucceeded IngestionJobStatus = "succeeded"
+	IngestionJobDead      IngestionJobStatus = "dead" // Se agotaron los reintentos o el error no es reintentable
+)
+
+// IngestionJob representa la conversión y subida a S3 pendiente de una solicitud
+type IngestionJob struct {
+	ID            int64              `json:"id" db:"id"`
+	RequestID     uuid.UUID          `json:"request_id" db:"request_id"`
+	BatchID       *uuid.UUID         `json:"batch_id,omitempty" db:"batch_id"` // Lote de la solicitud (ver ClaimNext)
+	SpoolPath     string             `json:"-" db:"spool_path"`
+	Status        IngestionJobStatus `json:"status" db:"status"`
+	Attempts      int                `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     string             `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	FinishedAt    *time.Time         `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// NewIngestionJob crea un trabajo encolado para ejecutarse de inmediato
+func NewIngestionJob(requestID uuid.UUID, spoolPath string) *IngestionJob {
+	now := time.Now()
+	return &IngestionJob{
+		RequestID:     requestID,
+		SpoolPath:     spoolPath,
+		Status:        IngestionJobQueued,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
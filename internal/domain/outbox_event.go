@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de evento de dominio escritos en el outbox. Los eventos de solicitudes
+// se nombran "resume.<estado>" (ver ResumeStatusEventType).
+const (
+	EventResumePending    = "resume.pending"
+	EventResumeUploaded   = "resume.uploaded"
+	EventResumeProcessing = "resume.processing"
+	EventResumeCompleted  = "resume.completed"
+	EventResumeFailed     = "resume.failed"
+	EventVersionCreated   = "version.created"
+	EventVersionActivated = "version.activated"
+	EventVersionDeleted   = "version.deleted"
+)
+
+// ResumeStatusEventType retorna el tipo de evento que se emite al pasar a status
+func ResumeStatusEventType(status ResumeRequestStatus) string {
+	return "resume." + string(status)
+}
+
+// OutboxEvent representa un evento de dominio pendiente o ya publicado
+type OutboxEvent struct {
+	ID          int64           `json:"-" db:"id"`
+	EventID     uuid.UUID       `json:"id" db:"event_id"`
+	RequestID   uuid.UUID       `json:"request_id" db:"request_id"`
+	UserID      string          `json:"user_id" db:"user_id"`
+	EventType   string          `json:"type" db:"event_type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Attempts    int             `json:"-" db:"attempts"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	PublishedAt *time.Time      `json:"-" db:"published_at"`
+}
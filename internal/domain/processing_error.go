@@ -0,0 +1,66 @@
+package domain
+
+import "unicode/utf8"
+
+// Códigos de error de procesamiento generados por el backend.
+// Los códigos reportados por AWS Lambda se guardan tal como llegan (recortados a MaxErrorFieldLength).
+const (
+	ErrorCodeConversionFailed = "CONVERSION_FAILED"
+	ErrorCodePresignFailed    = "PRESIGN_FAILED"
+	ErrorCodeUploadFailed     = "UPLOAD_FAILED"
+	ErrorCodeInvalidResult    = "INVALID_RESULT"
+	ErrorCodePersistFailed    = "PERSIST_FAILED"
+	ErrorCodeLambdaFailed     = "LAMBDA_FAILED"
+	ErrorCodeTimeout          = "PROCESSING_TIMEOUT"
+)
+
+// Etapas del pipeline en las que puede fallar una solicitud
+const (
+	StageConversion  = "conversion"
+	StageUpload      = "upload"
+	StageExtraction  = "extraction"
+	StagePersistence = "persistence"
+)
+
+// MaxErrorFieldLength es el ancho de las columnas error_code y error_stage (migración 004)
+const MaxErrorFieldLength = 50
+
+// ProcessingError describe por qué falló una solicitud de procesamiento
+type ProcessingError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Stage     string `json:"stage,omitempty"`
+}
+
+// NewProcessingError crea un error de procesamiento
+func NewProcessingError(code, message, stage string, retryable bool) ProcessingError {
+	return ProcessingError{
+		Code:      code,
+		Message:   message,
+		Retryable: retryable,
+		Stage:     stage,
+	}
+}
+
+// Error permite retornar el ProcessingError como error
+func (e ProcessingError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Truncated retorna una copia con Code y Stage recortados al ancho de sus columnas, para
+// que un valor largo reportado por Lambda no haga fallar el UPDATE de la solicitud
+func (e ProcessingError) Truncated() ProcessingError {
+	e.Code = truncateRunes(e.Code, MaxErrorFieldLength)
+	e.Stage = truncateRunes(e.Stage, MaxErrorFieldLength)
+	return e
+}
+
+// truncateRunes recorta s a max caracteres sin partir un carácter UTF-8
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max])
+}
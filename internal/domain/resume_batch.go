@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchSource indica cómo se recibieron los archivos de un lote
+type BatchSource string
+
+const (
+	BatchSourceFiles BatchSource = "files" // Varias partes 'file' del formulario
+	BatchSourceZIP   BatchSource = "zip"   // Un archivo ZIP
+)
+
+// BatchRejection es un archivo del lote que no se aceptó (no tiene solicitud)
+type BatchRejection struct {
+	Filename string `json:"filename"`
+	Message  string `json:"message"`
+}
+
+// ResumeBatch agrupa las solicitudes creadas a partir de una misma subida de varios CVs
+type ResumeBatch struct {
+	BatchID      uuid.UUID        `json:"batch_id" db:"batch_id"`
+	UserID       string           `json:"user_id" db:"user_id"`
+	Source       BatchSource      `json:"source" db:"source"`
+	TotalFiles   int              `json:"total_files" db:"total_files"`
+	Rejected     []BatchRejection `json:"rejected" db:"rejected"`
+	Language     string           `json:"language" db:"language"`
+	Instructions string           `json:"instructions" db:"instructions"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+}
+
+// NewResumeBatch crea un lote de totalFiles archivos recibidos
+func NewResumeBatch(userID string, source BatchSource, totalFiles int, language, instructions string) *ResumeBatch {
+	return &ResumeBatch{
+		BatchID:      uuid.New(),
+		UserID:       userID,
+		Source:       source,
+		TotalFiles:   totalFiles,
+		Rejected:     []BatchRejection{},
+		Language:     language,
+		Instructions: instructions,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// Reject registra un archivo rechazado del lote
+func (b *ResumeBatch) Reject(filename, message string) {
+	b.Rejected = append(b.Rejected, BatchRejection{Filename: filename, Message: message})
+}
+
+// BatchProgress es el avance de las solicitudes de un lote
+type BatchProgress struct {
+	// Solicitudes del lote por estado
+	StatusCounts map[ResumeRequestStatus]int
+}
+
+// Accepted retorna la cantidad de solicitudes del lote
+func (p BatchProgress) Accepted() int {
+	total := 0
+	for _, count := range p.StatusCounts {
+		total += count
+	}
+	return total
+}
+
+// Finished retorna la cantidad de solicitudes en un estado final (completed o failed)
+func (p BatchProgress) Finished() int {
+	finished := 0
+	for status, count := range p.StatusCounts {
+		if status.IsFinal() {
+			finished += count
+		}
+	}
+	return finished
+}
+
+// Percent retorna el porcentaje de solicitudes terminadas (100 si no hay ninguna)
+func (p BatchProgress) Percent() int {
+	accepted := p.Accepted()
+	if accepted == 0 {
+		return 100
+	}
+	return p.Finished() * 100 / accepted
+}
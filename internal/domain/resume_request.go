@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,12 +19,75 @@ const (
 	StatusFailed     ResumeRequestStatus = "failed"
 )
 
+// allowedTransitions define el ciclo de vida de una solicitud. completed y failed son
+// estados finales. pending → processing cubre el caso en que el procesador recibió el
+// archivo pero no se alcanzó a registrar la subida.
+var allowedTransitions = map[ResumeRequestStatus][]ResumeRequestStatus{
+	StatusPending:    {StatusUploaded, StatusProcessing, StatusFailed},
+	StatusUploaded:   {StatusProcessing, StatusFailed},
+	StatusProcessing: {StatusCompleted, StatusFailed},
+	StatusCompleted:  {},
+	StatusFailed:     {},
+}
+
+// CanTransitionTo indica si se permite pasar del estado actual a next
+func (s ResumeRequestStatus) CanTransitionTo(next ResumeRequestStatus) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal indica si el estado ya no admite transiciones
+func (s ResumeRequestStatus) IsFinal() bool {
+	return len(allowedTransitions[s]) == 0
+}
+
+// SourceStatuses retorna los estados desde los que se puede llegar a target
+func SourceStatuses(target ResumeRequestStatus) []ResumeRequestStatus {
+	var sources []ResumeRequestStatus
+	for _, from := range []ResumeRequestStatus{StatusPending, StatusUploaded, StatusProcessing, StatusCompleted, StatusFailed} {
+		if from.CanTransitionTo(target) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+// ErrInvalidTransition se usa con errors.Is para detectar cualquier StatusTransitionError
+var ErrInvalidTransition = errors.New("transición de estado inválida")
+
+// StatusTransitionError indica que la solicitud no estaba en un estado que permita la transición
+type StatusTransitionError struct {
+	RequestID uuid.UUID
+	From      ResumeRequestStatus
+	To        ResumeRequestStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("transición de estado inválida para solicitud %s: %s → %s", e.RequestID, e.From, e.To)
+}
+
+func (e *StatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
 // ResumeRequest representa una solicitud de procesamiento de CV
 type ResumeRequest struct {
 	RequestID        uuid.UUID           `json:"request_id" db:"request_id"`
 	UserID           string              `json:"user_id" db:"user_id"`
 	OriginalFilename string              `json:"original_filename" db:"original_filename"`
 	OriginalFileType string              `json:"original_file_type" db:"original_file_type"`
+	DetectedMIMEType string              `json:"detected_mime_type,omitempty" db:"detected_mime_type"`
+	TextEncoding     string              `json:"text_encoding,omitempty" db:"text_encoding"`
+	PageCount        int                 `json:"page_count,omitempty" db:"page_count"`
+	PDFProducer      string              `json:"pdf_producer,omitempty" db:"pdf_producer"`
+	ContentSHA256    string              `json:"content_sha256,omitempty" db:"content_sha256"`
+	ForceReprocess   bool                `json:"force_reprocess,omitempty" db:"force_reprocess"`
+	ClonedFrom       *uuid.UUID          `json:"cloned_from_request_id,omitempty" db:"cloned_from_request_id"`
+	BatchID          *uuid.UUID          `json:"batch_id,omitempty" db:"batch_id"`
 	FileSizeBytes    int64               `json:"file_size_bytes" db:"file_size_bytes"`
 	Language         string              `json:"language" db:"language"`
 	Instructions     string              `json:"instructions" db:"instructions"`
@@ -31,6 +96,9 @@ type ResumeRequest struct {
 	Status           ResumeRequestStatus `json:"status" db:"status"`
 	ProcessingTimeMs int64               `json:"processing_time_ms,omitempty" db:"processing_time_ms"`
 	ErrorMessage     string              `json:"error_message,omitempty" db:"error_message"`
+	ErrorCode        string              `json:"error_code,omitempty" db:"error_code"`
+	ErrorStage       string              `json:"error_stage,omitempty" db:"error_stage"`
+	ErrorRetryable   bool                `json:"error_retryable,omitempty" db:"error_retryable"`
 	CreatedAt        time.Time           `json:"created_at" db:"created_at"`
 	UploadedAt       *time.Time          `json:"uploaded_at,omitempty" db:"uploaded_at"`
 	CompletedAt      *time.Time          `json:"completed_at,omitempty" db:"completed_at"`
@@ -51,32 +119,66 @@ func NewResumeRequest(userID, filename, fileType string, fileSize int64, languag
 	}
 }
 
+// transitionTo valida y aplica el cambio de estado
+func (r *ResumeRequest) transitionTo(next ResumeRequestStatus) error {
+	if !r.Status.CanTransitionTo(next) {
+		return &StatusTransitionError{RequestID: r.RequestID, From: r.Status, To: next}
+	}
+	r.Status = next
+	return nil
+}
+
 // MarkAsUploaded marca la solicitud como subida a S3
-func (r *ResumeRequest) MarkAsUploaded(s3InputURL string) {
-	r.Status = StatusUploaded
+func (r *ResumeRequest) MarkAsUploaded(s3InputURL string) error {
+	if err := r.transitionTo(StatusUploaded); err != nil {
+		return err
+	}
 	r.S3InputURL = s3InputURL
 	now := time.Now()
 	r.UploadedAt = &now
+	return nil
 }
 
 // MarkAsProcessing marca la solicitud como en procesamiento
-func (r *ResumeRequest) MarkAsProcessing() {
-	r.Status = StatusProcessing
+func (r *ResumeRequest) MarkAsProcessing() error {
+	return r.transitionTo(StatusProcessing)
 }
 
 // MarkAsCompleted marca la solicitud como completada
-func (r *ResumeRequest) MarkAsCompleted(s3OutputURL string, processingTimeMs int64) {
-	r.Status = StatusCompleted
+func (r *ResumeRequest) MarkAsCompleted(s3OutputURL string, processingTimeMs int64) error {
+	if err := r.transitionTo(StatusCompleted); err != nil {
+		return err
+	}
 	r.S3OutputURL = s3OutputURL
 	r.ProcessingTimeMs = processingTimeMs
 	now := time.Now()
 	r.CompletedAt = &now
+	return nil
 }
 
 // MarkAsFailed marca la solicitud como fallida
-func (r *ResumeRequest) MarkAsFailed(errorMsg string) {
-	r.Status = StatusFailed
-	r.ErrorMessage = errorMsg
+func (r *ResumeRequest) MarkAsFailed(processingErr ProcessingError) error {
+	if err := r.transitionTo(StatusFailed); err != nil {
+		return err
+	}
+	r.ErrorMessage = processingErr.Message
+	r.ErrorCode = processingErr.Code
+	r.ErrorStage = processingErr.Stage
+	r.ErrorRetryable = processingErr.Retryable
 	now := time.Now()
 	r.CompletedAt = &now
+	return nil
+}
+
+// ProcessingError retorna el detalle del fallo, o nil si la solicitud no falló
+func (r *ResumeRequest) ProcessingError() *ProcessingError {
+	if r.Status != StatusFailed {
+		return nil
+	}
+	return &ProcessingError{
+		Code:      r.ErrorCode,
+		Message:   r.ErrorMessage,
+		Retryable: r.ErrorRetryable,
+		Stage:     r.ErrorStage,
+	}
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventActor identifica quién provocó un cambio de estado
+type EventActor string
+
+const (
+	ActorUser     EventActor = "user"     // Acción directa del usuario (ej: subir un CV)
+	ActorSystem   EventActor = "system"   // Pipeline interno del backend (conversión, subida)
+	ActorCallback EventActor = "callback" // Callback de AWS Lambda
+	ActorReaper   EventActor = "reaper"   // Worker que vence solicitudes sin resultado
+)
+
+// ResumeRequestEvent representa una transición de estado en el historial de una solicitud
+type ResumeRequestEvent struct {
+	ID         int64                `json:"id" db:"id"`
+	RequestID  uuid.UUID            `json:"request_id" db:"request_id"`
+	FromStatus *ResumeRequestStatus `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   ResumeRequestStatus  `json:"to_status" db:"to_status"`
+	Actor      EventActor           `json:"actor" db:"actor"`
+	Detail     json.RawMessage      `json:"detail,omitempty" db:"detail"`
+	CreatedAt  time.Time            `json:"created_at" db:"created_at"`
+}
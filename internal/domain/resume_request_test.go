@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+var allStatuses = []ResumeRequestStatus{StatusPending, StatusUploaded, StatusProcessing, StatusCompleted, StatusFailed}
+
+func TestCanTransitionTo(t *testing.T) {
+	allowed := map[[2]ResumeRequestStatus]bool{
+		{StatusPending, StatusUploaded}:     true,
+		{StatusPending, StatusProcessing}:   true,
+		{StatusPending, StatusFailed}:       true,
+		{StatusUploaded, StatusProcessing}:  true,
+		{StatusUploaded, StatusFailed}:      true,
+		{StatusProcessing, StatusCompleted}: true,
+		{StatusProcessing, StatusFailed}:    true,
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			expected := allowed[[2]ResumeRequestStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != expected {
+				t.Errorf("%s → %s: CanTransitionTo = %v, expected %v", from, to, got, expected)
+			}
+		}
+	}
+
+	for _, status := range []ResumeRequestStatus{StatusCompleted, StatusFailed} {
+		if !status.IsFinal() {
+			t.Errorf("%s debe ser un estado final", status)
+		}
+	}
+}
+
+func TestTransitionToRejectsForbiddenTransition(t *testing.T) {
+	request := &ResumeRequest{Status: StatusCompleted}
+
+	err := request.transitionTo(StatusPending)
+	if err == nil {
+		t.Fatal("completed → pending debe rechazarse")
+	}
+	if request.Status != StatusCompleted {
+		t.Errorf("el estado no debe cambiar: %s", request.Status)
+	}
+}
+
+// La migración 005 debe permitir exactamente las mismas transiciones que allowedTransitions
+func TestAllowedTransitionsMatchMigration(t *testing.T) {
+	migration, err := os.ReadFile("../../migrations/005_enforce_request_status_transitions.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := regexp.MustCompile(`\('(\w+)', '(\w+)'\)`).FindAllStringSubmatch(string(migration), -1)
+	inMigration := map[[2]ResumeRequestStatus]bool{}
+	for _, row := range rows {
+		from, to := ResumeRequestStatus(row[1]), ResumeRequestStatus(row[2])
+		inMigration[[2]ResumeRequestStatus{from, to}] = true
+		if !from.CanTransitionTo(to) {
+			t.Errorf("la migración permite %s → %s pero allowedTransitions no", from, to)
+		}
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allowedTransitions[from] {
+			if !inMigration[[2]ResumeRequestStatus{from, to}] {
+				t.Errorf("allowedTransitions permite %s → %s pero la migración no", from, to)
+			}
+		}
+	}
+}
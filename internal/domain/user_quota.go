@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// QuotaLimits son los límites de uso de un usuario. Un valor 0 significa sin límite.
+type QuotaLimits struct {
+	MaxRequestsPerDay int
+	MaxStorageBytes   int64
+}
+
+// QuotaUsage es el consumo actual de un usuario
+type QuotaUsage struct {
+	RequestsToday int
+	StoredBytes   int64
+}
+
+// QuotaDayStart retorna el inicio del día (UTC) en que se cuentan las solicitudes
+func QuotaDayStart(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour)
+}
+
+// CanCreateRequest indica si el usuario puede crear otra solicitud hoy
+func (l QuotaLimits) CanCreateRequest(usage QuotaUsage) bool {
+	return l.MaxRequestsPerDay == 0 || usage.RequestsToday < l.MaxRequestsPerDay
+}
+
+// CanStore indica si el usuario puede guardar un archivo de size bytes más
+func (l QuotaLimits) CanStore(usage QuotaUsage, size int64) bool {
+	return l.MaxStorageBytes == 0 || usage.StoredBytes+size <= l.MaxStorageBytes
+}
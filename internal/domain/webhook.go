@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de evento que se pueden recibir por webhook
+const (
+	WebhookEventResumeCompleted  = "resume.completed"
+	WebhookEventResumeFailed     = "resume.failed"
+	WebhookEventVersionCreated   = "version.created"
+	WebhookEventVersionActivated = "version.activated"
+)
+
+// WebhookEventTypes lista los eventos soportados
+var WebhookEventTypes = []string{
+	WebhookEventResumeCompleted,
+	WebhookEventResumeFailed,
+	WebhookEventVersionCreated,
+	WebhookEventVersionActivated,
+}
+
+// IsValidWebhookEventType indica si eventType es un evento soportado
+func IsValidWebhookEventType(eventType string) bool {
+	for _, t := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription representa una URL registrada por un usuario para recibir eventos
+type WebhookSubscription struct {
+	ID                  uuid.UUID  `json:"id" db:"id"`
+	UserID              string     `json:"user_id" db:"user_id"`
+	URL                 string     `json:"url" db:"url"`
+	EventTypes          []string   `json:"event_types" db:"event_types"`
+	Secret              string     `json:"-" db:"secret"`
+	Active              bool       `json:"active" db:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty" db:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewWebhookSubscription crea una suscripción activa
+func NewWebhookSubscription(userID, url string, eventTypes []string, secret string) *WebhookSubscription {
+	now := time.Now()
+	return &WebhookSubscription{
+		ID:         uuid.New(),
+		UserID:     userID,
+		URL:        url,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// WebhookDeliveryStatus representa el estado de una entrega
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // Se agotaron los reintentos
+)
+
+// WebhookDelivery representa el envío de un evento a una suscripción
+type WebhookDelivery struct {
+	ID                 int64                 `json:"id" db:"id"`
+	SubscriptionID     uuid.UUID             `json:"subscription_id" db:"subscription_id"`
+	EventID            uuid.UUID             `json:"event_id" db:"event_id"`
+	EventType          string                `json:"event_type" db:"event_type"`
+	Payload            json.RawMessage       `json:"payload" db:"payload"`
+	Status             WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts           int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt      time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	LastResponseStatus *int                  `json:"last_response_status,omitempty" db:"last_response_status"`
+	LastError          string                `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt          time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt        *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+
+	// Destino de la entrega; se completa al reclamarla para enviarla
+	URL    string `json:"-" db:"-"`
+	Secret string `json:"-" db:"-"`
+}
+
+// WebhookEvent es el cuerpo JSON que se envía a las suscripciones
+type WebhookEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -1,5 +1,21 @@
 package dto
 
+// Valores de status que puede enviar AWS Lambda en el callback
+const (
+	LambdaStatusSuccess    = "success"    // Resultado final con structured_data
+	LambdaStatusError      = "error"      // Fallo, con detalle opcional en "error"
+	LambdaStatusProcessing = "processing" // Acuse de recibo: el archivo está en proceso
+)
+
+// IsValidLambdaStatus indica si status es uno de los valores que envía AWS Lambda
+func IsValidLambdaStatus(status string) bool {
+	switch status {
+	case LambdaStatusSuccess, LambdaStatusError, LambdaStatusProcessing:
+		return true
+	}
+	return false
+}
+
 // AWSLambdaResponse es la estructura completa que envía AWS Lambda
 type AWSLambdaResponse struct {
 	RequestID          string          `json:"request_id"`          // UUID de tracking (viene de metadata)
@@ -8,6 +24,15 @@ type AWSLambdaResponse struct {
 	ProcessingTimeMs   int64           `json:"processing_time_ms"`
 	Status             string          `json:"status"`
 	StructuredData     CVProcessedData `json:"structured_data"`
+	Error              *AWSLambdaError `json:"error,omitempty"`        // Solo cuando status != success
+}
+
+// AWSLambdaError es el detalle estructurado de un fallo reportado por AWS Lambda
+type AWSLambdaError struct {
+	Code      string `json:"code"`      // Código estable, ej: CORRUPT_PDF, LLM_TIMEOUT
+	Message   string `json:"message"`   // Mensaje legible para el usuario
+	Retryable bool   `json:"retryable"` // true si el fallo es transitorio
+	Stage     string `json:"stage"`     // Etapa que falló, ej: download, extraction
 }
 
 // CVProcessedData es la estructura principal que contiene los datos extraídos y procesados del CV.
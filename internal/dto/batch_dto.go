@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// BatchResponseDTO es la respuesta de POST /resume/batch
+type BatchResponseDTO struct {
+	Status     string              `json:"status"`
+	Message    string              `json:"message"`
+	BatchID    string              `json:"batch_id"`
+	TotalFiles int                 `json:"total_files"`
+	Accepted   []BatchAcceptedFile `json:"accepted"`
+	Rejected   []BatchRejectedFile `json:"rejected"`
+}
+
+// BatchAcceptedFile es un archivo del lote con su solicitud
+type BatchAcceptedFile struct {
+	RequestID string `json:"request_id"`
+	Filename  string `json:"filename"`
+}
+
+// BatchRejectedFile es un archivo del lote que no se aceptó
+type BatchRejectedFile struct {
+	Filename string `json:"filename"`
+	Message  string `json:"message"`
+}
+
+// BatchStatusDTO es el progreso de un lote (GET /resume/batch/:batch_id)
+type BatchStatusDTO struct {
+	Status        string              `json:"status"`
+	BatchID       string              `json:"batch_id"`
+	Source        string              `json:"source"`
+	TotalFiles    int                 `json:"total_files"`
+	AcceptedFiles int                 `json:"accepted_files"`
+	RejectedFiles int                 `json:"rejected_files"`
+	StatusCounts  map[string]int      `json:"status_counts"`
+	Progress      int                 `json:"progress_percent"` // Solicitudes terminadas (completed o failed)
+	Finished      bool                `json:"finished"`
+	CreatedAt     time.Time           `json:"created_at"`
+	Items         []BatchItemDTO      `json:"items"`
+	Rejected      []BatchRejectedFile `json:"rejected"`
+}
+
+// BatchItemDTO es el estado de una solicitud del lote
+type BatchItemDTO struct {
+	RequestID    string     `json:"request_id"`
+	Filename     string     `json:"filename"`
+	Status       string     `json:"status"`
+	ErrorCode    string     `json:"error_code,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// QuotaResponse representa los límites y el consumo del usuario
+type QuotaResponse struct {
+	Status           string       `json:"status"`
+	MaxFileSizeBytes int64        `json:"max_file_size_bytes"`
+	MaxPDFPages      int          `json:"max_pdf_pages,omitempty"` // Se omite si no hay límite
+	RequestsPerDay   QuotaCounter `json:"requests_per_day"`
+	StorageBytes     QuotaCounter `json:"storage_bytes"`
+}
+
+// QuotaCounter describe un límite y su consumo. Limit y Remaining se omiten si no hay límite.
+type QuotaCounter struct {
+	Limit     *int64     `json:"limit,omitempty"`
+	Used      int64      `json:"used"`
+	Remaining *int64     `json:"remaining,omitempty"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+}
@@ -10,6 +10,13 @@ type ResumeDetailDTO struct {
 	RequestID        string    `json:"request_id"`
 	OriginalFilename string    `json:"original_filename"`
 	OriginalFileType string    `json:"original_file_type"`
+	DetectedMIMEType string    `json:"detected_mime_type,omitempty"`
+	TextEncoding     string    `json:"text_encoding,omitempty"`
+	PageCount        int       `json:"page_count,omitempty"`
+	PDFProducer      string    `json:"pdf_producer,omitempty"`
+	ContentSHA256    string    `json:"content_sha256,omitempty"`
+	ClonedFrom       string    `json:"cloned_from_request_id,omitempty"`
+	BatchID          string    `json:"batch_id,omitempty"`
 	FileSizeBytes    int64     `json:"file_size_bytes"`
 	Language         string    `json:"language"`
 	Instructions     string    `json:"instructions,omitempty"`
@@ -18,6 +25,7 @@ type ResumeDetailDTO struct {
 	S3OutputURL      string    `json:"s3_output_url,omitempty"`
 	ProcessingTimeMs int64     `json:"processing_time_ms,omitempty"`
 	ErrorMessage     string    `json:"error_message,omitempty"`
+	Error            *ProcessingErrorDTO `json:"error,omitempty"`
 	CreatedAt        time.Time `json:"created_at"`
 	UploadedAt       *time.Time `json:"uploaded_at,omitempty"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
@@ -25,3 +33,11 @@ type ResumeDetailDTO struct {
 	// Datos del CV procesado (si existe)
 	StructuredData *CVProcessedData `json:"structured_data,omitempty"`
 }
+
+// ProcessingErrorDTO describe por qué falló el procesamiento de un CV
+type ProcessingErrorDTO struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+	Stage     string `json:"stage,omitempty"`
+}
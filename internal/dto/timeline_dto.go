@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TimelineResponse representa el historial de estados de una solicitud
+type TimelineResponse struct {
+	Status    string          `json:"status"`
+	RequestID string          `json:"request_id"`
+	Total     int             `json:"total"`
+	Events    []TimelineEvent `json:"events"`
+}
+
+// TimelineEvent representa una transición de estado
+type TimelineEvent struct {
+	ID         int64           `json:"id"`
+	FromStatus string          `json:"from_status,omitempty"`
+	ToStatus   string          `json:"to_status"`
+	Actor      string          `json:"actor"`
+	Detail     json.RawMessage `json:"detail,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateWebhookRequest representa los datos para registrar un webhook
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+// WebhookSubscriptionItem representa una suscripción (sin el secreto)
+type WebhookSubscriptionItem struct {
+	ID                  string     `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// CreateWebhookResponse representa la respuesta al registrar un webhook.
+// El secreto solo se muestra en esta respuesta.
+type CreateWebhookResponse struct {
+	Status       string                  `json:"status"`
+	Message      string                  `json:"message"`
+	Subscription WebhookSubscriptionItem `json:"subscription"`
+	Secret       string                  `json:"secret"`
+}
+
+// WebhookListResponse representa el listado de webhooks del usuario
+type WebhookListResponse struct {
+	Status        string                    `json:"status"`
+	Total         int                       `json:"total"`
+	Subscriptions []WebhookSubscriptionItem `json:"subscriptions"`
+}
+
+// WebhookDeliveryItem representa una entrega del registro de un webhook
+type WebhookDeliveryItem struct {
+	ID                 int64           `json:"id"`
+	EventID            string          `json:"event_id"`
+	EventType          string          `json:"event_type"`
+	Status             string          `json:"status"`
+	Attempts           int             `json:"attempts"`
+	NextAttemptAt      *time.Time      `json:"next_attempt_at,omitempty"`
+	LastResponseStatus *int            `json:"last_response_status,omitempty"`
+	LastError          string          `json:"last_error,omitempty"`
+	Payload            json.RawMessage `json:"payload"`
+	CreatedAt          time.Time       `json:"created_at"`
+	DeliveredAt        *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryListResponse representa el registro de entregas de un webhook
+type WebhookDeliveryListResponse struct {
+	Status     string                `json:"status"`
+	Total      int                   `json:"total"`
+	Deliveries []WebhookDeliveryItem `json:"deliveries"`
+}
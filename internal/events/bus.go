@@ -0,0 +1,181 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"resume-backend-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// subscriberBufferSize es la cantidad de eventos pendientes que tolera un suscriptor
+// lento antes de ser desconectado
+const subscriberBufferSize = 64
+
+// StatusEvent representa un cambio de estado de una solicitud de procesamiento
+type StatusEvent struct {
+	ID         uint64                     `json:"id"`
+	RequestID  uuid.UUID                  `json:"request_id"`
+	UserID     string                     `json:"-"`
+	Status     domain.ResumeRequestStatus `json:"status"`
+	Error      *domain.ProcessingError    `json:"error,omitempty"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// Subscription recibe los eventos de un usuario. El canal se cierra al cancelar la
+// suscripción, al cerrar el bus o si el suscriptor no consume a tiempo.
+type Subscription struct {
+	C      <-chan StatusEvent
+	ch     chan StatusEvent
+	userID string
+	bus    *Bus
+}
+
+// Close cancela la suscripción
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus distribuye los eventos de estado en memoria a los suscriptores de cada usuario
+// y conserva los últimos eventos para reanudar streams con Last-Event-ID
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	history     []StatusEvent
+	historyNext int
+	historyFull bool
+	subscribers map[string]map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBus crea un bus que conserva los últimos historySize eventos.
+// Los IDs parten del reloj, así un Last-Event-ID anterior a un reinicio
+// sigue siendo menor que los IDs nuevos.
+func NewBus(historySize int) *Bus {
+	if historySize < 1 {
+		historySize = 1
+	}
+	return &Bus{
+		nextID:      uint64(time.Now().UnixMicro()),
+		history:     make([]StatusEvent, historySize),
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish asigna un ID al evento, lo guarda en el historial y lo envía a los
+// suscriptores del usuario. Nunca bloquea: un suscriptor con el buffer lleno se
+// desconecta y puede reanudar desde su último evento.
+func (b *Bus) Publish(event StatusEvent) StatusEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return event
+	}
+
+	b.nextID++
+	event.ID = b.nextID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.history[b.historyNext] = event
+	b.historyNext = (b.historyNext + 1) % len(b.history)
+	if b.historyNext == 0 {
+		b.historyFull = true
+	}
+
+	for sub := range b.subscribers[event.UserID] {
+		select {
+		case sub.ch <- event:
+		default:
+			b.removeLocked(sub)
+		}
+	}
+
+	return event
+}
+
+// Subscribe registra un suscriptor para los eventos de userID
+func (b *Bus) Subscribe(userID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan StatusEvent, subscriberBufferSize)
+	sub := &Subscription{C: ch, ch: ch, userID: userID, bus: b}
+
+	if b.closed {
+		close(ch)
+		return sub
+	}
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+
+	return sub
+}
+
+// Since retorna, en orden, los eventos de userID posteriores a lastEventID
+// que todavía están en el historial
+func (b *Bus) Since(userID string, lastEventID uint64) []StatusEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start, count := 0, b.historyNext
+	if b.historyFull {
+		start, count = b.historyNext, len(b.history)
+	}
+
+	var events []StatusEvent
+	for i := 0; i < count; i++ {
+		event := b.history[(start+i)%len(b.history)]
+		if event.UserID == userID && event.ID > lastEventID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Close desconecta a todos los suscriptores; los Publish posteriores se descartan
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// SubscriberCount retorna la cantidad de suscriptores activos de userID
+func (b *Bus) SubscriberCount(userID string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers[userID])
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(sub)
+}
+
+func (b *Bus) removeLocked(sub *Subscription) {
+	subs, ok := b.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.userID)
+	}
+	close(sub.ch)
+}
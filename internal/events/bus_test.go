@@ -0,0 +1,137 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"resume-backend-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func newEvent(userID string, status domain.ResumeRequestStatus) StatusEvent {
+	return StatusEvent{RequestID: uuid.New(), UserID: userID, Status: status}
+}
+
+func receive(t *testing.T, sub *Subscription) StatusEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.C:
+		if !ok {
+			t.Fatal("la suscripción se cerró inesperadamente")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no se recibió el evento")
+	}
+	return StatusEvent{}
+}
+
+func TestBusFansOutPerUser(t *testing.T) {
+	bus := NewBus(10)
+	alice1 := bus.Subscribe("alice")
+	alice2 := bus.Subscribe("alice")
+	bob := bus.Subscribe("bob")
+
+	published := bus.Publish(newEvent("alice", domain.StatusUploaded))
+
+	for _, sub := range []*Subscription{alice1, alice2} {
+		if got := receive(t, sub); got.ID != published.ID || got.Status != domain.StatusUploaded {
+			t.Errorf("evento recibido = %+v, se esperaba %+v", got, published)
+		}
+	}
+
+	select {
+	case event := <-bob.C:
+		t.Errorf("bob recibió un evento de otro usuario: %+v", event)
+	default:
+	}
+}
+
+func TestBusAssignsIncreasingIDs(t *testing.T) {
+	bus := NewBus(10)
+	first := bus.Publish(newEvent("alice", domain.StatusPending))
+	second := bus.Publish(newEvent("bob", domain.StatusPending))
+
+	if first.ID == 0 || second.ID <= first.ID {
+		t.Errorf("IDs no crecientes: %d, %d", first.ID, second.ID)
+	}
+	if first.OccurredAt.IsZero() {
+		t.Error("OccurredAt no fue asignado")
+	}
+}
+
+func TestBusSinceReplaysUserEventsAfterID(t *testing.T) {
+	bus := NewBus(10)
+	first := bus.Publish(newEvent("alice", domain.StatusPending))
+	bus.Publish(newEvent("bob", domain.StatusPending))
+	second := bus.Publish(newEvent("alice", domain.StatusUploaded))
+	third := bus.Publish(newEvent("alice", domain.StatusProcessing))
+
+	got := bus.Since("alice", first.ID)
+	if len(got) != 2 || got[0].ID != second.ID || got[1].ID != third.ID {
+		t.Fatalf("Since = %+v, se esperaban los eventos %d y %d", got, second.ID, third.ID)
+	}
+
+	if got := bus.Since("alice", third.ID); len(got) != 0 {
+		t.Errorf("Since con el último ID retornó %d eventos", len(got))
+	}
+}
+
+func TestBusHistoryKeepsOnlyLatestEvents(t *testing.T) {
+	bus := NewBus(3)
+	var published []StatusEvent
+	for i := 0; i < 5; i++ {
+		published = append(published, bus.Publish(newEvent("alice", domain.StatusPending)))
+	}
+
+	got := bus.Since("alice", 0)
+	if len(got) != 3 {
+		t.Fatalf("Since retornó %d eventos, se esperaban 3", len(got))
+	}
+	for i, event := range got {
+		if event.ID != published[i+2].ID {
+			t.Errorf("evento %d = %d, se esperaba %d", i, event.ID, published[i+2].ID)
+		}
+	}
+}
+
+func TestBusDisconnectsSlowSubscriber(t *testing.T) {
+	bus := NewBus(10)
+	slow := bus.Subscribe("alice")
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		bus.Publish(newEvent("alice", domain.StatusPending))
+	}
+
+	if n := bus.SubscriberCount("alice"); n != 0 {
+		t.Errorf("SubscriberCount = %d, el suscriptor lento debió desconectarse", n)
+	}
+
+	received := 0
+	for range slow.C {
+		received++
+	}
+	if received != subscriberBufferSize {
+		t.Errorf("se recibieron %d eventos antes del cierre, se esperaban %d", received, subscriberBufferSize)
+	}
+}
+
+func TestBusCloseEndsSubscriptions(t *testing.T) {
+	bus := NewBus(10)
+	sub := bus.Subscribe("alice")
+	bus.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Error("la suscripción sigue abierta tras Close")
+	}
+
+	late := bus.Subscribe("alice")
+	if _, ok := <-late.C; ok {
+		t.Error("una suscripción nueva tras Close debe estar cerrada")
+	}
+
+	// Publicar y cancelar después de cerrar no debe causar panic
+	bus.Publish(newEvent("alice", domain.StatusPending))
+	sub.Close()
+}
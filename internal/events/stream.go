@@ -0,0 +1,77 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Stream escribe los eventos de la suscripción en formato Server-Sent Events hasta que
+// la suscripción se cierre o falle una escritura (cliente desconectado). Primero envía
+// replay y luego los eventos en vivo, sin repetir IDs ya enviados. Cada heartbeat envía
+// un comentario para mantener viva la conexión y detectar desconexiones.
+func Stream(w *bufio.Writer, sub *Subscription, replay []StatusEvent, heartbeat time.Duration) error {
+	defer sub.Close()
+
+	if _, err := w.WriteString(": connected\n\n"); err != nil {
+		return err
+	}
+
+	var lastSentID uint64
+	for _, event := range replay {
+		if err := writeEvent(w, event); err != nil {
+			return err
+		}
+		lastSentID = event.ID
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if event.ID <= lastSentID {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return err
+			}
+			lastSentID = event.ID
+		case <-ticker.C:
+			if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+				return err
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// ParseLastEventID interpreta el header Last-Event-ID; un valor vacío o inválido es 0
+func ParseLastEventID(value string) uint64 {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func writeEvent(w *bufio.Writer, event StatusEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error al serializar evento %d: %w", event.ID, err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: status\ndata: %s\n\n", event.ID, data)
+	return err
+}
@@ -0,0 +1,164 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"resume-backend-service/internal/domain"
+)
+
+// sseFrame es un bloque de un stream SSE (separado por una línea en blanco)
+type sseFrame struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+func readFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	t.Helper()
+	var frame sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error al leer el stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return frame
+		}
+		switch {
+		case strings.HasPrefix(line, ": "):
+			frame.comment = strings.TrimPrefix(line, ": ")
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// startStream ejecuta Stream sobre un pipe y retorna el lector y el canal con su resultado
+func startStream(sub *Subscription, replay []StatusEvent, heartbeat time.Duration) (*bufio.Reader, *io.PipeReader, chan error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := Stream(bufio.NewWriter(pw), sub, replay, heartbeat)
+		pw.Close()
+		done <- err
+	}()
+	return bufio.NewReader(pr), pr, done
+}
+
+func TestStreamReplaysThenSendsLiveEvents(t *testing.T) {
+	bus := NewBus(10)
+	missed := bus.Publish(newEvent("alice", domain.StatusUploaded))
+
+	sub := bus.Subscribe("alice")
+	replay := bus.Since("alice", 0)
+	r, _, done := startStream(sub, replay, time.Hour)
+
+	if frame := readFrame(t, r); frame.comment != "connected" {
+		t.Fatalf("primer bloque = %+v, se esperaba el comentario de conexión", frame)
+	}
+
+	frame := readFrame(t, r)
+	if frame.id != strconv.FormatUint(missed.ID, 10) || frame.event != "status" {
+		t.Fatalf("bloque de replay = %+v", frame)
+	}
+
+	live := bus.Publish(newEvent("alice", domain.StatusFailed))
+	frame = readFrame(t, r)
+	if frame.id != strconv.FormatUint(live.ID, 10) {
+		t.Fatalf("bloque en vivo = %+v, se esperaba id %d", frame, live.ID)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(frame.data), &payload); err != nil {
+		t.Fatalf("data no es JSON: %v", err)
+	}
+	if payload["status"] != string(domain.StatusFailed) || payload["request_id"] != live.RequestID.String() {
+		t.Errorf("payload = %v", payload)
+	}
+	if _, ok := payload["user_id"]; ok {
+		t.Error("el payload no debe exponer user_id")
+	}
+
+	bus.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Stream retornó %v al cerrar el bus", err)
+	}
+}
+
+func TestStreamSkipsEventsAlreadyReplayed(t *testing.T) {
+	bus := NewBus(10)
+	sub := bus.Subscribe("alice")
+	// El evento llega tanto al historial como al canal de la suscripción
+	event := bus.Publish(newEvent("alice", domain.StatusUploaded))
+	replay := bus.Since("alice", 0)
+
+	r, _, done := startStream(sub, replay, time.Hour)
+	readFrame(t, r) // connected
+	if frame := readFrame(t, r); frame.id != strconv.FormatUint(event.ID, 10) {
+		t.Fatalf("bloque de replay = %+v", frame)
+	}
+
+	next := bus.Publish(newEvent("alice", domain.StatusProcessing))
+	if frame := readFrame(t, r); frame.id != strconv.FormatUint(next.ID, 10) {
+		t.Fatalf("se esperaba el evento %d sin duplicar el %d, se obtuvo %+v", next.ID, event.ID, frame)
+	}
+
+	bus.Close()
+	<-done
+}
+
+func TestStreamSendsHeartbeats(t *testing.T) {
+	bus := NewBus(10)
+	r, _, done := startStream(bus.Subscribe("alice"), nil, 10*time.Millisecond)
+
+	readFrame(t, r) // connected
+	if frame := readFrame(t, r); frame.comment != "heartbeat" {
+		t.Fatalf("bloque = %+v, se esperaba un heartbeat", frame)
+	}
+
+	bus.Close()
+	<-done
+}
+
+func TestStreamStopsWhenClientDisconnects(t *testing.T) {
+	bus := NewBus(10)
+	r, pr, done := startStream(bus.Subscribe("alice"), nil, 10*time.Millisecond)
+
+	readFrame(t, r) // connected
+	pr.CloseWithError(errors.New("cliente desconectado"))
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Stream debe retornar el error de escritura")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stream no terminó tras la desconexión")
+	}
+
+	if n := bus.SubscriberCount("alice"); n != 0 {
+		t.Errorf("SubscriberCount = %d, la suscripción debe cancelarse", n)
+	}
+}
+
+func TestParseLastEventID(t *testing.T) {
+	cases := map[string]uint64{"": 0, "42": 42, "abc": 0, "-1": 0}
+	for input, want := range cases {
+		if got := ParseLastEventID(input); got != want {
+			t.Errorf("ParseLastEventID(%q) = %d, se esperaba %d", input, got, want)
+		}
+	}
+}
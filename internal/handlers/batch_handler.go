@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"resume-backend-service/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type BatchHandler struct {
+	batchService *services.BatchService
+}
+
+func NewBatchHandler(batchService *services.BatchService) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+	}
+}
+
+// ProcessBatchHandler recibe varios CVs (el campo 'file' repetido) o un único ZIP y crea
+// un lote con una solicitud por archivo
+func (h *BatchHandler) ProcessBatchHandler(c *fiber.Ctx) error {
+	instructions := c.FormValue("instructions")
+	language := c.FormValue("language")
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Campo 'file' requerido.",
+		})
+	}
+
+	if language == "" {
+		language = "esp"
+	}
+
+	// force=true procesa los archivos aunque ya existan resultados para el mismo contenido
+	force := false
+	if value := c.FormValue("force"); value != "" {
+		force, err = strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "El campo 'force' debe ser true o false.",
+			})
+		}
+	}
+
+	userID := ""
+	if subject := c.Locals("user_subject"); subject != nil {
+		userID = subject.(string)
+	}
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "error",
+			"message": "No se pudo identificar al usuario.",
+		})
+	}
+
+	response, err := h.batchService.ProcessBatch(userID, instructions, language, force, form.File["file"])
+	if err != nil {
+		return batchErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(response)
+}
+
+// GetBatchStatus obtiene el progreso de un lote y el estado de cada una de sus solicitudes
+func (h *BatchHandler) GetBatchStatus(c *fiber.Ctx) error {
+	batchID, err := uuid.Parse(c.Params("batch_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Batch ID inválido",
+		})
+	}
+
+	userID := c.Locals("user_subject").(string)
+
+	status, err := h.batchService.GetBatchStatus(userID, batchID)
+	if err != nil {
+		return batchErrorResponse(c, err)
+	}
+
+	return c.JSON(status)
+}
+
+func batchErrorResponse(c *fiber.Ctx, err error) error {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		return c.Status(fiberErr.Code).JSON(fiber.Map{
+			"status":  "error",
+			"message": fiberErr.Message,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Error interno del servidor.",
+	})
+}
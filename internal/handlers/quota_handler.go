@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"resume-backend-service/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QuotaHandler struct {
+	quotaService *services.QuotaService
+}
+
+func NewQuotaHandler(quotaService *services.QuotaService) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+	}
+}
+
+// GetQuota obtiene los límites y el consumo del usuario autenticado
+func (h *QuotaHandler) GetQuota(c *fiber.Ctx) error {
+	userID := c.Locals("user_subject").(string)
+
+	response, err := h.quotaService.GetQuota(userID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return c.Status(fiberErr.Code).JSON(fiber.Map{
+				"status":  "error",
+				"message": fiberErr.Message,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Error interno del servidor.",
+		})
+	}
+
+	return c.JSON(response)
+}
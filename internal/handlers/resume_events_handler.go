@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"bufio"
+	"log"
+	"resume-backend-service/internal/events"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ResumeEventsHandler struct {
+	eventBus  *events.Bus
+	heartbeat time.Duration
+}
+
+func NewResumeEventsHandler(eventBus *events.Bus, heartbeat time.Duration) *ResumeEventsHandler {
+	return &ResumeEventsHandler{
+		eventBus:  eventBus,
+		heartbeat: heartbeat,
+	}
+}
+
+// StreamStatus abre un stream SSE con los cambios de estado de las solicitudes del usuario.
+// Si el cliente envía Last-Event-ID se reenvían primero los eventos que se perdió.
+func (h *ResumeEventsHandler) StreamStatus(c *fiber.Ctx) error {
+	userID := c.Locals("user_subject").(string)
+
+	lastEventID := events.ParseLastEventID(c.Get("Last-Event-ID"))
+
+	// Suscribirse antes de leer el historial para no perder eventos entre ambos pasos
+	subscription := h.eventBus.Subscribe(userID)
+	replay := h.eventBus.Since(userID, lastEventID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	log.Printf("📡 Stream de estados abierto: user_id=%s, last_event_id=%d, replay=%d", userID, lastEventID, len(replay))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := events.Stream(w, subscription, replay, h.heartbeat)
+		if err != nil {
+			log.Printf("📡 Stream de estados cerrado: user_id=%s (%v)", userID, err)
+			return
+		}
+		log.Printf("📡 Stream de estados cerrado: user_id=%s", userID)
+	})
+
+	return nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"resume-backend-service/internal/services"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,8 +21,9 @@ func (h *ResumeHandler) ProcessResumeHandler(c *fiber.Ctx) error {
 	instructions := c.FormValue("instructions")
 	language := c.FormValue("language")
 
-	fileHeader, err := c.FormFile("file")
-	if err != nil {
+	// Varias imágenes (una por página) se envían repitiendo el campo 'file'
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Campo 'file' requerido.",
@@ -32,6 +34,18 @@ func (h *ResumeHandler) ProcessResumeHandler(c *fiber.Ctx) error {
 		language = "esp"
 	}
 
+	// force=true procesa el archivo aunque ya exista un resultado para el mismo contenido
+	force := false
+	if value := c.FormValue("force"); value != "" {
+		force, err = strconv.ParseBool(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "El campo 'force' debe ser true o false.",
+			})
+		}
+	}
+
 	// Extraer user_id del token JWT (guardado por el middleware de autenticación)
 	// El middleware guarda el subject (UUID del usuario) en user_subject
 	userID := ""
@@ -51,7 +65,8 @@ func (h *ResumeHandler) ProcessResumeHandler(c *fiber.Ctx) error {
 		userID,
 		instructions,
 		language,
-		fileHeader,
+		force,
+		form.File["file"],
 	)
 	if err != nil {
 		// Si es un error de Fiber, retornarlo con su código
@@ -127,6 +127,11 @@ func (h *ResumeListHandler) GetResumeDetail(c *fiber.Ctx) error {
 		RequestID:        request.RequestID.String(),
 		OriginalFilename: request.OriginalFilename,
 		OriginalFileType: request.OriginalFileType,
+		DetectedMIMEType: request.DetectedMIMEType,
+		TextEncoding:     request.TextEncoding,
+		PageCount:        request.PageCount,
+		PDFProducer:      request.PDFProducer,
+		ContentSHA256:    request.ContentSHA256,
 		FileSizeBytes:    request.FileSizeBytes,
 		Language:         request.Language,
 		Instructions:     request.Instructions,
@@ -140,6 +145,24 @@ func (h *ResumeListHandler) GetResumeDetail(c *fiber.Ctx) error {
 		CompletedAt:      request.CompletedAt,
 	}
 
+	// Si se completó copiando el resultado de otra solicitud con el mismo contenido
+	if request.ClonedFrom != nil {
+		detail.ClonedFrom = request.ClonedFrom.String()
+	}
+	if request.BatchID != nil {
+		detail.BatchID = request.BatchID.String()
+	}
+
+	// Si falló, exponer el detalle estructurado del error
+	if processingErr := request.ProcessingError(); processingErr != nil {
+		detail.Error = &dto.ProcessingErrorDTO{
+			Code:      processingErr.Code,
+			Message:   processingErr.Message,
+			Retryable: processingErr.Retryable,
+			Stage:     processingErr.Stage,
+		}
+	}
+
 	// Si está completado, obtener datos estructurados desde versión activa
 	if request.Status == "completed" {
 		processedResume, err := h.processedResumeRepo.FindByRequestID(requestID)
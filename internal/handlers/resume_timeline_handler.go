@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ResumeTimelineHandler struct {
+	resumeRequestRepo *repository.ResumeRequestRepository
+	eventRepo         *repository.ResumeRequestEventRepository
+}
+
+func NewResumeTimelineHandler(resumeRequestRepo *repository.ResumeRequestRepository, eventRepo *repository.ResumeRequestEventRepository) *ResumeTimelineHandler {
+	return &ResumeTimelineHandler{
+		resumeRequestRepo: resumeRequestRepo,
+		eventRepo:         eventRepo,
+	}
+}
+
+// GetTimeline obtiene el historial de cambios de estado de una solicitud
+func (h *ResumeTimelineHandler) GetTimeline(c *fiber.Ctx) error {
+	requestIDStr := c.Params("request_id")
+	requestID, err := uuid.Parse(requestIDStr)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Request ID inválido",
+		})
+	}
+
+	userID := c.Locals("user_subject").(string)
+
+	// Verificar que la solicitud pertenece al usuario
+	request, err := h.resumeRequestRepo.FindByRequestID(requestID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "error",
+			"message": "CV no encontrado",
+		})
+	}
+
+	if request.UserID != userID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "No tienes acceso a este CV",
+		})
+	}
+
+	events, err := h.eventRepo.FindByRequestID(requestID)
+	if err != nil {
+		log.Printf("❌ Error al obtener historial: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Error al obtener historial",
+		})
+	}
+
+	// Convertir a DTO
+	timelineEvents := make([]dto.TimelineEvent, len(events))
+	for i, event := range events {
+		timelineEvents[i] = dto.TimelineEvent{
+			ID:        event.ID,
+			ToStatus:  string(event.ToStatus),
+			Actor:     string(event.Actor),
+			Detail:    event.Detail,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.FromStatus != nil {
+			timelineEvents[i].FromStatus = string(*event.FromStatus)
+		}
+	}
+
+	return c.JSON(dto.TimelineResponse{
+		Status:    "success",
+		RequestID: requestID.String(),
+		Total:     len(timelineEvents),
+		Events:    timelineEvents,
+	})
+}
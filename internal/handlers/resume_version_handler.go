@@ -12,12 +12,14 @@ import (
 )
 
 type ResumeVersionHandler struct {
+	unitOfWork          *repository.UnitOfWork
 	resumeVersionRepo   *repository.ResumeVersionRepository
 	processedResumeRepo *repository.ProcessedResumeRepository
 }
 
-func NewResumeVersionHandler(resumeVersionRepo *repository.ResumeVersionRepository, processedResumeRepo *repository.ProcessedResumeRepository) *ResumeVersionHandler {
+func NewResumeVersionHandler(unitOfWork *repository.UnitOfWork, resumeVersionRepo *repository.ResumeVersionRepository, processedResumeRepo *repository.ProcessedResumeRepository) *ResumeVersionHandler {
 	return &ResumeVersionHandler{
+		unitOfWork:          unitOfWork,
 		resumeVersionRepo:   resumeVersionRepo,
 		processedResumeRepo: processedResumeRepo,
 	}
@@ -121,14 +123,24 @@ func (h *ResumeVersionHandler) CreateVersion(c *fiber.Ctx) error {
 		})
 	}
 
-	// Crear nueva versión
-	versionID, err := h.resumeVersionRepo.CreateVersion(
-		requestID,
-		userID,
-		&req.StructuredData,
-		req.VersionName,
-		"user",
-	)
+	// Crear nueva versión. El CV se bloquea durante la transacción para que dos
+	// versiones simultáneas no calculen el mismo version_number.
+	var versionID int64
+	err = h.unitOfWork.Do(func(tx *sql.Tx) error {
+		if _, err := h.processedResumeRepo.WithTx(tx).FindByRequestIDForUpdate(requestID); err != nil {
+			return err
+		}
+
+		var txErr error
+		versionID, txErr = h.resumeVersionRepo.WithTx(tx).CreateVersion(
+			requestID,
+			userID,
+			&req.StructuredData,
+			req.VersionName,
+			"user",
+		)
+		return txErr
+	})
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"path/filepath"
+	"resume-backend-service/pkg/storage"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StorageHandler struct {
+	storage *storage.LocalStorage
+}
+
+func NewStorageHandler(localStorage *storage.LocalStorage) *StorageHandler {
+	return &StorageHandler{
+		storage: localStorage,
+	}
+}
+
+// Download sirve un objeto del almacenamiento local a partir de una URL de
+// LocalStorage.SignedURL (parámetros expires y signature)
+func (h *StorageHandler) Download(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		key = ""
+	}
+
+	if err := h.storage.VerifySignature(key, c.Query("expires"), c.Query("signature")); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "URL de descarga inválida o vencida.",
+		})
+	}
+
+	object, err := h.storage.Get(c.UserContext(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Archivo no encontrado.",
+		})
+	}
+	if err != nil {
+		log.Printf("❌ Error al leer objeto %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Error interno del servidor.",
+		})
+	}
+
+	// Attachment toma el tipo de la extensión; se reemplaza por el guardado si lo hay
+	c.Attachment(filepath.Base(key))
+	if contentType := h.storage.ContentType(key); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+	// Fiber cierra el stream al terminar de enviarlo
+	return c.SendStream(object)
+}
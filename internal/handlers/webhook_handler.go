@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook registra un webhook para el usuario autenticado
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	userID := c.Locals("user_subject").(string)
+
+	var req dto.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Datos inválidos",
+		})
+	}
+
+	response, err := h.webhookService.CreateSubscription(userID, req)
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListWebhooks obtiene los webhooks del usuario autenticado
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	userID := c.Locals("user_subject").(string)
+
+	response, err := h.webhookService.ListSubscriptions(userID)
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.JSON(response)
+}
+
+// DeleteWebhook elimina un webhook del usuario autenticado
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	subscriptionID, err := uuid.Parse(c.Params("webhook_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Webhook ID inválido",
+		})
+	}
+
+	userID := c.Locals("user_subject").(string)
+
+	if err := h.webhookService.DeleteSubscription(userID, subscriptionID); err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"message": "Webhook eliminado correctamente",
+	})
+}
+
+// ListDeliveries obtiene el registro de entregas de un webhook
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	subscriptionID, err := uuid.Parse(c.Params("webhook_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Webhook ID inválido",
+		})
+	}
+
+	userID := c.Locals("user_subject").(string)
+
+	response, err := h.webhookService.ListDeliveries(userID, subscriptionID)
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+
+	return c.JSON(response)
+}
+
+// webhookErrorResponse mapea los errores del servicio a la respuesta JSON
+func webhookErrorResponse(c *fiber.Ctx, err error) error {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		return c.Status(fiberErr.Code).JSON(fiber.Map{
+			"status":  "error",
+			"message": fiberErr.Message,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Error interno del servidor.",
+	})
+}
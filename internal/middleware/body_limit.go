@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxDiscardBytes es lo que se lee (y descarta) del body que el handler no consumió,
+// para poder reutilizar la conexión; si queda más, la conexión se cierra
+const maxDiscardBytes = 64 * 1024
+
+// BodyLimit limita el tamaño del body. El servidor recibe los bodies como stream
+// (StreamRequestBody), así que fasthttp ya no aplica Config.BodyLimit: el body se lee de
+// la conexión recién cuando el handler lo consume. Por eso el límite se valida con el
+// Content-Length antes de leer nada, y los bodies sin Content-Length (chunked) se
+// rechazan con 411. message es el error que se responde con 413.
+//
+// El límite se aplica por ruta: una ruta con un límite propio debe registrarse antes que
+// el límite general y responder sin llamar a Next, para que el general no se ejecute.
+func BodyLimit(limit int64, message string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !c.Request().IsBodyStream() {
+			return c.Next()
+		}
+
+		length := c.Request().Header.ContentLength()
+		if length < 0 {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Se requiere el header Content-Length.",
+			})
+		}
+		if int64(length) > limit {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"status":  "error",
+				"message": message,
+			})
+		}
+
+		err := c.Next()
+		discardUnreadBody(c)
+		return err
+	}
+}
+
+// discardUnreadBody consume lo que quede del body (p. ej. si el handler respondió sin
+// leerlo). Con el body como stream, esos bytes se leerían como el inicio de la siguiente
+// solicitud de la conexión.
+func discardUnreadBody(c *fiber.Ctx) {
+	stream := c.Context().RequestBodyStream()
+	if stream == nil {
+		return
+	}
+	if n, _ := io.CopyN(io.Discard, stream, maxDiscardBytes+1); n > maxDiscardBytes {
+		c.Context().SetConnectionClose()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"log"
+	"resume-backend-service/pkg/utils"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// CallbackSignatureHeader contiene la firma HMAC del body ("sha256=<hex>")
+	CallbackSignatureHeader = "X-Callback-Signature"
+	// CallbackTimestampHeader contiene el timestamp Unix (segundos) usado en la firma
+	CallbackTimestampHeader = "X-Callback-Timestamp"
+)
+
+// CallbackSignatureMiddleware valida la firma HMAC de los callbacks de AWS Lambda
+type CallbackSignatureMiddleware struct {
+	secrets   []string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewCallbackSignatureMiddleware crea el middleware con los secretos activos.
+// Se aceptan varios secretos para permitir la rotación (actual + anterior).
+func NewCallbackSignatureMiddleware(secrets []string, tolerance time.Duration) *CallbackSignatureMiddleware {
+	activeSecrets := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			activeSecrets = append(activeSecrets, secret)
+		}
+	}
+
+	return &CallbackSignatureMiddleware{
+		secrets:   activeSecrets,
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+// ValidateSignature rechaza con 401 los callbacks sin firma, con firma inválida
+// o con un timestamp fuera de la ventana de tolerancia (protección contra replays)
+func (m *CallbackSignatureMiddleware) ValidateSignature() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(m.secrets) == 0 {
+			log.Printf("❌ Callback rechazado: no hay secretos HMAC configurados (ip=%s)", c.IP())
+			return unauthorizedCallback(c, "Firma de callback no configurada")
+		}
+
+		signature := c.Get(CallbackSignatureHeader)
+		timestampStr := c.Get(CallbackTimestampHeader)
+		if signature == "" || timestampStr == "" {
+			log.Printf("❌ Callback rechazado: headers de firma ausentes (ip=%s)", c.IP())
+			return unauthorizedCallback(c, "Firma de callback requerida")
+		}
+
+		timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+		if err != nil {
+			log.Printf("❌ Callback rechazado: timestamp inválido %q (ip=%s)", timestampStr, c.IP())
+			return unauthorizedCallback(c, "Timestamp de firma inválido")
+		}
+
+		// Verificar que el timestamp esté dentro de la ventana permitida
+		age := m.now().Sub(time.Unix(timestamp, 0))
+		if age > m.tolerance || age < -m.tolerance {
+			log.Printf("❌ Callback rechazado: firma expirada (edad=%s, ip=%s)", age.Round(time.Second), c.IP())
+			return unauthorizedCallback(c, "Firma de callback expirada")
+		}
+
+		// Probar con todos los secretos activos (rotación)
+		body := c.Body()
+		for _, secret := range m.secrets {
+			if utils.VerifyHMACSignature(secret, timestamp, body, signature) {
+				return c.Next()
+			}
+		}
+
+		log.Printf("❌ Callback rechazado: firma inválida (ip=%s)", c.IP())
+		return unauthorizedCallback(c, "Firma de callback inválida")
+	}
+}
+
+func unauthorizedCallback(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"status":  "error",
+		"message": message,
+	})
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"resume-backend-service/pkg/utils"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestValidateSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"request_id":"abc","status":"completed"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	sign := func(secret string, ts time.Time, payload []byte) string {
+		return utils.ComputeHMACSignature(secret, ts.Unix(), payload)
+	}
+
+	tests := []struct {
+		name      string
+		secrets   []string
+		body      []byte
+		signature string
+		timestamp string
+		expected  int
+	}{
+		{"Valid signature", []string{"actual"}, body, sign("actual", now, body), timestamp, fiber.StatusOK},
+		{"Rotated secret", []string{"actual", "anterior"}, body, sign("anterior", now, body), timestamp, fiber.StatusOK},
+		{"Within tolerance", []string{"actual"}, body, sign("actual", now.Add(-4*time.Minute), body), strconv.FormatInt(now.Add(-4*time.Minute).Unix(), 10), fiber.StatusOK},
+		{"Tampered body", []string{"actual"}, []byte(`{"request_id":"abc","status":"failed"}`), sign("actual", now, body), timestamp, fiber.StatusUnauthorized},
+		{"Unknown secret", []string{"actual"}, body, sign("otro", now, body), timestamp, fiber.StatusUnauthorized},
+		{"Expired timestamp", []string{"actual"}, body, sign("actual", now.Add(-10*time.Minute), body), strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), fiber.StatusUnauthorized},
+		{"Future timestamp", []string{"actual"}, body, sign("actual", now.Add(10*time.Minute), body), strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10), fiber.StatusUnauthorized},
+		{"Timestamp not signed", []string{"actual"}, body, sign("actual", now, body), strconv.FormatInt(now.Unix()-1, 10), fiber.StatusUnauthorized},
+		{"Missing signature", []string{"actual"}, body, "", timestamp, fiber.StatusUnauthorized},
+		{"Missing timestamp", []string{"actual"}, body, sign("actual", now, body), "", fiber.StatusUnauthorized},
+		{"Malformed timestamp", []string{"actual"}, body, sign("actual", now, body), "ayer", fiber.StatusUnauthorized},
+		{"Malformed signature", []string{"actual"}, body, "md5=abc", timestamp, fiber.StatusUnauthorized},
+		{"No secrets configured", []string{""}, body, sign("", now, body), timestamp, fiber.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewCallbackSignatureMiddleware(tt.secrets, 5*time.Minute)
+			m.now = func() time.Time { return now }
+
+			app := fiber.New()
+			app.Post("/callback", m.ValidateSignature(), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("POST", "/callback", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.signature != "" {
+				req.Header.Set(CallbackSignatureHeader, tt.signature)
+			}
+			if tt.timestamp != "" {
+				req.Header.Set(CallbackTimestampHeader, tt.timestamp)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			if resp.StatusCode != tt.expected {
+				t.Errorf("status = %d, expected %d", resp.StatusCode, tt.expected)
+			}
+		})
+	}
+}
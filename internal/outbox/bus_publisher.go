@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/events"
+	"strings"
+)
+
+// BusPublisher reenvía los eventos resume.* al bus en memoria que alimenta el stream SSE
+type BusPublisher struct {
+	bus *events.Bus
+}
+
+func NewBusPublisher(bus *events.Bus) *BusPublisher {
+	return &BusPublisher{bus: bus}
+}
+
+// resumeEventPayload es el contenido de los eventos resume.* escritos por el repositorio
+type resumeEventPayload struct {
+	Status domain.ResumeRequestStatus `json:"status"`
+	Error  *domain.ProcessingError    `json:"error"`
+}
+
+func (p *BusPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	if !strings.HasPrefix(event.EventType, "resume.") {
+		return nil
+	}
+
+	var payload resumeEventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("payload inválido en evento %s: %w", event.EventID, err)
+	}
+
+	p.bus.Publish(events.StatusEvent{
+		RequestID:  event.RequestID,
+		UserID:     event.UserID,
+		Status:     payload.Status,
+		Error:      payload.Error,
+		OccurredAt: event.CreatedAt,
+	})
+	return nil
+}
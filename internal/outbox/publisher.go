@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"resume-backend-service/internal/domain"
+	"sync"
+)
+
+// Publisher entrega los eventos del outbox a un consumidor. La entrega es al menos una
+// vez: si Publish retorna error el evento se reintenta, y un evento ya publicado puede
+// repetirse tras una caída, así que los consumidores deben deduplicar por EventID.
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// MultiPublisher publica cada evento en todos los publishers. Si alguno falla, el evento
+// se reintenta completo (los que ya lo recibieron lo verán de nuevo).
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogPublisher registra cada evento en el log de la aplicación
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	log.Printf("📣 Evento %s: event_id=%s, request_id=%s, user_id=%s, payload=%s",
+		event.EventType, event.EventID, event.RequestID, event.UserID, string(event.Payload))
+	return nil
+}
+
+// MemoryPublisher guarda los últimos eventos publicados en memoria (útil en desarrollo
+// y para inspeccionar lo que se publicó sin infraestructura externa)
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	capacity int
+	events   []domain.OutboxEvent
+}
+
+// NewMemoryPublisher crea un publisher que conserva como máximo capacity eventos
+func NewMemoryPublisher(capacity int) *MemoryPublisher {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryPublisher{capacity: capacity}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.events) == p.capacity {
+		p.events = append(p.events[:0], p.events[1:]...)
+	}
+	p.events = append(p.events, *event)
+	return nil
+}
+
+// Events retorna una copia de los eventos guardados, del más antiguo al más reciente
+func (p *MemoryPublisher) Events() []domain.OutboxEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]domain.OutboxEvent, len(p.events))
+	copy(events, p.events)
+	return events
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"resume-backend-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type CallbackDeliveryRepository struct {
+	db DBTX
+}
+
+func NewCallbackDeliveryRepository(db *sql.DB) *CallbackDeliveryRepository {
+	return &CallbackDeliveryRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *CallbackDeliveryRepository) WithTx(tx *sql.Tx) *CallbackDeliveryRepository {
+	return &CallbackDeliveryRepository{db: tx}
+}
+
+// RecordDelivery registra la llegada de un resultado. Si el mismo resultado ya
+// había llegado antes, incrementa el contador y retorna el registro existente.
+func (r *CallbackDeliveryRepository) RecordDelivery(requestID uuid.UUID, payloadHash, lambdaStatus string) (*domain.CallbackDelivery, error) {
+	query := `
+		INSERT INTO callback_deliveries (request_id, payload_hash, lambda_status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (request_id, payload_hash) DO UPDATE
+		SET delivery_count = callback_deliveries.delivery_count + 1,
+		    last_received_at = CURRENT_TIMESTAMP
+		RETURNING id, request_id, payload_hash, lambda_status, delivery_count,
+		          outcome, first_received_at, last_received_at
+	`
+
+	var delivery domain.CallbackDelivery
+	var outcome sql.NullString
+	err := r.db.QueryRow(query, requestID, payloadHash, lambdaStatus).Scan(
+		&delivery.ID,
+		&delivery.RequestID,
+		&delivery.PayloadHash,
+		&delivery.LambdaStatus,
+		&delivery.DeliveryCount,
+		&outcome,
+		&delivery.FirstReceivedAt,
+		&delivery.LastReceivedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error al registrar entrega de callback: %w", err)
+	}
+
+	if outcome.Valid {
+		o := domain.CallbackOutcome(outcome.String)
+		delivery.Outcome = &o
+	}
+
+	return &delivery, nil
+}
+
+// LockOutcome bloquea la entrega hasta el fin de la transacción y retorna su resultado
+// actual. Así, dos entregas simultáneas del mismo resultado se procesan de a una.
+func (r *CallbackDeliveryRepository) LockOutcome(deliveryID int64) (*domain.CallbackOutcome, error) {
+	query := `SELECT outcome FROM callback_deliveries WHERE id = $1 FOR UPDATE`
+
+	var outcome sql.NullString
+	if err := r.db.QueryRow(query, deliveryID).Scan(&outcome); err != nil {
+		return nil, fmt.Errorf("error al bloquear entrega de callback: %w", err)
+	}
+
+	if !outcome.Valid {
+		return nil, nil
+	}
+
+	o := domain.CallbackOutcome(outcome.String)
+	return &o, nil
+}
+
+// SetOutcome guarda el resultado de procesar una entrega
+func (r *CallbackDeliveryRepository) SetOutcome(deliveryID int64, outcome domain.CallbackOutcome) error {
+	query := `UPDATE callback_deliveries SET outcome = $1 WHERE id = $2`
+
+	if _, err := r.db.Exec(query, outcome, deliveryID); err != nil {
+		return fmt.Errorf("error al actualizar resultado de entrega: %w", err)
+	}
+
+	return nil
+}
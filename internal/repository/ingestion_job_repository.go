@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"resume-backend-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// spoolChunkSize es el tamaño de cada parte de un archivo del spool en la base de datos
+const spoolChunkSize = 1024 * 1024
+
+type IngestionJobRepository struct {
+	db DBTX
+}
+
+func NewIngestionJobRepository(db *sql.DB) *IngestionJobRepository {
+	return &IngestionJobRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *IngestionJobRepository) WithTx(tx *sql.Tx) *IngestionJobRepository {
+	return &IngestionJobRepository{db: tx}
+}
+
+// Create encola un trabajo de ingesta
+func (r *IngestionJobRepository) Create(job *domain.IngestionJob) error {
+	query := `
+		INSERT INTO ingestion_jobs (request_id, batch_id, spool_path, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(query, job.RequestID, job.BatchID, job.SpoolPath, job.Status, job.NextAttemptAt, job.CreatedAt).Scan(&job.ID)
+	if err != nil {
+		return fmt.Errorf("error al encolar trabajo de ingesta: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimNext reserva el próximo trabajo listo para ejecutar y lo pasa a running por lease.
+// También toma trabajos running cuyo lease venció (el worker que los tenía se cayó).
+// SKIP LOCKED permite que varios workers reserven en paralelo sin tomar el mismo
+// trabajo; como los archivos del trabajo están en la base de datos (ver SaveSpoolFile),
+// el worker puede ser de cualquier instancia. Retorna nil si no hay trabajos pendientes.
+// Los trabajos de un lote se omiten mientras el lote tenga maxPerBatch trabajos en
+// curso (0 = sin límite), así un lote grande no ocupa todo el pool. Dos reservas
+// simultáneas pueden ver la misma cuenta: el límite puede excederse en a lo sumo la
+// cantidad de workers que reservan a la vez.
+func (r *IngestionJobRepository) ClaimNext(lease time.Duration, maxPerBatch int) (*domain.IngestionJob, error) {
+	query := `
+		WITH next_job AS (
+			SELECT id
+			FROM ingestion_jobs q
+			WHERE ((status = $1 AND next_attempt_at <= NOW())
+			    OR (status = $2 AND locked_until <= NOW()))
+			  AND ($4 = 0 OR batch_id IS NULL OR (
+			      SELECT COUNT(*)
+			      FROM ingestion_jobs running
+			      WHERE running.batch_id = q.batch_id
+			        AND running.status = $2
+			        AND running.locked_until > NOW()
+			  ) < $4)
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE ingestion_jobs j
+		SET status = $2,
+		    attempts = j.attempts + 1,
+		    locked_until = NOW() + make_interval(secs => $3)
+		FROM next_job
+		WHERE j.id = next_job.id
+		RETURNING j.id, j.request_id, j.batch_id, j.spool_path, j.status, j.attempts, j.next_attempt_at,
+		          COALESCE(j.last_error, ''), j.created_at
+	`
+
+	var job domain.IngestionJob
+	var batchID uuid.NullUUID
+	err := r.db.QueryRow(query, domain.IngestionJobQueued, domain.IngestionJobRunning, lease.Seconds(), maxPerBatch).Scan(
+		&job.ID,
+		&job.RequestID,
+		&batchID,
+		&job.SpoolPath,
+		&job.Status,
+		&job.Attempts,
+		&job.NextAttemptAt,
+		&job.LastError,
+		&job.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al reservar trabajo de ingesta: %w", err)
+	}
+	if batchID.Valid {
+		job.BatchID = &batchID.UUID
+	}
+
+	return &job, nil
+}
+
+// SaveSpoolFile guarda un archivo del trabajo en partes de spoolChunkSize, leyendo
+// content como stream. name es la ruta relativa del archivo en el spool.
+func (r *IngestionJobRepository) SaveSpoolFile(jobID int64, name string, content io.Reader) error {
+	query := `
+		INSERT INTO ingestion_spool_chunks (job_id, file_name, chunk_index, data)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	buf := make([]byte, spoolChunkSize)
+	for index := 0; ; index++ {
+		n, readErr := io.ReadFull(content, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return fmt.Errorf("error al leer archivo %s del spool: %w", name, readErr)
+		}
+		// Un archivo vacío se guarda igual, como una parte vacía
+		if n > 0 || index == 0 {
+			if _, err := r.db.Exec(query, jobID, name, index, buf[:n]); err != nil {
+				return fmt.Errorf("error al guardar archivo %s del spool: %w", name, err)
+			}
+		}
+		if readErr != nil {
+			return nil
+		}
+	}
+}
+
+// ListSpoolFiles retorna las rutas relativas de los archivos del trabajo, ordenadas
+func (r *IngestionJobRepository) ListSpoolFiles(jobID int64) ([]string, error) {
+	query := `
+		SELECT DISTINCT file_name
+		FROM ingestion_spool_chunks
+		WHERE job_id = $1
+		ORDER BY file_name
+	`
+
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar archivos del spool: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error al escanear archivo del spool: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// CopySpoolFile escribe en dst el archivo name del trabajo, parte por parte
+func (r *IngestionJobRepository) CopySpoolFile(jobID int64, name string, dst io.Writer) error {
+	query := `
+		SELECT data
+		FROM ingestion_spool_chunks
+		WHERE job_id = $1 AND file_name = $2 AND chunk_index = $3
+	`
+
+	for index := 0; ; index++ {
+		var data []byte
+		err := r.db.QueryRow(query, jobID, name, index).Scan(&data)
+		if err == sql.ErrNoRows {
+			if index == 0 {
+				return fmt.Errorf("archivo %s del spool no encontrado", name)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error al leer archivo %s del spool: %w", name, err)
+		}
+		if _, err := dst.Write(data); err != nil {
+			return fmt.Errorf("error al copiar archivo %s del spool: %w", name, err)
+		}
+	}
+}
+
+// MarkSucceeded marca el trabajo como terminado y elimina sus archivos del spool
+func (r *IngestionJobRepository) MarkSucceeded(jobID int64) error {
+	query := `
+		WITH finished AS (
+			UPDATE ingestion_jobs
+			SET status = $2, locked_until = NULL, last_error = NULL, finished_at = NOW()
+			WHERE id = $1
+			RETURNING id
+		)
+		DELETE FROM ingestion_spool_chunks
+		WHERE job_id IN (SELECT id FROM finished)
+	`
+
+	if _, err := r.db.Exec(query, jobID, domain.IngestionJobSucceeded); err != nil {
+		return fmt.Errorf("error al marcar trabajo de ingesta como terminado: %w", err)
+	}
+
+	return nil
+}
+
+// MarkRetry devuelve el trabajo a la cola para reintentarlo en nextAttemptAt
+func (r *IngestionJobRepository) MarkRetry(jobID int64, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE ingestion_jobs
+		SET status = $2, locked_until = NULL, last_error = $3, next_attempt_at = $4
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, jobID, domain.IngestionJobQueued, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("error al reprogramar trabajo de ingesta: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDead mueve el trabajo al estado dead (dead-letter) y elimina sus archivos del
+// spool; no se vuelve a intentar
+func (r *IngestionJobRepository) MarkDead(jobID int64, lastError string) error {
+	query := `
+		WITH finished AS (
+			UPDATE ingestion_jobs
+			SET status = $2, locked_until = NULL, last_error = $3, finished_at = NOW()
+			WHERE id = $1
+			RETURNING id
+		)
+		DELETE FROM ingestion_spool_chunks
+		WHERE job_id IN (SELECT id FROM finished)
+	`
+
+	if _, err := r.db.Exec(query, jobID, domain.IngestionJobDead, lastError); err != nil {
+		return fmt.Errorf("error al mover trabajo de ingesta a dead-letter: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"bytes"
+	"database/sql"
+	"resume-backend-service/internal/domain"
+	"testing"
+	"time"
+)
+
+// queuedJob crea, dentro de tx, una solicitud pending con su trabajo de ingesta
+func queuedJob(t *testing.T, db *sql.DB, tx *sql.Tx) (*domain.ResumeRequest, *domain.IngestionJob) {
+	t.Helper()
+
+	request := domain.NewResumeRequest("test-ingestion", "cv.pdf", "pdf", 1024, "esp", "")
+	if err := NewResumeRequestRepository(db).WithTx(tx).Create(request); err != nil {
+		t.Fatalf("Create request: %v", err)
+	}
+	job := domain.NewIngestionJob(request.RequestID, request.RequestID.String()+".pdf")
+	if err := NewIngestionJobRepository(db).WithTx(tx).Create(job); err != nil {
+		t.Fatalf("Create job: %v", err)
+	}
+	return request, job
+}
+
+func TestSpoolFileRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	repo := NewIngestionJobRepository(db).WithTx(tx)
+	_, job := queuedJob(t, db, tx)
+
+	// Más de dos partes, con la última incompleta, y un archivo vacío
+	content := bytes.Repeat([]byte("0123456789"), spoolChunkSize/4)
+	files := map[string][]byte{job.SpoolPath: content, "vacio.txt": {}}
+	for name, data := range files {
+		if err := repo.SaveSpoolFile(job.ID, name, bytes.NewReader(data)); err != nil {
+			t.Fatalf("SaveSpoolFile(%s): %v", name, err)
+		}
+	}
+
+	names, err := repo.ListSpoolFiles(job.ID)
+	if err != nil {
+		t.Fatalf("ListSpoolFiles: %v", err)
+	}
+	if len(names) != len(files) {
+		t.Fatalf("archivos = %v, se esperaban %d", names, len(files))
+	}
+	for name, data := range files {
+		var got bytes.Buffer
+		if err := repo.CopySpoolFile(job.ID, name, &got); err != nil {
+			t.Fatalf("CopySpoolFile(%s): %v", name, err)
+		}
+		if !bytes.Equal(got.Bytes(), data) {
+			t.Errorf("%s: se leyeron %d bytes, se esperaban %d", name, got.Len(), len(data))
+		}
+	}
+
+	// Al terminar el trabajo se eliminan sus archivos
+	if err := repo.MarkSucceeded(job.ID); err != nil {
+		t.Fatalf("MarkSucceeded: %v", err)
+	}
+	if names, err := repo.ListSpoolFiles(job.ID); err != nil || len(names) != 0 {
+		t.Errorf("archivos tras MarkSucceeded = %v (err=%v), se esperaba ninguno", names, err)
+	}
+}
+
+func TestFailStaleRequestsSkipsPendingWithLiveJob(t *testing.T) {
+	db := openTestDB(t)
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	requestRepo := NewResumeRequestRepository(db).WithTx(tx)
+	jobRepo := NewIngestionJobRepository(db).WithTx(tx)
+
+	queued, _ := queuedJob(t, db, tx)
+	dead, deadJob := queuedJob(t, db, tx)
+	if err := jobRepo.MarkDead(deadJob.ID, "error"); err != nil {
+		t.Fatalf("MarkDead: %v", err)
+	}
+
+	// Un timeout negativo vuelve vencidas a todas las solicitudes de la transacción
+	failed, err := requestRepo.FailStaleRequests(-time.Hour, 1000)
+	if err != nil {
+		t.Fatalf("FailStaleRequests: %v", err)
+	}
+	reaped := map[string]bool{}
+	for _, request := range failed {
+		reaped[request.RequestID.String()] = true
+	}
+	if reaped[queued.RequestID.String()] {
+		t.Error("una solicitud con un trabajo en cola no debe marcarse como vencida")
+	}
+	if !reaped[dead.RequestID.String()] {
+		t.Error("una solicitud sin trabajo activo debe marcarse como vencida")
+	}
+}
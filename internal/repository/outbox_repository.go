@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"resume-backend-service/internal/domain"
+	"time"
+)
+
+// outboxDispatcherLockKey identifica el advisory lock que asegura un único dispatcher activo
+const outboxDispatcherLockKey = 724_310_001
+
+// requestOutboxEventSQL inserta el evento resume.<estado> de cada fila de la CTE indicada,
+// que debe exponer las columnas de resume_requests (alias rr). Se usa dentro de las
+// sentencias que cambian el estado para que el evento quede en la misma transacción.
+const requestOutboxEventSQL = `
+		INSERT INTO outbox_events (request_id, user_id, event_type, payload)
+		SELECT rr.request_id, rr.user_id, 'resume.' || rr.status,
+		       jsonb_strip_nulls(jsonb_build_object(
+		           'request_id', rr.request_id,
+		           'status', rr.status,
+		           'error', CASE WHEN rr.status = 'failed' THEN jsonb_build_object(
+		               'code', rr.error_code,
+		               'message', rr.error_message,
+		               'retryable', rr.error_retryable,
+		               'stage', rr.error_stage
+		           ) END
+		       ))
+		FROM %s rr`
+
+type OutboxRepository struct {
+	db DBTX
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *OutboxRepository) WithTx(tx *sql.Tx) *OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+// TryLockDispatcher toma el lock del dispatcher hasta el fin de la transacción.
+// Retorna false si otra instancia lo tiene; así se conserva el orden por request_id.
+func (r *OutboxRepository) TryLockDispatcher() (bool, error) {
+	var acquired bool
+	if err := r.db.QueryRow(`SELECT pg_try_advisory_xact_lock($1)`, outboxDispatcherLockKey).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("error al tomar lock del dispatcher: %w", err)
+	}
+	return acquired, nil
+}
+
+// FindPending obtiene hasta limit eventos pendientes en orden de creación. Se excluyen los
+// eventos de una solicitud que tiene un evento anterior esperando un reintento, para no
+// publicarlos fuera de orden.
+func (r *OutboxRepository) FindPending(limit int) ([]*domain.OutboxEvent, error) {
+	query := `
+		SELECT e.id, e.event_id, e.request_id, e.user_id, e.event_type, e.payload,
+		       e.attempts, e.created_at
+		FROM outbox_events e
+		WHERE e.published_at IS NULL
+		  AND e.next_attempt_at <= NOW()
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox_events p
+		      WHERE p.request_id = e.request_id
+		        AND p.published_at IS NULL
+		        AND p.id < e.id
+		        AND p.next_attempt_at > NOW()
+		  )
+		ORDER BY e.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener eventos pendientes: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.OutboxEvent
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.EventID,
+			&event.RequestID,
+			&event.UserID,
+			&event.EventType,
+			&event.Payload,
+			&event.Attempts,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error al escanear evento del outbox: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkPublished marca el evento como publicado
+func (r *OutboxRepository) MarkPublished(id int64) error {
+	query := `UPDATE outbox_events SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("error al marcar evento como publicado: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed registra un intento de publicación fallido y programa el siguiente
+func (r *OutboxRepository) MarkFailed(id int64, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $1
+	`
+	if _, err := r.db.Exec(query, id, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("error al registrar fallo de publicación: %w", err)
+	}
+	return nil
+}
@@ -9,13 +9,18 @@ import (
 )
 
 type ProcessedResumeRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewProcessedResumeRepository(db *sql.DB) *ProcessedResumeRepository {
 	return &ProcessedResumeRepository{db: db}
 }
 
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *ProcessedResumeRepository) WithTx(tx *sql.Tx) *ProcessedResumeRepository {
+	return &ProcessedResumeRepository{db: tx}
+}
+
 // Create crea un nuevo CV procesado (simplificado)
 func (r *ProcessedResumeRepository) Create(resume *domain.ProcessedResume) error {
 	query := `
@@ -42,11 +47,21 @@ func (r *ProcessedResumeRepository) Create(resume *domain.ProcessedResume) error
 
 // FindByRequestID busca un CV procesado por su request_id
 func (r *ProcessedResumeRepository) FindByRequestID(requestID uuid.UUID) (*domain.ProcessedResume, error) {
+	return r.findByRequestID(requestID, "")
+}
+
+// FindByRequestIDForUpdate busca un CV procesado y bloquea su fila hasta el fin de la
+// transacción, serializando operaciones concurrentes sobre sus versiones
+func (r *ProcessedResumeRepository) FindByRequestIDForUpdate(requestID uuid.UUID) (*domain.ProcessedResume, error) {
+	return r.findByRequestID(requestID, "FOR UPDATE")
+}
+
+func (r *ProcessedResumeRepository) findByRequestID(requestID uuid.UUID, lockClause string) (*domain.ProcessedResume, error) {
 	query := `
 		SELECT id, request_id, user_id, active_version_id, created_at, updated_at
 		FROM processed_resumes
 		WHERE request_id = $1
-	`
+	` + lockClause
 
 	var resume domain.ProcessedResume
 	err := r.db.QueryRow(query, requestID).Scan(
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"resume-backend-service/internal/domain"
+	"time"
+)
+
+type QuotaRepository struct {
+	db DBTX
+}
+
+func NewQuotaRepository(db *sql.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *QuotaRepository) WithTx(tx *sql.Tx) *QuotaRepository {
+	return &QuotaRepository{db: tx}
+}
+
+// LockUser bloquea la fila de cuota del usuario (creándola si no existe) hasta el fin de
+// la transacción, así las subidas simultáneas del mismo usuario se validan de a una.
+// Solo tiene sentido en un repositorio obtenido con WithTx.
+func (r *QuotaRepository) LockUser(userID string) error {
+	if _, err := r.db.Exec(`INSERT INTO user_quotas (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`, userID); err != nil {
+		return fmt.Errorf("error al crear cuota del usuario: %w", err)
+	}
+
+	var locked string
+	err := r.db.QueryRow(`SELECT user_id FROM user_quotas WHERE user_id = $1 FOR UPDATE`, userID).Scan(&locked)
+	if err != nil {
+		return fmt.Errorf("error al bloquear cuota del usuario: %w", err)
+	}
+
+	return nil
+}
+
+// FindLimits retorna los límites del usuario; los que no están personalizados toman el
+// valor de defaults
+func (r *QuotaRepository) FindLimits(userID string, defaults domain.QuotaLimits) (domain.QuotaLimits, error) {
+	query := `
+		SELECT COALESCE(MAX(max_requests_per_day), $2), COALESCE(MAX(max_storage_bytes), $3)
+		FROM user_quotas
+		WHERE user_id = $1
+	`
+
+	var limits domain.QuotaLimits
+	err := r.db.QueryRow(query, userID, defaults.MaxRequestsPerDay, defaults.MaxStorageBytes).Scan(
+		&limits.MaxRequestsPerDay,
+		&limits.MaxStorageBytes,
+	)
+	if err != nil {
+		return domain.QuotaLimits{}, fmt.Errorf("error al obtener cuota del usuario: %w", err)
+	}
+
+	return limits, nil
+}
+
+// GetUsage calcula el consumo del usuario: solicitudes creadas desde dayStart y bytes
+// guardados. No cuentan como almacenamiento los archivos que fallaron antes de subirse a S3.
+func (r *QuotaRepository) GetUsage(userID string, dayStart time.Time) (domain.QuotaUsage, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE created_at >= $2),
+		       COALESCE(SUM(file_size_bytes) FILTER (WHERE status <> $3 OR s3_input_url IS NOT NULL), 0)
+		FROM resume_requests
+		WHERE user_id = $1
+	`
+
+	var usage domain.QuotaUsage
+	err := r.db.QueryRow(query, userID, dayStart, domain.StatusFailed).Scan(&usage.RequestsToday, &usage.StoredBytes)
+	if err != nil {
+		return domain.QuotaUsage{}, fmt.Errorf("error al calcular consumo del usuario: %w", err)
+	}
+
+	return usage, nil
+}
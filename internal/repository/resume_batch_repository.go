@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"resume-backend-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ResumeBatchRepository struct {
+	db DBTX
+}
+
+func NewResumeBatchRepository(db *sql.DB) *ResumeBatchRepository {
+	return &ResumeBatchRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *ResumeBatchRepository) WithTx(tx *sql.Tx) *ResumeBatchRepository {
+	return &ResumeBatchRepository{db: tx}
+}
+
+// BatchRequestItem es el estado resumido de una solicitud de un lote
+type BatchRequestItem struct {
+	RequestID        uuid.UUID
+	OriginalFilename string
+	Status           domain.ResumeRequestStatus
+	ErrorCode        sql.NullString
+	ErrorMessage     sql.NullString
+	CreatedAt        time.Time
+	CompletedAt      *time.Time
+}
+
+// Create guarda un lote
+func (r *ResumeBatchRepository) Create(batch *domain.ResumeBatch) error {
+	rejected, err := json.Marshal(batch.Rejected)
+	if err != nil {
+		return fmt.Errorf("error al serializar archivos rechazados: %w", err)
+	}
+
+	query := `
+		INSERT INTO resume_batches (batch_id, user_id, source, total_files, rejected, language, instructions, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.Exec(
+		query,
+		batch.BatchID,
+		batch.UserID,
+		batch.Source,
+		batch.TotalFiles,
+		rejected,
+		batch.Language,
+		batch.Instructions,
+		batch.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error al crear lote: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRejected reemplaza la lista de archivos rechazados del lote
+func (r *ResumeBatchRepository) UpdateRejected(batchID uuid.UUID, rejected []domain.BatchRejection) error {
+	data, err := json.Marshal(rejected)
+	if err != nil {
+		return fmt.Errorf("error al serializar archivos rechazados: %w", err)
+	}
+
+	if _, err := r.db.Exec(`UPDATE resume_batches SET rejected = $2 WHERE batch_id = $1`, batchID, data); err != nil {
+		return fmt.Errorf("error al actualizar archivos rechazados del lote: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID busca un lote por su batch_id
+func (r *ResumeBatchRepository) FindByID(batchID uuid.UUID) (*domain.ResumeBatch, error) {
+	query := `
+		SELECT batch_id, user_id, source, total_files, rejected, language, instructions, created_at
+		FROM resume_batches
+		WHERE batch_id = $1
+	`
+
+	var batch domain.ResumeBatch
+	var rejected []byte
+	var instructions sql.NullString
+	err := r.db.QueryRow(query, batchID).Scan(
+		&batch.BatchID,
+		&batch.UserID,
+		&batch.Source,
+		&batch.TotalFiles,
+		&rejected,
+		&batch.Language,
+		&instructions,
+		&batch.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("lote no encontrado: %s", batchID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar lote: %w", err)
+	}
+
+	if err := json.Unmarshal(rejected, &batch.Rejected); err != nil {
+		return nil, fmt.Errorf("error al leer archivos rechazados del lote: %w", err)
+	}
+	batch.Instructions = instructions.String
+
+	return &batch, nil
+}
+
+// GetProgress cuenta las solicitudes del lote por estado
+func (r *ResumeBatchRepository) GetProgress(batchID uuid.UUID) (domain.BatchProgress, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM resume_requests
+		WHERE batch_id = $1
+		GROUP BY status
+	`
+
+	rows, err := r.db.Query(query, batchID)
+	if err != nil {
+		return domain.BatchProgress{}, fmt.Errorf("error al obtener progreso del lote: %w", err)
+	}
+	defer rows.Close()
+
+	progress := domain.BatchProgress{StatusCounts: map[domain.ResumeRequestStatus]int{}}
+	for rows.Next() {
+		var status domain.ResumeRequestStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return domain.BatchProgress{}, fmt.Errorf("error al escanear progreso del lote: %w", err)
+		}
+		progress.StatusCounts[status] = count
+	}
+	if err := rows.Err(); err != nil {
+		return domain.BatchProgress{}, fmt.Errorf("error al obtener progreso del lote: %w", err)
+	}
+
+	return progress, nil
+}
+
+// ListRequests obtiene las solicitudes del lote en el orden en que se crearon
+func (r *ResumeBatchRepository) ListRequests(batchID uuid.UUID) ([]BatchRequestItem, error) {
+	query := `
+		SELECT request_id, original_filename, status, error_code, error_message, created_at, completed_at
+		FROM resume_requests
+		WHERE batch_id = $1
+		ORDER BY created_at, request_id
+	`
+
+	rows, err := r.db.Query(query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener solicitudes del lote: %w", err)
+	}
+	defer rows.Close()
+
+	var items []BatchRequestItem
+	for rows.Next() {
+		var item BatchRequestItem
+		err := rows.Scan(
+			&item.RequestID,
+			&item.OriginalFilename,
+			&item.Status,
+			&item.ErrorCode,
+			&item.ErrorMessage,
+			&item.CreatedAt,
+			&item.CompletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear solicitud del lote: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al obtener solicitudes del lote: %w", err)
+	}
+
+	return items, nil
+}
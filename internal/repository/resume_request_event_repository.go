@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"resume-backend-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ResumeRequestEventRepository lee el historial de estados. Los eventos se escriben
+// desde ResumeRequestRepository, en la misma sentencia que cada transición.
+type ResumeRequestEventRepository struct {
+	db DBTX
+}
+
+func NewResumeRequestEventRepository(db *sql.DB) *ResumeRequestEventRepository {
+	return &ResumeRequestEventRepository{db: db}
+}
+
+// FindByRequestID obtiene el historial de una solicitud en orden cronológico
+func (r *ResumeRequestEventRepository) FindByRequestID(requestID uuid.UUID) ([]*domain.ResumeRequestEvent, error) {
+	query := `
+		SELECT id, request_id, from_status, to_status, actor, detail, created_at
+		FROM resume_request_events
+		WHERE request_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(query, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener historial de la solicitud: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.ResumeRequestEvent
+	for rows.Next() {
+		var event domain.ResumeRequestEvent
+		var fromStatus sql.NullString
+		var detail []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.RequestID,
+			&fromStatus,
+			&event.ToStatus,
+			&event.Actor,
+			&detail,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error al escanear evento: %w", err)
+		}
+
+		if fromStatus.Valid {
+			status := domain.ResumeRequestStatus(fromStatus.String)
+			event.FromStatus = &status
+		}
+		if len(detail) > 0 {
+			event.Detail = detail
+		}
+
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
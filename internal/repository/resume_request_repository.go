@@ -2,28 +2,43 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"resume-backend-service/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type ResumeRequestRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewResumeRequestRepository(db *sql.DB) *ResumeRequestRepository {
 	return &ResumeRequestRepository{db: db}
 }
 
-// Create crea una nueva solicitud de procesamiento
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *ResumeRequestRepository) WithTx(tx *sql.Tx) *ResumeRequestRepository {
+	return &ResumeRequestRepository{db: tx}
+}
+
+// Create crea una nueva solicitud de procesamiento y registra el evento inicial del
+// historial y del outbox
 func (r *ResumeRequestRepository) Create(request *domain.ResumeRequest) error {
 	query := `
-		INSERT INTO resume_requests (
-			request_id, user_id, original_filename, original_file_type,
-			file_size_bytes, language, instructions, status, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`
+		WITH inserted AS (
+			INSERT INTO resume_requests (
+				request_id, user_id, original_filename, original_file_type, detected_mime_type,
+				text_encoding, page_count, pdf_producer, file_size_bytes, language, instructions,
+				force_reprocess, batch_id, status, created_at
+			) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, 0), NULLIF($8, ''), $9, $10, $11, $12, $13, $14, $15)
+			RETURNING *
+		), history AS (
+			INSERT INTO resume_request_events (request_id, from_status, to_status, actor, created_at)
+			SELECT request_id, NULL, status, $16, created_at FROM inserted
+		)` + fmt.Sprintf(requestOutboxEventSQL, "inserted")
 
 	_, err := r.db.Exec(
 		query,
@@ -31,11 +46,18 @@ func (r *ResumeRequestRepository) Create(request *domain.ResumeRequest) error {
 		request.UserID,
 		request.OriginalFilename,
 		request.OriginalFileType,
+		request.DetectedMIMEType,
+		request.TextEncoding,
+		request.PageCount,
+		request.PDFProducer,
 		request.FileSizeBytes,
 		request.Language,
 		request.Instructions,
+		request.ForceReprocess,
+		request.BatchID,
 		request.Status,
 		request.CreatedAt,
+		domain.ActorUser,
 	)
 
 	if err != nil {
@@ -47,23 +69,46 @@ func (r *ResumeRequestRepository) Create(request *domain.ResumeRequest) error {
 
 // FindByRequestID busca una solicitud por su request_id
 func (r *ResumeRequestRepository) FindByRequestID(requestID uuid.UUID) (*domain.ResumeRequest, error) {
+	return r.findByRequestID(requestID, "")
+}
+
+// FindByRequestIDForUpdate busca una solicitud y bloquea su fila hasta el fin de la
+// transacción. Solo tiene sentido en un repositorio obtenido con WithTx.
+func (r *ResumeRequestRepository) FindByRequestIDForUpdate(requestID uuid.UUID) (*domain.ResumeRequest, error) {
+	return r.findByRequestID(requestID, "FOR UPDATE")
+}
+
+func (r *ResumeRequestRepository) findByRequestID(requestID uuid.UUID, lockClause string) (*domain.ResumeRequest, error) {
 	query := `
-		SELECT request_id, user_id, original_filename, original_file_type,
-		       file_size_bytes, language, instructions, s3_input_url, s3_output_url,
-		       status, processing_time_ms, error_message, created_at, uploaded_at, completed_at
+		SELECT request_id, user_id, original_filename, original_file_type, detected_mime_type,
+		       text_encoding, page_count, pdf_producer, content_sha256, force_reprocess,
+		       cloned_from_request_id, batch_id, file_size_bytes, language, instructions,
+		       s3_input_url, s3_output_url,
+		       status, processing_time_ms, error_message, error_code, error_stage, error_retryable,
+		       created_at, uploaded_at, completed_at
 		FROM resume_requests
 		WHERE request_id = $1
-	`
+	` + lockClause
 
 	var request domain.ResumeRequest
-	var s3InputURL, s3OutputURL, errorMessage sql.NullString
-	var processingTimeMs sql.NullInt64
+	var detectedMIMEType, textEncoding, pdfProducer, contentSHA256, s3InputURL, s3OutputURL, errorMessage, errorCode, errorStage sql.NullString
+	var clonedFrom, batchID uuid.NullUUID
+	var pageCount, processingTimeMs sql.NullInt64
+	var errorRetryable sql.NullBool
 	
 	err := r.db.QueryRow(query, requestID).Scan(
 		&request.RequestID,
 		&request.UserID,
 		&request.OriginalFilename,
 		&request.OriginalFileType,
+		&detectedMIMEType,
+		&textEncoding,
+		&pageCount,
+		&pdfProducer,
+		&contentSHA256,
+		&request.ForceReprocess,
+		&clonedFrom,
+		&batchID,
 		&request.FileSizeBytes,
 		&request.Language,
 		&request.Instructions,
@@ -72,6 +117,9 @@ func (r *ResumeRequestRepository) FindByRequestID(requestID uuid.UUID) (*domain.
 		&request.Status,
 		&processingTimeMs,
 		&errorMessage,
+		&errorCode,
+		&errorStage,
+		&errorRetryable,
 		&request.CreatedAt,
 		&request.UploadedAt,
 		&request.CompletedAt,
@@ -87,6 +135,20 @@ func (r *ResumeRequestRepository) FindByRequestID(requestID uuid.UUID) (*domain.
 		if errorMessage.Valid {
 			request.ErrorMessage = errorMessage.String
 		}
+		request.DetectedMIMEType = detectedMIMEType.String
+		request.TextEncoding = textEncoding.String
+		request.PageCount = int(pageCount.Int64)
+		request.PDFProducer = pdfProducer.String
+		request.ContentSHA256 = contentSHA256.String
+		if clonedFrom.Valid {
+			request.ClonedFrom = &clonedFrom.UUID
+		}
+		if batchID.Valid {
+			request.BatchID = &batchID.UUID
+		}
+		request.ErrorCode = errorCode.String
+		request.ErrorStage = errorStage.String
+		request.ErrorRetryable = errorRetryable.Bool
 		if processingTimeMs.Valid {
 			request.ProcessingTimeMs = processingTimeMs.Int64
 		}
@@ -106,9 +168,12 @@ func (r *ResumeRequestRepository) FindByRequestID(requestID uuid.UUID) (*domain.
 // FindByUserID busca todas las solicitudes de un usuario
 func (r *ResumeRequestRepository) FindByUserID(userID string) ([]*domain.ResumeRequest, error) {
 	query := `
-		SELECT request_id, user_id, original_filename, original_file_type,
-		       file_size_bytes, language, instructions, s3_input_url, s3_output_url,
-		       status, processing_time_ms, error_message, created_at, uploaded_at, completed_at
+		SELECT request_id, user_id, original_filename, original_file_type, detected_mime_type,
+		       text_encoding, page_count, pdf_producer, content_sha256, force_reprocess,
+		       cloned_from_request_id, batch_id, file_size_bytes, language, instructions,
+		       s3_input_url, s3_output_url,
+		       status, processing_time_ms, error_message, error_code, error_stage, error_retryable,
+		       created_at, uploaded_at, completed_at
 		FROM resume_requests
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -123,14 +188,24 @@ func (r *ResumeRequestRepository) FindByUserID(userID string) ([]*domain.ResumeR
 	var requests []*domain.ResumeRequest
 	for rows.Next() {
 		var request domain.ResumeRequest
-		var s3InputURL, s3OutputURL, errorMessage sql.NullString
-		var processingTimeMs sql.NullInt64
+		var detectedMIMEType, textEncoding, pdfProducer, contentSHA256, s3InputURL, s3OutputURL, errorMessage, errorCode, errorStage sql.NullString
+		var clonedFrom, batchID uuid.NullUUID
+		var pageCount, processingTimeMs sql.NullInt64
+		var errorRetryable sql.NullBool
 		
 		err := rows.Scan(
 			&request.RequestID,
 			&request.UserID,
 			&request.OriginalFilename,
 			&request.OriginalFileType,
+			&detectedMIMEType,
+			&textEncoding,
+			&pageCount,
+			&pdfProducer,
+			&contentSHA256,
+			&request.ForceReprocess,
+			&clonedFrom,
+			&batchID,
 			&request.FileSizeBytes,
 			&request.Language,
 			&request.Instructions,
@@ -139,6 +214,9 @@ func (r *ResumeRequestRepository) FindByUserID(userID string) ([]*domain.ResumeR
 			&request.Status,
 			&processingTimeMs,
 			&errorMessage,
+			&errorCode,
+			&errorStage,
+			&errorRetryable,
 			&request.CreatedAt,
 			&request.UploadedAt,
 			&request.CompletedAt,
@@ -156,6 +234,20 @@ func (r *ResumeRequestRepository) FindByUserID(userID string) ([]*domain.ResumeR
 		if errorMessage.Valid {
 			request.ErrorMessage = errorMessage.String
 		}
+		request.DetectedMIMEType = detectedMIMEType.String
+		request.TextEncoding = textEncoding.String
+		request.PageCount = int(pageCount.Int64)
+		request.PDFProducer = pdfProducer.String
+		request.ContentSHA256 = contentSHA256.String
+		if clonedFrom.Valid {
+			request.ClonedFrom = &clonedFrom.UUID
+		}
+		if batchID.Valid {
+			request.BatchID = &batchID.UUID
+		}
+		request.ErrorCode = errorCode.String
+		request.ErrorStage = errorStage.String
+		request.ErrorRetryable = errorRetryable.Bool
 		if processingTimeMs.Valid {
 			request.ProcessingTimeMs = processingTimeMs.Int64
 		}
@@ -166,63 +258,147 @@ func (r *ResumeRequestRepository) FindByUserID(userID string) ([]*domain.ResumeR
 	return requests, nil
 }
 
-// UpdateStatus actualiza el estado de una solicitud
-func (r *ResumeRequestRepository) UpdateStatus(requestID uuid.UUID, status domain.ResumeRequestStatus) error {
-	query := `UPDATE resume_requests SET status = $1 WHERE request_id = $2`
-
-	result, err := r.db.Exec(query, status, requestID)
+// SetContentSHA256 guarda el hash del PDF que se envía a procesar
+func (r *ResumeRequestRepository) SetContentSHA256(requestID uuid.UUID, contentSHA256 string) error {
+	_, err := r.db.Exec(`UPDATE resume_requests SET content_sha256 = $2 WHERE request_id = $1`, requestID, contentSHA256)
 	if err != nil {
-		return fmt.Errorf("error al actualizar estado: %w", err)
+		return fmt.Errorf("error al guardar hash del contenido: %w", err)
 	}
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// FindCompletedDuplicate busca la solicitud completada más reciente del usuario con el
+// mismo contenido, idioma e instrucciones, y con un CV procesado que tenga versión
+// activa. Retorna nil si no hay ninguna.
+func (r *ResumeRequestRepository) FindCompletedDuplicate(userID, contentSHA256, language, instructions string, excludeRequestID uuid.UUID) (*domain.ResumeRequest, error) {
+	query := `
+		SELECT rr.request_id
+		FROM resume_requests rr
+		JOIN processed_resumes pr ON pr.request_id = rr.request_id
+		WHERE rr.user_id = $1
+		  AND rr.content_sha256 = $2
+		  AND rr.status = $3
+		  AND rr.language = $4
+		  AND COALESCE(rr.instructions, '') = $5
+		  AND rr.request_id <> $6
+		  AND pr.active_version_id IS NOT NULL
+		ORDER BY rr.completed_at DESC
+		LIMIT 1
+	`
+
+	var requestID uuid.UUID
+	err := r.db.QueryRow(query, userID, contentSHA256, domain.StatusCompleted, language, instructions, excludeRequestID).Scan(&requestID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+		return nil, fmt.Errorf("error al buscar solicitud con el mismo contenido: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("solicitud no encontrada: %s", requestID)
-	}
+	return r.FindByRequestID(requestID)
+}
 
+// UpdateStatus actualiza el estado de una solicitud respetando las transiciones permitidas
+func (r *ResumeRequestRepository) UpdateStatus(requestID uuid.UUID, status domain.ResumeRequestStatus, actor domain.EventActor) error {
+	if err := r.execTransition(requestID, status, actor, nil, ""); err != nil {
+		return fmt.Errorf("error al actualizar estado: %w", err)
+	}
 	return nil
 }
 
 // MarkAsUploaded marca la solicitud como subida a S3
-func (r *ResumeRequestRepository) MarkAsUploaded(requestID uuid.UUID, s3InputURL string) error {
-	query := `
-		UPDATE resume_requests
-		SET status = $1, s3_input_url = $2, uploaded_at = NOW()
-		WHERE request_id = $3
-	`
+func (r *ResumeRequestRepository) MarkAsUploaded(requestID uuid.UUID, s3InputURL string, actor domain.EventActor) error {
+	setClause := `, s3_input_url = $6, uploaded_at = NOW()`
+	detail := map[string]any{"s3_input_url": s3InputURL}
 
-	result, err := r.db.Exec(query, domain.StatusUploaded, s3InputURL, requestID)
-	if err != nil {
+	if err := r.execTransition(requestID, domain.StatusUploaded, actor, detail, setClause, s3InputURL); err != nil {
 		return fmt.Errorf("error al marcar como subido: %w", err)
 	}
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+// MarkAsProcessing marca la solicitud como en procesamiento (el procesador la recibió)
+func (r *ResumeRequestRepository) MarkAsProcessing(requestID uuid.UUID, actor domain.EventActor) error {
+	if err := r.execTransition(requestID, domain.StatusProcessing, actor, nil, ""); err != nil {
+		return fmt.Errorf("error al marcar como en procesamiento: %w", err)
 	}
+	return nil
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("solicitud no encontrada: %s", requestID)
+// MarkAsCompleted marca la solicitud como completada
+func (r *ResumeRequestRepository) MarkAsCompleted(requestID uuid.UUID, s3OutputURL string, processingTimeMs int64, actor domain.EventActor) error {
+	setClause := `, s3_output_url = $6, processing_time_ms = $7, completed_at = NOW()`
+	detail := map[string]any{"s3_output_url": s3OutputURL, "processing_time_ms": processingTimeMs}
+
+	if err := r.execTransition(requestID, domain.StatusCompleted, actor, detail, setClause, s3OutputURL, processingTimeMs); err != nil {
+		return fmt.Errorf("error al marcar como completado: %w", err)
+	}
+	return nil
+}
+
+// MarkAsCloned marca la solicitud como completada con el resultado copiado de
+// sourceRequestID (mismo contenido ya procesado), sin pasar por Lambda
+func (r *ResumeRequestRepository) MarkAsCloned(requestID, sourceRequestID uuid.UUID, s3OutputURL string, actor domain.EventActor) error {
+	setClause := `, s3_output_url = NULLIF($6, ''), cloned_from_request_id = $7, completed_at = NOW()`
+	detail := map[string]any{"cloned_from_request_id": sourceRequestID, "s3_output_url": s3OutputURL}
+
+	if err := r.execTransition(requestID, domain.StatusCompleted, actor, detail, setClause, s3OutputURL, sourceRequestID); err != nil {
+		return fmt.Errorf("error al marcar como copiado: %w", err)
 	}
+	return nil
+}
 
+// MarkAsFailed marca la solicitud como fallida guardando el detalle del error.
+// El código y la etapa se recortan al ancho de sus columnas.
+func (r *ResumeRequestRepository) MarkAsFailed(requestID uuid.UUID, processingErr domain.ProcessingError, actor domain.EventActor) error {
+	processingErr = processingErr.Truncated()
+	setClause := `, error_message = $6, error_code = $7, error_stage = $8, error_retryable = $9, completed_at = NOW()`
+
+	err := r.execTransition(
+		requestID,
+		domain.StatusFailed,
+		actor,
+		processingErr,
+		setClause,
+		processingErr.Message,
+		processingErr.Code,
+		sql.NullString{String: processingErr.Stage, Valid: processingErr.Stage != ""},
+		processingErr.Retryable,
+	)
+	if err != nil {
+		return fmt.Errorf("error al marcar como fallido: %w", err)
+	}
 	return nil
 }
 
-// MarkAsCompleted marca la solicitud como completada
-func (r *ResumeRequestRepository) MarkAsCompleted(requestID uuid.UUID, s3OutputURL string, processingTimeMs int64) error {
+// execTransition ejecuta un UPDATE condicionado a que el estado actual permita pasar a `to`
+// y, en la misma sentencia, registra el evento en el historial y en el outbox. Los placeholders $1 (nuevo
+// estado), $2 (request_id), $3 (estados de origen), $4 (actor) y $5 (detalle) están
+// reservados; setClause usa desde $6 en adelante. Si la fila existe pero su estado no
+// permite la transición, retorna un *domain.StatusTransitionError.
+func (r *ResumeRequestRepository) execTransition(requestID uuid.UUID, to domain.ResumeRequestStatus, actor domain.EventActor, detail any, setClause string, args ...any) error {
 	query := `
-		UPDATE resume_requests
-		SET status = $1, s3_output_url = $2, processing_time_ms = $3, completed_at = NOW()
-		WHERE request_id = $4
-	`
+		WITH previous AS (
+			SELECT request_id, status FROM resume_requests WHERE request_id = $2 FOR UPDATE
+		), updated AS (
+			UPDATE resume_requests SET status = $1` + setClause + `
+			WHERE request_id = $2 AND status = ANY($3)
+			RETURNING *
+		), history AS (
+			INSERT INTO resume_request_events (request_id, from_status, to_status, actor, detail)
+			SELECT updated.request_id, previous.status, $1, $4, $5
+			FROM updated JOIN previous ON previous.request_id = updated.request_id
+		)` + fmt.Sprintf(requestOutboxEventSQL, "updated")
+
+	detailJSON, err := marshalEventDetail(detail)
+	if err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(query, domain.StatusCompleted, s3OutputURL, processingTimeMs, requestID)
+	params := append([]any{to, requestID, statusArray(domain.SourceStatuses(to)), actor, detailJSON}, args...)
+	result, err := r.db.Exec(query, params...)
 	if err != nil {
-		return fmt.Errorf("error al marcar como completado: %w", err)
+		return err
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -230,34 +406,117 @@ func (r *ResumeRequestRepository) MarkAsCompleted(requestID uuid.UUID, s3OutputU
 		return fmt.Errorf("error al verificar filas afectadas: %w", err)
 	}
 
-	if rowsAffected == 0 {
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Distinguir entre solicitud inexistente y transición no permitida
+	var current domain.ResumeRequestStatus
+	err = r.db.QueryRow(`SELECT status FROM resume_requests WHERE request_id = $1`, requestID).Scan(&current)
+	if err == sql.ErrNoRows {
 		return fmt.Errorf("solicitud no encontrada: %s", requestID)
 	}
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return &domain.StatusTransitionError{RequestID: requestID, From: current, To: to}
 }
 
-// MarkAsFailed marca la solicitud como fallida
-func (r *ResumeRequestRepository) MarkAsFailed(requestID uuid.UUID, errorMessage string) error {
-	query := `
-		UPDATE resume_requests
-		SET status = $1, error_message = $2, completed_at = NOW()
-		WHERE request_id = $3
-	`
+// marshalEventDetail serializa el detalle del evento (nil se guarda como NULL)
+func marshalEventDetail(detail any) (any, error) {
+	if detail == nil {
+		return nil, nil
+	}
 
-	result, err := r.db.Exec(query, domain.StatusFailed, errorMessage, requestID)
+	detailJSON, err := json.Marshal(detail)
 	if err != nil {
-		return fmt.Errorf("error al marcar como fallido: %w", err)
+		return nil, fmt.Errorf("error al serializar detalle del evento: %w", err)
 	}
+	return detailJSON, nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// statusArray convierte una lista de estados al tipo de arreglo de PostgreSQL
+func statusArray(statuses []domain.ResumeRequestStatus) any {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	return pq.Array(values)
+}
+
+// FailStaleRequests marca como fallidas (por timeout) hasta limit solicitudes que siguen en
+// pending/uploaded/processing después de timeout, registrando el evento del reaper. Las filas
+// bloqueadas por un callback en curso se saltan (SKIP LOCKED), así que nunca se sobrescribe
+// una solicitud que se está completando en ese momento. Las solicitudes pending con un
+// trabajo de ingesta en cola o en curso tampoco se marcan: la cola las resuelve (con sus
+// reintentos) o las marca como fallidas al descartar el trabajo.
+func (r *ResumeRequestRepository) FailStaleRequests(timeout time.Duration, limit int) ([]*domain.ResumeRequest, error) {
+	query := `
+		WITH stale AS (
+			SELECT request_id, status
+			FROM resume_requests
+			WHERE status = ANY($6)
+			  AND COALESCE(uploaded_at, created_at) < NOW() - make_interval(secs => $7)
+			  AND NOT (status = $10 AND EXISTS (
+			      SELECT 1
+			      FROM ingestion_jobs j
+			      WHERE j.request_id = resume_requests.request_id
+			        AND j.status IN ($11, $12)
+			  ))
+			ORDER BY created_at
+			LIMIT $8
+			FOR UPDATE SKIP LOCKED
+		), updated AS (
+			UPDATE resume_requests rr
+			SET status = $1,
+			    error_message = $2,
+			    error_code = $3,
+			    error_stage = CASE WHEN stale.status = 'pending' THEN $4 ELSE $5 END,
+			    error_retryable = TRUE,
+			    completed_at = NOW()
+			FROM stale
+			WHERE rr.request_id = stale.request_id
+			RETURNING rr.request_id, rr.user_id, rr.status, stale.status AS from_status,
+			          rr.error_code, rr.error_message, rr.error_stage, rr.error_retryable
+		), events AS (
+			INSERT INTO resume_request_events (request_id, from_status, to_status, actor, detail)
+			SELECT request_id, from_status, $1, $9,
+			       jsonb_build_object('code', error_code, 'message', error_message, 'stage', error_stage, 'retryable', TRUE)
+			FROM updated
+		), outbox AS (` + fmt.Sprintf(requestOutboxEventSQL, "updated") + `
+		)
+		SELECT request_id, user_id, error_code, error_message, error_stage FROM updated
+	`
+
+	rows, err := r.db.Query(
+		query,
+		domain.StatusFailed,
+		"No se recibió el resultado del procesamiento a tiempo",
+		domain.ErrorCodeTimeout,
+		domain.StageUpload,
+		domain.StageExtraction,
+		statusArray(domain.SourceStatuses(domain.StatusFailed)),
+		timeout.Seconds(),
+		limit,
+		domain.ActorReaper,
+		domain.StatusPending,
+		domain.IngestionJobQueued,
+		domain.IngestionJobRunning,
+	)
 	if err != nil {
-		return fmt.Errorf("error al verificar filas afectadas: %w", err)
+		return nil, fmt.Errorf("error al marcar solicitudes vencidas: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("solicitud no encontrada: %s", requestID)
+	var requests []*domain.ResumeRequest
+	for rows.Next() {
+		request := &domain.ResumeRequest{Status: domain.StatusFailed, ErrorRetryable: true}
+		if err := rows.Scan(&request.RequestID, &request.UserID, &request.ErrorCode, &request.ErrorMessage, &request.ErrorStage); err != nil {
+			return nil, fmt.Errorf("error al escanear solicitud vencida: %w", err)
+		}
+		requests = append(requests, request)
 	}
 
-	return nil
+	return requests, rows.Err()
 }
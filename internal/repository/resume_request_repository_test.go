@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"resume-backend-service/internal/domain"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// openTestDB abre la base de datos de TEST_DATABASE_URL (con las migraciones aplicadas).
+// Sin esa variable los tests de integración se omiten.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL no configurada")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("no se pudo conectar a TEST_DATABASE_URL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// requestInStatus crea, dentro de tx, una solicitud y la lleva hasta status por
+// transiciones permitidas
+func requestInStatus(t *testing.T, repo *ResumeRequestRepository, status domain.ResumeRequestStatus) *domain.ResumeRequest {
+	t.Helper()
+
+	request := domain.NewResumeRequest("test-state-machine", "cv.pdf", "pdf", 1024, "esp", "")
+	if err := repo.Create(request); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	path := map[domain.ResumeRequestStatus][]domain.ResumeRequestStatus{
+		domain.StatusUploaded:   {domain.StatusUploaded},
+		domain.StatusProcessing: {domain.StatusProcessing},
+		domain.StatusCompleted:  {domain.StatusProcessing, domain.StatusCompleted},
+		domain.StatusFailed:     {domain.StatusFailed},
+	}
+	for _, next := range path[status] {
+		if err := repo.UpdateStatus(request.RequestID, next, domain.ActorSystem); err != nil {
+			t.Fatalf("llevar a %s: %v", next, err)
+		}
+	}
+	return request
+}
+
+var allStatuses = []domain.ResumeRequestStatus{
+	domain.StatusPending, domain.StatusUploaded, domain.StatusProcessing, domain.StatusCompleted, domain.StatusFailed,
+}
+
+func TestExecTransitionAppliesOnlyAllowedTransitions(t *testing.T) {
+	db := openTestDB(t)
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			if from == to {
+				continue
+			}
+			t.Run(string(from)+"→"+string(to), func(t *testing.T) {
+				tx, err := db.Begin()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer tx.Rollback()
+				repo := NewResumeRequestRepository(db).WithTx(tx)
+
+				request := requestInStatus(t, repo, from)
+				err = repo.UpdateStatus(request.RequestID, to, domain.ActorSystem)
+
+				var current domain.ResumeRequestStatus
+				if scanErr := tx.QueryRow(`SELECT status FROM resume_requests WHERE request_id = $1`, request.RequestID).Scan(&current); scanErr != nil {
+					t.Fatal(scanErr)
+				}
+
+				if from.CanTransitionTo(to) {
+					if err != nil || current != to {
+						t.Errorf("transición permitida: err = %v, estado = %s", err, current)
+					}
+					return
+				}
+
+				var transitionErr *domain.StatusTransitionError
+				if !errors.As(err, &transitionErr) || transitionErr.From != from {
+					t.Errorf("se esperaba StatusTransitionError desde %s, se obtuvo %v", from, err)
+				}
+				if current != from {
+					t.Errorf("el estado no debe cambiar: %s", current)
+				}
+			})
+		}
+	}
+}
+
+func TestTriggerRejectsForbiddenTransition(t *testing.T) {
+	db := openTestDB(t)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	request := requestInStatus(t, NewResumeRequestRepository(db).WithTx(tx), domain.StatusCompleted)
+
+	// Un UPDATE directo (sin la condición del CTE) debe frenarlo el trigger de la migración 005
+	_, err = tx.Exec(`UPDATE resume_requests SET status = 'pending' WHERE request_id = $1`, request.RequestID)
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code.Name() != "check_violation" || !strings.Contains(pqErr.Message, "Transición de estado inválida") {
+		t.Fatalf("se esperaba check_violation del trigger, se obtuvo %v", err)
+	}
+}
@@ -10,14 +10,20 @@ import (
 )
 
 type ResumeVersionRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewResumeVersionRepository(db *sql.DB) *ResumeVersionRepository {
 	return &ResumeVersionRepository{db: db}
 }
 
-// CreateVersion crea una nueva versión usando la función SQL
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *ResumeVersionRepository) WithTx(tx *sql.Tx) *ResumeVersionRepository {
+	return &ResumeVersionRepository{db: tx}
+}
+
+// CreateVersion crea una nueva versión usando la función SQL y registra el evento
+// version.created en el outbox
 func (r *ResumeVersionRepository) CreateVersion(requestID uuid.UUID, userID string, cvData *dto.CVProcessedData, versionName, createdBy string) (int64, error) {
 	structuredDataBytes, err := json.Marshal(cvData)
 	if err != nil {
@@ -25,20 +31,40 @@ func (r *ResumeVersionRepository) CreateVersion(requestID uuid.UUID, userID stri
 	}
 
 	var versionID int64
-	query := `SELECT create_resume_version($1, $2, $3, $4, $5)`
+	query := `
+		WITH created AS (
+			SELECT create_resume_version($1, $2, $3, $4, $5) AS version_id
+		), outbox AS (
+			INSERT INTO outbox_events (request_id, user_id, event_type, payload)
+			SELECT $1, $2, $6,
+			       jsonb_build_object('request_id', $1::uuid, 'version_id', version_id, 'created_by', $5::text)
+			FROM created
+		)
+		SELECT version_id FROM created`
 	
 	err = r.db.QueryRow(query, requestID, userID, structuredDataBytes, 
-		sql.NullString{String: versionName, Valid: versionName != ""}, createdBy).Scan(&versionID)
+		sql.NullString{String: versionName, Valid: versionName != ""}, createdBy,
+		domain.EventVersionCreated).Scan(&versionID)
 	
 	return versionID, err
 }
 
-// ActivateVersion activa una versión específica
+// ActivateVersion activa una versión específica y registra el evento version.activated
 func (r *ResumeVersionRepository) ActivateVersion(requestID uuid.UUID, versionID int64) error {
-	query := `SELECT activate_resume_version($1, $2)`
+	query := `
+		WITH activated AS (
+			SELECT activate_resume_version($1, $2) AS success
+		), outbox AS (
+			INSERT INTO outbox_events (request_id, user_id, event_type, payload)
+			SELECT v.request_id, v.user_id, $3,
+			       jsonb_build_object('request_id', v.request_id, 'version_id', v.id)
+			FROM activated, resume_versions v
+			WHERE activated.success AND v.id = $2
+		)
+		SELECT success FROM activated`
 	var success bool
 	
-	err := r.db.QueryRow(query, requestID, versionID).Scan(&success)
+	err := r.db.QueryRow(query, requestID, versionID, domain.EventVersionActivated).Scan(&success)
 	if err != nil {
 		return err
 	}
@@ -116,12 +142,22 @@ func (r *ResumeVersionRepository) GetVersionByID(versionID int64) (*domain.Resum
 	return version, nil
 }
 
-// SoftDeleteVersion marca una versión como eliminada
+// SoftDeleteVersion marca una versión como eliminada y registra el evento version.deleted
 func (r *ResumeVersionRepository) SoftDeleteVersion(versionID int64, userID string) error {
-	query := `SELECT soft_delete_resume_version($1, $2)`
+	query := `
+		WITH deleted AS (
+			SELECT soft_delete_resume_version($1, $2) AS success
+		), outbox AS (
+			INSERT INTO outbox_events (request_id, user_id, event_type, payload)
+			SELECT v.request_id, v.user_id, $3,
+			       jsonb_build_object('request_id', v.request_id, 'version_id', v.id)
+			FROM deleted, resume_versions v
+			WHERE deleted.success AND v.id = $1
+		)
+		SELECT success FROM deleted`
 	var success bool
 	
-	err := r.db.QueryRow(query, versionID, userID).Scan(&success)
+	err := r.db.QueryRow(query, versionID, userID, domain.EventVersionDeleted).Scan(&success)
 	if err != nil {
 		return err
 	}
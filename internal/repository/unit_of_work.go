@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// DBTX es la interfaz común de *sql.DB y *sql.Tx. Los repositorios la usan para
+// poder operar tanto sobre la conexión como dentro de una transacción.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// UnitOfWork agrupa operaciones de varios repositorios en una única transacción
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+func NewUnitOfWork(db *sql.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do ejecuta fn dentro de una transacción. Si fn retorna error (o entra en pánico)
+// se hace rollback de todo; si no, se confirma. Los repositorios se enlazan a la
+// transacción con su método WithTx.
+func (u *UnitOfWork) Do(fn func(tx *sql.Tx) error) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error al iniciar transacción: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("⚠️  Error al hacer rollback: %v", rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al confirmar transacción: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"resume-backend-service/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db DBTX
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// WithTx retorna una copia del repositorio que opera dentro de la transacción
+func (r *WebhookRepository) WithTx(tx *sql.Tx) *WebhookRepository {
+	return &WebhookRepository{db: tx}
+}
+
+// CreateSubscription guarda una nueva suscripción
+func (r *WebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, user_id, url, event_types, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		subscription.ID,
+		subscription.UserID,
+		subscription.URL,
+		pq.Array(subscription.EventTypes),
+		subscription.Secret,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error al crear suscripción de webhook: %w", err)
+	}
+
+	return nil
+}
+
+// FindSubscriptionsByUserID obtiene las suscripciones de un usuario, de la más reciente a la más antigua
+func (r *WebhookRepository) FindSubscriptionsByUserID(userID string) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, user_id, url, event_types, secret, active, consecutive_failures,
+		       disabled_at, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener suscripciones de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// FindSubscriptionByID busca una suscripción por su ID
+func (r *WebhookRepository) FindSubscriptionByID(id uuid.UUID) (*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, user_id, url, event_types, secret, active, consecutive_failures,
+		       disabled_at, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanWebhookSubscription(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("suscripción no encontrada")
+	}
+	return subscription, err
+}
+
+// DeleteSubscription elimina una suscripción del usuario junto con su registro de entregas.
+// Retorna sql.ErrNoRows si no existe o pertenece a otro usuario.
+func (r *WebhookRepository) DeleteSubscription(id uuid.UUID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("error al eliminar suscripción de webhook: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// EnqueueEvent crea una entrega pendiente del evento para cada suscripción activa del
+// usuario que incluya el tipo de evento en su filtro. Retorna cuántas se crearon.
+func (r *WebhookRepository) EnqueueEvent(userID string, eventID uuid.UUID, eventType string, payload []byte) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT id, $2, $3, $4
+		FROM webhook_subscriptions
+		WHERE user_id = $1 AND active AND $3 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.Exec(query, userID, eventID, eventType, payload)
+	if err != nil {
+		return 0, fmt.Errorf("error al encolar evento de webhook: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ClaimDueDeliveries reserva hasta limit entregas pendientes cuyo próximo intento ya venció.
+// Cada entrega reservada cuenta un intento y queda postergada por lease, así otro worker
+// no la toma mientras se envía y se reintenta sola si este proceso se cae.
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = $1 AND d.next_attempt_at <= NOW() AND s.active
+			ORDER BY d.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $3)
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status,
+		          d.attempts, d.created_at, s.url, s.secret
+	`
+
+	rows, err := r.db.Query(query, domain.WebhookDeliveryPending, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error al reservar entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.Secret,
+		); err != nil {
+			return nil, fmt.Errorf("error al escanear entrega de webhook: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// MarkDeliverySucceeded marca la entrega como exitosa y reinicia el contador de
+// fallos consecutivos de la suscripción
+func (r *WebhookRepository) MarkDeliverySucceeded(deliveryID int64, responseStatus int) error {
+	query := `
+		WITH delivered AS (
+			UPDATE webhook_deliveries
+			SET status = $2, last_response_status = $3, last_error = NULL, delivered_at = NOW()
+			WHERE id = $1
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = 0, updated_at = NOW()
+		WHERE id IN (SELECT subscription_id FROM delivered)
+	`
+
+	if _, err := r.db.Exec(query, deliveryID, domain.WebhookDeliverySucceeded, responseStatus); err != nil {
+		return fmt.Errorf("error al marcar entrega de webhook como exitosa: %w", err)
+	}
+
+	return nil
+}
+
+// MarkDeliveryFailed registra un intento fallido. Si nextAttemptAt es nil la entrega
+// agotó sus reintentos y queda como failed. La suscripción suma un fallo consecutivo y
+// se desactiva al llegar a disableAfter; en ese caso retorna true.
+func (r *WebhookRepository) MarkDeliveryFailed(deliveryID int64, responseStatus *int, lastError string, nextAttemptAt *time.Time, disableAfter int) (bool, error) {
+	status := domain.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = domain.WebhookDeliveryFailed
+	}
+
+	query := `
+		WITH failed AS (
+			UPDATE webhook_deliveries
+			SET status = $2,
+			    last_response_status = $3,
+			    last_error = $4,
+			    next_attempt_at = COALESCE($5, next_attempt_at)
+			WHERE id = $1
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = consecutive_failures + 1,
+		    active = active AND consecutive_failures + 1 < $6,
+		    disabled_at = CASE
+		        WHEN active AND consecutive_failures + 1 >= $6 THEN NOW()
+		        ELSE disabled_at
+		    END,
+		    updated_at = NOW()
+		WHERE id IN (SELECT subscription_id FROM failed)
+		RETURNING active
+	`
+
+	var active bool
+	err := r.db.QueryRow(query, deliveryID, status, responseStatus, lastError, nextAttemptAt, disableAfter).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("error al registrar fallo de entrega de webhook: %w", err)
+	}
+
+	return !active, nil
+}
+
+// FindDeliveriesBySubscriptionID obtiene las últimas entregas de una suscripción
+func (r *WebhookRepository) FindDeliveriesBySubscriptionID(subscriptionID uuid.UUID, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, subscription_id, event_id, event_type, payload, status, attempts,
+		       next_attempt_at, last_response_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		var lastResponseStatus sql.NullInt64
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&lastResponseStatus,
+			&lastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("error al escanear entrega de webhook: %w", err)
+		}
+		if lastResponseStatus.Valid {
+			status := int(lastResponseStatus.Int64)
+			delivery.LastResponseStatus = &status
+		}
+		delivery.LastError = lastError.String
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// scanner abstrae *sql.Row y *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookSubscription(row scanner) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.UserID,
+		&subscription.URL,
+		pq.Array(&subscription.EventTypes),
+		&subscription.Secret,
+		&subscription.Active,
+		&subscription.ConsecutiveFailures,
+		&subscription.DisabledAt,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al escanear suscripción de webhook: %w", err)
+	}
+	return &subscription, nil
+}
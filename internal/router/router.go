@@ -2,48 +2,82 @@ package router
 
 import (
 	"database/sql"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/events"
 	"resume-backend-service/internal/handlers"
 	"resume-backend-service/internal/middleware"
 	"resume-backend-service/internal/repository"
 	"resume-backend-service/internal/services"
-	"resume-backend-service/pkg/client"
+	"resume-backend-service/pkg/storage"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupRoutes(app *fiber.App, db *sql.DB, presignedURLEndpoint string, authMiddleware *middleware.AuthMiddleware) {
-	// API v1
+// multipartOverheadBytes es el margen del body para los campos del formulario
+// (instructions, language) y los delimitadores multipart
+const multipartOverheadBytes = 1024 * 1024
+
+func SetupRoutes(app *fiber.App, db *sql.DB, store storage.Storage, ingestionSpoolDir, callbackReplayPolicy string, maxFileSize int64, maxPDFPages int, defaultQuota domain.QuotaLimits, batchLimits services.BatchLimits, eventBus *events.Bus, sseHeartbeat time.Duration, webhookAllowInsecureURLs bool, authMiddleware *middleware.AuthMiddleware, callbackMiddleware *middleware.CallbackSignatureMiddleware) {
+	// API v1 y rutas de CV Processor
 	api := app.Group("/api/v1")
+	resume := api.Group("/resume")
 
-	// Health routes (sin autenticación)
-	health := api.Group("/health")
-	healthHandler := handlers.NewHealthHandler()
-	health.Get("/", healthHandler.HandleHealthCheck)
+	// Límites del body: el de un archivo para todas las rutas y el de los archivos de un
+	// lote solo para /resume/batch, más el margen de los demás campos del formulario
+	fileBodyLimit := middleware.BodyLimit(maxFileSize+multipartOverheadBytes, services.FileTooLargeMessage(0, maxFileSize))
+	batchBodyLimit := middleware.BodyLimit(batchLimits.MaxUploadSize+multipartOverheadBytes, services.BatchTooLargeMessage(batchLimits.MaxUploadSize))
 
 	// Inicializar repositorios
 	resumeRequestRepo := repository.NewResumeRequestRepository(db)
 	processedResumeRepo := repository.NewProcessedResumeRepository(db)
 	resumeVersionRepo := repository.NewResumeVersionRepository(db)
-
-	// Inicializar clientes
-	presignedURLClient := client.NewPresignedURLClient(presignedURLEndpoint)
+	callbackDeliveryRepo := repository.NewCallbackDeliveryRepository(db)
+	resumeRequestEventRepo := repository.NewResumeRequestEventRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	ingestionJobRepo := repository.NewIngestionJobRepository(db)
+	quotaRepo := repository.NewQuotaRepository(db)
+	resumeBatchRepo := repository.NewResumeBatchRepository(db)
+	unitOfWork := repository.NewUnitOfWork(db)
 
 	// Inicializar servicios
-	resumeService := services.NewResumeService(presignedURLClient, resumeRequestRepo)
+	webhookService := services.NewWebhookService(webhookRepo, webhookAllowInsecureURLs)
+	quotaService := services.NewQuotaService(quotaRepo, defaultQuota, maxFileSize, maxPDFPages)
+	resumeService := services.NewResumeService(store, unitOfWork, resumeRequestRepo, ingestionJobRepo, processedResumeRepo, resumeVersionRepo, quotaService, ingestionSpoolDir)
+	batchService := services.NewBatchService(resumeService, unitOfWork, resumeBatchRepo, batchLimits)
+	resumeResultService := services.NewResumeResultService(unitOfWork, resumeRequestRepo, processedResumeRepo, resumeVersionRepo, callbackDeliveryRepo, domain.CallbackReplayPolicy(callbackReplayPolicy))
 
 	// Inicializar handlers con dependencias
 	resumeHandler := handlers.NewResumeHandler(resumeService)
-	awsHandler := handlers.NewAWSHandler(resumeRequestRepo, processedResumeRepo, resumeVersionRepo)
+	batchHandler := handlers.NewBatchHandler(batchService)
+	awsHandler := handlers.NewAWSHandler(resumeResultService)
 	resumeListHandler := handlers.NewResumeListHandler(resumeRequestRepo, processedResumeRepo, resumeVersionRepo)
-	resumeVersionHandler := handlers.NewResumeVersionHandler(resumeVersionRepo, processedResumeRepo)
+	resumeEventsHandler := handlers.NewResumeEventsHandler(eventBus, sseHeartbeat)
+	resumeTimelineHandler := handlers.NewResumeTimelineHandler(resumeRequestRepo, resumeRequestEventRepo)
+	resumeVersionHandler := handlers.NewResumeVersionHandler(unitOfWork, resumeVersionRepo, processedResumeRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	quotaHandler := handlers.NewQuotaHandler(quotaService)
 
-	// CV Processor routes
-	resume := api.Group("/resume")
+	// Lotes de CVs: la ruta se registra antes que el límite general y responde sin llamar
+	// a Next, así que a los lotes solo se les aplica su propio límite
+	resume.Post("/batch", batchBodyLimit, authMiddleware.ValidateJWT(), batchHandler.ProcessBatchHandler)
+	app.Use(fileBodyLimit)
 
+	// Health routes (sin autenticación)
+	health := api.Group("/health")
+	healthHandler := handlers.NewHealthHandler()
+	health.Get("/", healthHandler.HandleHealthCheck)
+
+	// CV Processor routes
+	//
 	// Endpoints protegidos (requieren autenticación de usuario)
 	resume.Post("/", authMiddleware.ValidateJWT(), resumeHandler.ProcessResumeHandler)
 	resume.Get("/my-resumes", authMiddleware.ValidateJWT(), resumeListHandler.GetMyResumes)
+	resume.Get("/events", authMiddleware.ValidateJWT(), resumeEventsHandler.StreamStatus)
+	resume.Get("/quota", authMiddleware.ValidateJWT(), quotaHandler.GetQuota)
+	resume.Get("/batch/:batch_id", authMiddleware.ValidateJWT(), batchHandler.GetBatchStatus)
 	resume.Get("/:request_id", authMiddleware.ValidateJWT(), resumeListHandler.GetResumeDetail)
+	resume.Get("/:request_id/timeline", authMiddleware.ValidateJWT(), resumeTimelineHandler.GetTimeline)
 
 	// Endpoints de versionado
 	resume.Get("/:request_id/versions", authMiddleware.ValidateJWT(), resumeVersionHandler.GetVersions)
@@ -52,7 +86,21 @@ func SetupRoutes(app *fiber.App, db *sql.DB, presignedURLEndpoint string, authMi
 	resume.Delete("/versions/:version_id", authMiddleware.ValidateJWT(), resumeVersionHandler.DeleteVersion)
 	resume.Get("/versions/:version_id", authMiddleware.ValidateJWT(), resumeVersionHandler.GetVersionDetail)
 
-	// Endpoint público (callback de AWS Lambda)
-	resume.Post("/results", awsHandler.ProcessResumeResultsHandler)
+	// Webhooks de eventos del ciclo de vida de los CVs
+	webhooks := api.Group("/webhooks")
+	webhooks.Post("/", authMiddleware.ValidateJWT(), webhookHandler.CreateWebhook)
+	webhooks.Get("/", authMiddleware.ValidateJWT(), webhookHandler.ListWebhooks)
+	webhooks.Delete("/:webhook_id", authMiddleware.ValidateJWT(), webhookHandler.DeleteWebhook)
+	webhooks.Get("/:webhook_id/deliveries", authMiddleware.ValidateJWT(), webhookHandler.ListDeliveries)
+
+	// Endpoint público (callback de AWS Lambda, autenticado con firma HMAC)
+	resume.Post("/results", callbackMiddleware.ValidateSignature(), awsHandler.ProcessResumeResultsHandler)
+
+	// Descargas del almacenamiento local (URL firmada, sin JWT). Con S3 las descargas
+	// no pasan por este servicio
+	if localStorage, ok := store.(*storage.LocalStorage); ok {
+		storageHandler := handlers.NewStorageHandler(localStorage)
+		app.Get(storage.LocalDownloadPath+"*", storageHandler.Download)
+	}
 
 }
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/events"
+	"resume-backend-service/internal/middleware"
+	"resume-backend-service/internal/services"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	_ "github.com/lib/pq"
+)
+
+func TestBodyLimitPerRoute(t *testing.T) {
+	const (
+		maxFileSize  = 1024 * 1024
+		maxBatchSize = 3 * 1024 * 1024
+	)
+
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	app := fiber.New(fiber.Config{StreamRequestBody: true, DisablePreParseMultipartForm: true})
+	SetupRoutes(app, db, nil, t.TempDir(), string(domain.ReplayPolicyReject), maxFileSize, 0, domain.QuotaLimits{},
+		services.BatchLimits{MaxUploadSize: maxBatchSize}, events.NewBus(1), time.Second, false,
+		middleware.NewAuthMiddleware("http://127.0.0.1:1/jwks.json"),
+		middleware.NewCallbackSignatureMiddleware([]string{"secret"}, time.Minute))
+
+	tests := []struct {
+		name        string
+		path        string
+		size        int
+		wantStatus  int
+		wantMessage string
+	}{
+		{"archivo dentro del límite", "/api/v1/resume/", maxFileSize, fiber.StatusUnauthorized, ""},
+		{"archivo sobre el límite", "/api/v1/resume/", 3 * maxFileSize, fiber.StatusRequestEntityTooLarge, services.FileTooLargeMessage(0, maxFileSize)},
+		{"callback sobre el límite", "/api/v1/resume/results", 3 * maxFileSize, fiber.StatusRequestEntityTooLarge, services.FileTooLargeMessage(0, maxFileSize)},
+		{"ruta inexistente sobre el límite", "/api/v1/unknown", 3 * maxFileSize, fiber.StatusRequestEntityTooLarge, services.FileTooLargeMessage(0, maxFileSize)},
+		{"lote sobre el límite de archivo", "/api/v1/resume/batch", 3 * maxFileSize, fiber.StatusUnauthorized, ""},
+		{"lote sobre el límite de lote", "/api/v1/resume/batch", 2 * maxBatchSize, fiber.StatusRequestEntityTooLarge, services.BatchTooLargeMessage(maxBatchSize)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, tt.path, bytes.NewReader(make([]byte, tt.size)))
+			req.Header.Set("Content-Type", "application/octet-stream")
+
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("message = %q, se esperaba %q", body.Message, tt.wantMessage)
+			}
+		})
+	}
+}
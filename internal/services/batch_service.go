@@ -0,0 +1,327 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/pkg/ziparchive"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// BatchLimits acota las subidas de varios CVs en una misma solicitud (0 = sin límite)
+type BatchLimits struct {
+	// MaxFiles es la cantidad máxima de archivos del lote (partes del formulario o
+	// archivos del ZIP)
+	MaxFiles int
+	// MaxUploadSize es el tamaño máximo de todos los archivos recibidos (o del ZIP)
+	MaxUploadSize int64
+	// MaxUncompressedSize es el tamaño descomprimido máximo del contenido de un ZIP
+	MaxUncompressedSize int64
+	// MaxCompressionRatio es la tasa de compresión máxima de los archivos de un ZIP
+	MaxCompressionRatio int64
+}
+
+// BatchService crea lotes de solicitudes a partir de varios archivos o de un ZIP. Cada
+// archivo se valida y se encola como una solicitud individual (ver ResumeService); la
+// cola de ingesta acota cuántos archivos de un mismo lote se procesan a la vez.
+type BatchService struct {
+	resumeService *ResumeService
+	unitOfWork    *repository.UnitOfWork
+	batchRepo     *repository.ResumeBatchRepository
+	limits        BatchLimits
+}
+
+func NewBatchService(resumeService *ResumeService, unitOfWork *repository.UnitOfWork, batchRepo *repository.ResumeBatchRepository, limits BatchLimits) *BatchService {
+	return &BatchService{
+		resumeService: resumeService,
+		unitOfWork:    unitOfWork,
+		batchRepo:     batchRepo,
+		limits:        limits,
+	}
+}
+
+// ProcessBatch registra un lote con una solicitud por cada archivo válido. Se reciben
+// varios archivos (un CV cada uno) o un único ZIP. Los archivos inválidos o que superan
+// la cuota se informan en Rejected sin impedir el resto del lote; si ninguno se acepta
+// no se crea el lote.
+func (s *BatchService) ProcessBatch(userID string, instructions string, language string, force bool, fileHeaders []*multipart.FileHeader) (dto.BatchResponseDTO, error) {
+	if len(fileHeaders) == 0 {
+		return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusBadRequest, "Campo 'file' requerido.")
+	}
+
+	// 1. Validar el tamaño del lote y obtener sus archivos (extrayendo el ZIP si lo hay)
+	var totalSize int64
+	source := domain.BatchSourceFiles
+	for _, fileHeader := range fileHeaders {
+		totalSize += fileHeader.Size
+		if isZIPFilename(fileHeader.Filename) {
+			source = domain.BatchSourceZIP
+		}
+	}
+	if source == domain.BatchSourceZIP && len(fileHeaders) > 1 {
+		return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusBadRequest, "El ZIP se debe enviar como único archivo del lote.")
+	}
+	if s.limits.MaxUploadSize > 0 && totalSize > s.limits.MaxUploadSize {
+		return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusRequestEntityTooLarge, BatchTooLargeMessage(s.limits.MaxUploadSize))
+	}
+
+	var files []uploadFile
+	var skipped []ziparchive.Skipped
+	if source == domain.BatchSourceZIP {
+		extractDir, err := os.MkdirTemp(s.resumeService.spoolDir, "batch-")
+		if err != nil {
+			log.Printf("❌ Error al crear directorio para el ZIP: %v", err)
+			return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+		}
+		// Los archivos extraídos se copian al spool; el directorio se descarta al terminar
+		defer os.RemoveAll(extractDir)
+
+		if files, skipped, err = s.extractZIP(fileHeaders[0], extractDir); err != nil {
+			return dto.BatchResponseDTO{}, err
+		}
+	} else {
+		if s.limits.MaxFiles > 0 && len(fileHeaders) > s.limits.MaxFiles {
+			return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("Se permiten como máximo %d archivos por lote.", s.limits.MaxFiles))
+		}
+		files = multipartUploads(fileHeaders)
+	}
+
+	batch := domain.NewResumeBatch(userID, source, len(files)+len(skipped), language, instructions)
+	for _, entry := range skipped {
+		batch.Reject(entry.Name, fmt.Sprintf("Se omitió del ZIP: %s.", entry.Reason))
+	}
+
+	// 2. Validar cada archivo como un CV independiente
+	var uploads []*preparedUpload
+	for _, file := range files {
+		upload, err := s.resumeService.prepareUpload(userID, instructions, language, force, []uploadFile{file})
+		if err != nil {
+			var fiberErr *fiber.Error
+			if !errors.As(err, &fiberErr) || fiberErr.Code >= fiber.StatusInternalServerError {
+				return dto.BatchResponseDTO{}, err
+			}
+			batch.Reject(file.Filename, fiberErr.Message)
+			continue
+		}
+		upload.request.BatchID = &batch.BatchID
+		uploads = append(uploads, upload)
+	}
+	if len(uploads) == 0 {
+		return dto.BatchResponseDTO{}, noValidFilesError(batch.Rejected)
+	}
+
+	// 3. Guardar los archivos válidos en el spool
+	spoolPaths := make(map[uuid.UUID]string, len(uploads))
+	removeSpool := func() {
+		for _, spoolPath := range spoolPaths {
+			os.RemoveAll(spoolPath)
+		}
+	}
+	// Los trabajos de ingesta llevan su copia de cada archivo (ver enqueueUpload)
+	defer removeSpool()
+	for _, upload := range uploads {
+		spoolPath, err := s.resumeService.saveToSpool(upload.request.RequestID.String(), upload.files)
+		if err != nil {
+			log.Printf("❌ Error al guardar archivo en el spool: %v", err)
+			return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+		}
+		spoolPaths[upload.request.RequestID] = spoolPath
+	}
+
+	// 4. Guardar el lote y sus solicitudes, y encolar las ingestas en una sola
+	// transacción. Los archivos que superan la cuota del usuario se rechazan uno a uno
+	var accepted []*domain.ResumeRequest
+	var quotaRejected []domain.BatchRejection
+	err := s.unitOfWork.Do(func(tx *sql.Tx) error {
+		accepted, quotaRejected = nil, nil
+		if err := s.batchRepo.WithTx(tx).Create(batch); err != nil {
+			return err
+		}
+
+		var lastQuotaErr error
+		for _, upload := range uploads {
+			err := s.resumeService.enqueueUpload(tx, upload.request, spoolPaths[upload.request.RequestID])
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				quotaRejected = append(quotaRejected, domain.BatchRejection{Filename: upload.request.OriginalFilename, Message: fiberErr.Message})
+				lastQuotaErr = err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			accepted = append(accepted, upload.request)
+		}
+		if len(accepted) == 0 {
+			return lastQuotaErr
+		}
+
+		if len(quotaRejected) > 0 {
+			rejected := append(append([]domain.BatchRejection{}, batch.Rejected...), quotaRejected...)
+			return s.batchRepo.WithTx(tx).UpdateRejected(batch.BatchID, rejected)
+		}
+		return nil
+	})
+	if err != nil {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			return dto.BatchResponseDTO{}, fiberErr
+		}
+		log.Printf("❌ Error al guardar lote: %v", err)
+		return dto.BatchResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+	batch.Rejected = append(batch.Rejected, quotaRejected...)
+
+	log.Printf("📦 Lote encolado: batch_id=%s, user_id=%s, origen=%s, aceptados=%d, rechazados=%d",
+		batch.BatchID, userID, batch.Source, len(accepted), len(batch.Rejected))
+
+	response := dto.BatchResponseDTO{
+		Status:     "accepted",
+		Message:    "Lote encolado para procesamiento.",
+		BatchID:    batch.BatchID.String(),
+		TotalFiles: batch.TotalFiles,
+		Accepted:   make([]dto.BatchAcceptedFile, len(accepted)),
+		Rejected:   rejectedFilesDTO(batch.Rejected),
+	}
+	for i, request := range accepted {
+		response.Accepted[i] = dto.BatchAcceptedFile{RequestID: request.RequestID.String(), Filename: request.OriginalFilename}
+	}
+	return response, nil
+}
+
+// extractZIP extrae los archivos del ZIP recibido en extractDir
+func (s *BatchService) extractZIP(fileHeader *multipart.FileHeader, extractDir string) ([]uploadFile, []ziparchive.Skipped, error) {
+	archive, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("❌ Error al leer archivo recibido: %v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+	defer archive.Close()
+
+	result, err := ziparchive.Extract(archive, fileHeader.Size, extractDir, ziparchive.Limits{
+		MaxFiles:            s.limits.MaxFiles,
+		MaxFileSize:         s.resumeService.quotaService.maxFileSize,
+		MaxTotalSize:        s.limits.MaxUncompressedSize,
+		MaxCompressionRatio: s.limits.MaxCompressionRatio,
+	})
+	switch {
+	case errors.Is(err, ziparchive.ErrNotZip):
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "El archivo no es un ZIP válido o está dañado.")
+	case errors.Is(err, ziparchive.ErrUnsafePath):
+		log.Printf("🚫 ZIP con rutas inseguras: filename=%s", fileHeader.Filename)
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "El ZIP contiene rutas inseguras (absolutas o con '..').")
+	case errors.Is(err, ziparchive.ErrTooManyFiles):
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("El ZIP contiene más de %d archivos.", s.limits.MaxFiles))
+	case errors.Is(err, ziparchive.ErrTooLarge):
+		return nil, nil, fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("El contenido descomprimido del ZIP supera el máximo de %s.", formatBytes(s.limits.MaxUncompressedSize)))
+	case errors.Is(err, ziparchive.ErrCompressionRatio):
+		log.Printf("🚫 ZIP con tasa de compresión sospechosa: filename=%s", fileHeader.Filename)
+		return nil, nil, fiber.NewError(fiber.StatusUnprocessableEntity, "El ZIP tiene una tasa de compresión sospechosa y no se procesó.")
+	case err != nil:
+		log.Printf("❌ Error al extraer ZIP: %v", err)
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+
+	if len(result.Files) == 0 && len(result.Skipped) == 0 {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "El ZIP no contiene archivos.")
+	}
+
+	files := make([]uploadFile, len(result.Files))
+	for i, file := range result.Files {
+		files[i] = diskUpload(file.Name, file.Path, file.Size)
+	}
+	return files, result.Skipped, nil
+}
+
+// GetBatchStatus retorna el progreso de un lote del usuario
+func (s *BatchService) GetBatchStatus(userID string, batchID uuid.UUID) (dto.BatchStatusDTO, error) {
+	batch, err := s.batchRepo.FindByID(batchID)
+	if err != nil {
+		return dto.BatchStatusDTO{}, fiber.NewError(fiber.StatusNotFound, "Lote no encontrado")
+	}
+	if batch.UserID != userID {
+		return dto.BatchStatusDTO{}, fiber.NewError(fiber.StatusForbidden, "No tienes acceso a este lote")
+	}
+
+	progress, err := s.batchRepo.GetProgress(batchID)
+	if err != nil {
+		log.Printf("❌ Error al obtener progreso del lote: %v", err)
+		return dto.BatchStatusDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener el lote")
+	}
+	items, err := s.batchRepo.ListRequests(batchID)
+	if err != nil {
+		log.Printf("❌ Error al obtener solicitudes del lote: %v", err)
+		return dto.BatchStatusDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener el lote")
+	}
+
+	status := dto.BatchStatusDTO{
+		Status:        "success",
+		BatchID:       batch.BatchID.String(),
+		Source:        string(batch.Source),
+		TotalFiles:    batch.TotalFiles,
+		AcceptedFiles: progress.Accepted(),
+		RejectedFiles: len(batch.Rejected),
+		StatusCounts:  make(map[string]int, len(progress.StatusCounts)),
+		Progress:      progress.Percent(),
+		Finished:      progress.Finished() == progress.Accepted(),
+		CreatedAt:     batch.CreatedAt,
+		Items:         make([]dto.BatchItemDTO, len(items)),
+		Rejected:      rejectedFilesDTO(batch.Rejected),
+	}
+	for requestStatus, count := range progress.StatusCounts {
+		status.StatusCounts[string(requestStatus)] = count
+	}
+	for i, item := range items {
+		status.Items[i] = dto.BatchItemDTO{
+			RequestID:    item.RequestID.String(),
+			Filename:     item.OriginalFilename,
+			Status:       string(item.Status),
+			ErrorCode:    item.ErrorCode.String,
+			ErrorMessage: item.ErrorMessage.String,
+			CompletedAt:  item.CompletedAt,
+		}
+	}
+	return status, nil
+}
+
+// BatchTooLargeMessage es el mensaje de error para un lote que supera maxUploadSize
+func BatchTooLargeMessage(maxUploadSize int64) string {
+	return fmt.Sprintf("Los archivos del lote superan el tamaño máximo permitido de %s.", formatBytes(maxUploadSize))
+}
+
+// isZIPFilename indica si el archivo recibido es un ZIP. Se decide por la extensión:
+// los .docx y .odt también son ZIP por dentro
+func isZIPFilename(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".zip"
+}
+
+// noValidFilesError es el error de un lote sin archivos aceptados; incluye el motivo
+// del primer rechazo
+func noValidFilesError(rejected []domain.BatchRejection) error {
+	message := "Ningún archivo del lote es válido."
+	if len(rejected) > 0 {
+		message = fmt.Sprintf("Ningún archivo del lote es válido (%s: %s)", rejected[0].Filename, rejected[0].Message)
+	}
+	return fiber.NewError(fiber.StatusUnprocessableEntity, message)
+}
+
+func rejectedFilesDTO(rejected []domain.BatchRejection) []dto.BatchRejectedFile {
+	files := make([]dto.BatchRejectedFile, len(rejected))
+	for i, rejection := range rejected {
+		files[i] = dto.BatchRejectedFile{Filename: rejection.Filename, Message: rejection.Message}
+	}
+	return files
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"resume-backend-service/internal/domain"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// zipUpload arma un archivo de formulario con un ZIP de los archivos indicados
+func zipUpload(t *testing.T, files map[string]string) *multipart.FileHeader {
+	t.Helper()
+
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	for name, content := range files {
+		w, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	zipWriter.Close()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, _ := writer.CreateFormFile("file", "cvs.zip")
+	part.Write(archive.Bytes())
+	writer.Close()
+
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestExtractZIPReturnsValidatableUploads(t *testing.T) {
+	service := &BatchService{
+		resumeService: &ResumeService{quotaService: NewQuotaService(nil, domain.QuotaLimits{}, 1024, 0)},
+		limits:        BatchLimits{MaxFiles: 10},
+	}
+
+	files, skipped, err := service.extractZIP(zipUpload(t, map[string]string{
+		"equipo/ana.txt":  "Ana Pérez - Desarrolladora",
+		"equipo/juan.txt": string(bytes.Repeat([]byte("x"), 2048)),
+	}), t.TempDir())
+	if err != nil {
+		t.Fatalf("extractZIP: %v", err)
+	}
+
+	if len(files) != 1 || files[0].Filename != "ana.txt" {
+		t.Fatalf("archivos = %+v", files)
+	}
+	if _, err := validateUpload(files[0]); err != nil {
+		t.Errorf("validateUpload: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0].Name != "juan.txt" {
+		t.Errorf("omitidos = %+v: juan.txt supera el tamaño máximo", skipped)
+	}
+}
+
+func TestExtractZIPRejectsUnsafeArchive(t *testing.T) {
+	service := &BatchService{resumeService: &ResumeService{quotaService: NewQuotaService(nil, domain.QuotaLimits{}, 1024, 0)}}
+
+	_, _, err := service.extractZIP(zipUpload(t, map[string]string{"../../etc/cv.txt": "texto"}), t.TempDir())
+	if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusBadRequest {
+		t.Errorf("se esperaba 400, se obtuvo %v", err)
+	}
+}
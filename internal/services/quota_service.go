@@ -0,0 +1,151 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/repository"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QuotaService aplica el tamaño máximo de archivo, las páginas máximas de los PDF y
+// las cuotas de uso por usuario
+type QuotaService struct {
+	quotaRepo   *repository.QuotaRepository
+	defaults    domain.QuotaLimits
+	maxFileSize int64
+	maxPDFPages int
+}
+
+// NewQuotaService crea el servicio. defaults son los límites de los usuarios sin cuota
+// personalizada en user_quotas; maxPDFPages 0 no limita las páginas.
+func NewQuotaService(quotaRepo *repository.QuotaRepository, defaults domain.QuotaLimits, maxFileSize int64, maxPDFPages int) *QuotaService {
+	return &QuotaService{
+		quotaRepo:   quotaRepo,
+		defaults:    defaults,
+		maxFileSize: maxFileSize,
+		maxPDFPages: maxPDFPages,
+	}
+}
+
+// CheckFileSize rechaza con 413 los archivos que superan el tamaño máximo
+func (s *QuotaService) CheckFileSize(size int64) error {
+	if s.maxFileSize > 0 && size > s.maxFileSize {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, FileTooLargeMessage(size, s.maxFileSize))
+	}
+	return nil
+}
+
+// CheckPageCount rechaza con 422 los PDF que superan la cantidad máxima de páginas
+func (s *QuotaService) CheckPageCount(pages int) error {
+	if s.maxPDFPages > 0 && pages > s.maxPDFPages {
+		return fiber.NewError(fiber.StatusUnprocessableEntity,
+			fmt.Sprintf("El PDF tiene %d páginas y supera el máximo permitido de %d.", pages, s.maxPDFPages))
+	}
+	return nil
+}
+
+// ReserveUpload valida, dentro de la transacción que crea la solicitud, que el usuario
+// no supere su cuota diaria ni de almacenamiento con un archivo de size bytes. La fila de
+// cuota queda bloqueada hasta el commit, así dos subidas simultáneas no la superan.
+func (s *QuotaService) ReserveUpload(tx *sql.Tx, userID string, size int64) error {
+	quotaRepo := s.quotaRepo.WithTx(tx)
+
+	if err := quotaRepo.LockUser(userID); err != nil {
+		return err
+	}
+
+	limits, err := quotaRepo.FindLimits(userID, s.defaults)
+	if err != nil {
+		return err
+	}
+
+	usage, err := quotaRepo.GetUsage(userID, domain.QuotaDayStart(time.Now()))
+	if err != nil {
+		return err
+	}
+
+	if !limits.CanCreateRequest(usage) {
+		log.Printf("🚫 Cuota diaria alcanzada: user_id=%s, solicitudes=%d", userID, usage.RequestsToday)
+		return fiber.NewError(fiber.StatusTooManyRequests,
+			fmt.Sprintf("Se alcanzó el máximo de %d solicitudes por día. El contador se reinicia a las 00:00 UTC.", limits.MaxRequestsPerDay))
+	}
+
+	if !limits.CanStore(usage, size) {
+		log.Printf("🚫 Cuota de almacenamiento alcanzada: user_id=%s, usado=%d, archivo=%d", userID, usage.StoredBytes, size)
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("El archivo supera el espacio disponible: usados %s de %s.", formatBytes(usage.StoredBytes), formatBytes(limits.MaxStorageBytes)))
+	}
+
+	return nil
+}
+
+// GetQuota obtiene los límites y el consumo actual del usuario
+func (s *QuotaService) GetQuota(userID string) (dto.QuotaResponse, error) {
+	limits, err := s.quotaRepo.FindLimits(userID, s.defaults)
+	if err != nil {
+		log.Printf("❌ Error al obtener cuota: %v", err)
+		return dto.QuotaResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener la cuota.")
+	}
+
+	dayStart := domain.QuotaDayStart(time.Now())
+	usage, err := s.quotaRepo.GetUsage(userID, dayStart)
+	if err != nil {
+		log.Printf("❌ Error al obtener consumo: %v", err)
+		return dto.QuotaResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener la cuota.")
+	}
+
+	requests := newQuotaCounter(int64(limits.MaxRequestsPerDay), int64(usage.RequestsToday))
+	resetsAt := dayStart.Add(24 * time.Hour)
+	requests.ResetsAt = &resetsAt
+
+	return dto.QuotaResponse{
+		Status:           "success",
+		MaxFileSizeBytes: s.maxFileSize,
+		MaxPDFPages:      s.maxPDFPages,
+		RequestsPerDay:   requests,
+		StorageBytes:     newQuotaCounter(limits.MaxStorageBytes, usage.StoredBytes),
+	}, nil
+}
+
+// FileTooLargeMessage es el mensaje de error para un archivo que supera maxFileSize.
+// size puede ser 0 si no se conoce (el body completo fue rechazado).
+func FileTooLargeMessage(size, maxFileSize int64) string {
+	if size <= 0 {
+		return fmt.Sprintf("El archivo supera el tamaño máximo permitido de %s.", formatBytes(maxFileSize))
+	}
+	return fmt.Sprintf("El archivo pesa %s y supera el tamaño máximo permitido de %s.", formatBytes(size), formatBytes(maxFileSize))
+}
+
+func newQuotaCounter(limit, used int64) dto.QuotaCounter {
+	counter := dto.QuotaCounter{Used: used}
+	if limit > 0 {
+		remaining := limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		counter.Limit = &limit
+		counter.Remaining = &remaining
+	}
+	return counter
+}
+
+// formatBytes expresa un tamaño en la unidad más legible (B, KB, MB, GB)
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, suffix := float64(size)/unit, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
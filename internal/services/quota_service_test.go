@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"resume-backend-service/internal/domain"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCheckFileSizeRejectsWith413(t *testing.T) {
+	service := NewQuotaService(nil, domain.QuotaLimits{}, 10*1024*1024, 0)
+
+	if err := service.CheckFileSize(10 * 1024 * 1024); err != nil {
+		t.Errorf("un archivo del tamaño máximo debe aceptarse: %v", err)
+	}
+
+	err := service.CheckFileSize(12 * 1024 * 1024)
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) || fiberErr.Code != fiber.StatusRequestEntityTooLarge {
+		t.Fatalf("se esperaba 413, se obtuvo %v", err)
+	}
+	if want := "El archivo pesa 12.0 MB y supera el tamaño máximo permitido de 10.0 MB."; fiberErr.Message != want {
+		t.Errorf("mensaje = %q, se esperaba %q", fiberErr.Message, want)
+	}
+}
+
+func TestCheckPageCountRejectsWith422(t *testing.T) {
+	service := NewQuotaService(nil, domain.QuotaLimits{}, 0, 20)
+
+	if err := service.CheckPageCount(20); err != nil {
+		t.Errorf("un PDF con las páginas máximas debe aceptarse: %v", err)
+	}
+
+	err := service.CheckPageCount(80)
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) || fiberErr.Code != fiber.StatusUnprocessableEntity {
+		t.Fatalf("se esperaba 422, se obtuvo %v", err)
+	}
+	if want := "El PDF tiene 80 páginas y supera el máximo permitido de 20."; fiberErr.Message != want {
+		t.Errorf("mensaje = %q, se esperaba %q", fiberErr.Message, want)
+	}
+
+	if err := NewQuotaService(nil, domain.QuotaLimits{}, 0, 0).CheckPageCount(500); err != nil {
+		t.Errorf("un límite 0 significa sin límite: %v", err)
+	}
+}
+
+func TestQuotaLimits(t *testing.T) {
+	limits := domain.QuotaLimits{MaxRequestsPerDay: 2, MaxStorageBytes: 100}
+
+	if !limits.CanCreateRequest(domain.QuotaUsage{RequestsToday: 1}) {
+		t.Error("debe permitir la segunda solicitud del día")
+	}
+	if limits.CanCreateRequest(domain.QuotaUsage{RequestsToday: 2}) {
+		t.Error("no debe permitir superar el máximo diario")
+	}
+	if !limits.CanStore(domain.QuotaUsage{StoredBytes: 60}, 40) || limits.CanStore(domain.QuotaUsage{StoredBytes: 60}, 41) {
+		t.Error("el almacenamiento debe permitir llegar justo al límite y no superarlo")
+	}
+
+	unlimited := domain.QuotaLimits{}
+	if !unlimited.CanCreateRequest(domain.QuotaUsage{RequestsToday: 1000}) || !unlimited.CanStore(domain.QuotaUsage{StoredBytes: 1 << 40}, 1) {
+		t.Error("un límite 0 significa sin límite")
+	}
+}
+
+func TestNewQuotaCounter(t *testing.T) {
+	counter := newQuotaCounter(10, 12)
+	if counter.Limit == nil || *counter.Limit != 10 || counter.Remaining == nil || *counter.Remaining != 0 {
+		t.Errorf("contador con límite = %+v", counter)
+	}
+
+	counter = newQuotaCounter(0, 5)
+	if counter.Limit != nil || counter.Remaining != nil || counter.Used != 5 {
+		t.Errorf("contador sin límite = %+v", counter)
+	}
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ResumeResultService procesa los resultados que AWS Lambda envía por callback
+type ResumeResultService struct {
+	unitOfWork           *repository.UnitOfWork
+	resumeRequestRepo    *repository.ResumeRequestRepository
+	processedResumeRepo  *repository.ProcessedResumeRepository
+	resumeVersionRepo    *repository.ResumeVersionRepository
+	callbackDeliveryRepo *repository.CallbackDeliveryRepository
+	replayPolicy         domain.CallbackReplayPolicy
+}
+
+func NewResumeResultService(unitOfWork *repository.UnitOfWork, resumeRequestRepo *repository.ResumeRequestRepository, processedResumeRepo *repository.ProcessedResumeRepository, resumeVersionRepo *repository.ResumeVersionRepository, callbackDeliveryRepo *repository.CallbackDeliveryRepository, replayPolicy domain.CallbackReplayPolicy) *ResumeResultService {
+	return &ResumeResultService{
+		unitOfWork:           unitOfWork,
+		resumeRequestRepo:    resumeRequestRepo,
+		processedResumeRepo:  processedResumeRepo,
+		resumeVersionRepo:    resumeVersionRepo,
+		callbackDeliveryRepo: callbackDeliveryRepo,
+		replayPolicy:         replayPolicy,
+	}
+}
+
+// ProcessResult guarda el resultado de Lambda de forma idempotente y atómica.
+// Los errores retornados son *fiber.Error con el código HTTP a responder.
+func (s *ResumeResultService) ProcessResult(requestID uuid.UUID, lambdaResponse *dto.AWSLambdaResponse) (dto.AWSProcessResponse, error) {
+	// Un status desconocido no se registra: no entra en la columna de la entrega y
+	// reintentar el callback no lo corrige
+	if !dto.IsValidLambdaStatus(lambdaResponse.Status) {
+		log.Printf("❌ Status de Lambda desconocido para request_id=%s: %.50q", requestID, lambdaResponse.Status)
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Status inválido. Permite: %s, %s, %s.", dto.LambdaStatusSuccess, dto.LambdaStatusError, dto.LambdaStatusProcessing))
+	}
+
+	// 1. Verificar que la solicitud exista
+	resumeRequest, err := s.resumeRequestRepo.FindByRequestID(requestID)
+	if err != nil {
+		log.Printf("❌ Error al buscar solicitud: %v", err)
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusNotFound, "Solicitud no encontrada.")
+	}
+
+	log.Printf("✅ Solicitud encontrada: user_id=%s, filename=%s", resumeRequest.UserID, resumeRequest.OriginalFilename)
+
+	// El procesador confirma que recibió el archivo: solo avanza a processing
+	if lambdaResponse.Status == dto.LambdaStatusProcessing {
+		return s.acknowledgeProcessing(requestID)
+	}
+
+	// 2. Registrar la entrega fuera de la transacción, para que el contador
+	// refleje cada llegada aunque el procesamiento haga rollback
+	delivery, err := s.callbackDeliveryRepo.RecordDelivery(requestID, resultFingerprint(lambdaResponse), lambdaResponse.Status)
+	if err != nil {
+		log.Printf("❌ Error al registrar entrega del callback: %v", err)
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al registrar el callback.")
+	}
+
+	if delivery.IsDuplicate() {
+		log.Printf("🔁 Callback duplicado para request_id=%s (entrega #%d, outcome=%s), sin cambios",
+			requestID, delivery.DeliveryCount, *delivery.Outcome)
+		return dto.AWSProcessResponse{Status: "success", Message: "Callback duplicado, sin cambios."}, nil
+	}
+
+	// 3. Sanitizar los datos antes de abrir la transacción
+	var sanitizedStructuredData dto.CVProcessedData
+	if lambdaResponse.Status == dto.LambdaStatusSuccess {
+		data, err := sanitizeStructuredData(lambdaResponse.StructuredData)
+		if err != nil {
+			log.Printf("❌ Error al sanitizar datos estructurados: %v", err)
+			s.failDelivery(resumeRequest, delivery, domain.NewProcessingError(domain.ErrorCodeInvalidResult, "Error al sanitizar datos estructurados", domain.StagePersistence, false))
+			return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al sanitizar datos estructurados.")
+		}
+		sanitizedStructuredData = *data
+		logStructuredData(requestID, resumeRequest.UserID, lambdaResponse, &sanitizedStructuredData)
+	}
+
+	// 4. Aplicar el resultado en una única transacción. Los rechazos se confirman
+	// igual (guardan el outcome) y se reportan al cliente después del commit.
+	var response dto.AWSProcessResponse
+	var rejection *fiber.Error
+	err = s.unitOfWork.Do(func(tx *sql.Tx) error {
+		var txErr error
+		response, rejection, txErr = s.applyResult(tx, requestID, delivery, lambdaResponse, &sanitizedStructuredData)
+		return txErr
+	})
+	if err != nil {
+		log.Printf("❌ Error al guardar resultado (rollback): %v", err)
+		s.failDelivery(resumeRequest, delivery, domain.NewProcessingError(domain.ErrorCodePersistFailed, "Error al guardar resultado del procesamiento", domain.StagePersistence, true))
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al guardar resultado del procesamiento.")
+	}
+
+	if rejection != nil {
+		return dto.AWSProcessResponse{}, rejection
+	}
+
+	return response, nil
+}
+
+// applyResult contiene todos los cambios del callback; corre dentro de la transacción.
+// Retorna la respuesta, un rechazo a reportar tras el commit, o un error que hace rollback.
+func (s *ResumeResultService) applyResult(tx *sql.Tx, requestID uuid.UUID, delivery *domain.CallbackDelivery, lambdaResponse *dto.AWSLambdaResponse, structuredData *dto.CVProcessedData) (dto.AWSProcessResponse, *fiber.Error, error) {
+	requestRepo := s.resumeRequestRepo.WithTx(tx)
+	deliveryRepo := s.callbackDeliveryRepo.WithTx(tx)
+
+	// Otra entrega del mismo resultado pudo haberse aplicado mientras esperábamos el lock
+	outcome, err := deliveryRepo.LockOutcome(delivery.ID)
+	if err != nil {
+		return dto.AWSProcessResponse{}, nil, err
+	}
+	if outcome != nil && *outcome != domain.CallbackOutcomeError {
+		log.Printf("🔁 Entrega %d ya resuelta por otro callback (outcome=%s), sin cambios", delivery.ID, *outcome)
+		return dto.AWSProcessResponse{Status: "success", Message: "Callback duplicado, sin cambios."}, nil, nil
+	}
+
+	resumeRequest, err := requestRepo.FindByRequestIDForUpdate(requestID)
+	if err != nil {
+		return dto.AWSProcessResponse{}, nil, err
+	}
+	alreadyCompleted := resumeRequest.Status == domain.StatusCompleted
+
+	// Lambda reportó un fallo
+	if lambdaResponse.Status != dto.LambdaStatusSuccess {
+		log.Printf("⚠️  AWS reportó status: %s", lambdaResponse.Status)
+
+		// Un fallo tardío no debe sobrescribir una solicitud ya finalizada
+		if resumeRequest.Status.IsFinal() {
+			log.Printf("⚠️  Solicitud %s ya finalizada (%s), se ignora el fallo reportado", requestID, resumeRequest.Status)
+			if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeIgnored); err != nil {
+				return dto.AWSProcessResponse{}, nil, err
+			}
+			return dto.AWSProcessResponse{Status: "success", Message: "Solicitud ya finalizada, se ignora el fallo reportado."}, nil, nil
+		}
+
+		if err := requestRepo.MarkAsFailed(requestID, lambdaProcessingError(lambdaResponse), domain.ActorCallback); err != nil {
+			return dto.AWSProcessResponse{}, nil, err
+		}
+		if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeApplied); err != nil {
+			return dto.AWSProcessResponse{}, nil, err
+		}
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error en el procesamiento de AWS."), nil
+	}
+
+	versionRepo := s.resumeVersionRepo.WithTx(tx)
+
+	// Un resultado nuevo para una solicitud completada depende de la política configurada
+	if alreadyCompleted {
+		if s.replayPolicy != domain.ReplayPolicyNewVersion {
+			log.Printf("⚠️  Solicitud %s ya completada, resultado nuevo rechazado (política=%s)", requestID, s.replayPolicy)
+			if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeRejected); err != nil {
+				return dto.AWSProcessResponse{}, nil, err
+			}
+			return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusConflict, "La solicitud ya fue completada."), nil
+		}
+
+		versionID, err := versionRepo.CreateVersion(requestID, resumeRequest.UserID, structuredData, "Reprocesamiento", "system")
+		if err != nil {
+			return dto.AWSProcessResponse{}, nil, fmt.Errorf("error al crear versión de reprocesamiento: %w", err)
+		}
+		if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeApplied); err != nil {
+			return dto.AWSProcessResponse{}, nil, err
+		}
+
+		log.Printf("✅ Nueva versión del sistema creada para request_id=%s: version_id=%d", requestID, versionID)
+		return dto.AWSProcessResponse{Status: "success", Message: "Nueva versión creada a partir del resultado."}, nil, nil
+	}
+
+	// Una solicitud fallida (ej: por timeout) no puede completarse con un resultado tardío
+	if resumeRequest.Status.IsFinal() {
+		log.Printf("⚠️  Solicitud %s en estado %s, resultado tardío rechazado", requestID, resumeRequest.Status)
+		if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeRejected); err != nil {
+			return dto.AWSProcessResponse{}, nil, err
+		}
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusConflict, "La solicitud ya no admite resultados (estado: "+string(resumeRequest.Status)+")."), nil
+	}
+
+	// Si el procesador no envió el acuse de recibo, pasar primero por processing
+	if resumeRequest.Status != domain.StatusProcessing {
+		if err := requestRepo.MarkAsProcessing(requestID, domain.ActorCallback); err != nil {
+			return dto.AWSProcessResponse{}, nil, err
+		}
+	}
+
+	// Crear CV procesado, primera versión y marcar la solicitud como completada
+	processedResume := domain.NewProcessedResume(requestID, resumeRequest.UserID)
+	if err := s.processedResumeRepo.WithTx(tx).Create(processedResume); err != nil {
+		return dto.AWSProcessResponse{}, nil, err
+	}
+
+	versionID, err := versionRepo.CreateVersion(requestID, resumeRequest.UserID, structuredData, "Versión inicial", "system")
+	if err != nil {
+		return dto.AWSProcessResponse{}, nil, fmt.Errorf("error al crear versión inicial: %w", err)
+	}
+
+	if err := requestRepo.MarkAsCompleted(requestID, lambdaResponse.OutputFile, lambdaResponse.ProcessingTimeMs, domain.ActorCallback); err != nil {
+		return dto.AWSProcessResponse{}, nil, err
+	}
+
+	if err := deliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeApplied); err != nil {
+		return dto.AWSProcessResponse{}, nil, err
+	}
+
+	log.Printf("✅ CV procesado guardado: resume_id=%d, version_id=%d", processedResume.ID, versionID)
+	log.Printf("🎉 Procesamiento completo para request_id: %s", requestID)
+
+	return dto.AWSProcessResponse{Status: "success", Message: "Datos procesados y guardados correctamente."}, nil, nil
+}
+
+// acknowledgeProcessing pasa la solicitud a processing cuando Lambda confirma que la recibió.
+// Los acuses repetidos o tardíos (la solicitud ya avanzó) se responden sin cambios.
+func (s *ResumeResultService) acknowledgeProcessing(requestID uuid.UUID) (dto.AWSProcessResponse, error) {
+	err := s.resumeRequestRepo.MarkAsProcessing(requestID, domain.ActorCallback)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		log.Printf("ℹ️  Acuse de procesamiento ignorado: %v", err)
+		return dto.AWSProcessResponse{Status: "success", Message: "La solicitud ya no está pendiente de procesamiento, sin cambios."}, nil
+	}
+	if err != nil {
+		log.Printf("❌ Error al marcar solicitud como en procesamiento: %v", err)
+		return dto.AWSProcessResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al actualizar el estado de la solicitud.")
+	}
+
+	log.Printf("⚙️  Solicitud %s en procesamiento", requestID)
+	return dto.AWSProcessResponse{Status: "success", Message: "Solicitud marcada como en procesamiento."}, nil
+}
+
+// failDelivery marca la entrega como error (reintentable) y la solicitud como fallida,
+// salvo que la solicitud ya esté finalizada
+func (s *ResumeResultService) failDelivery(resumeRequest *domain.ResumeRequest, delivery *domain.CallbackDelivery, processingErr domain.ProcessingError) {
+	if err := s.callbackDeliveryRepo.SetOutcome(delivery.ID, domain.CallbackOutcomeError); err != nil {
+		log.Printf("⚠️  Error al guardar resultado de la entrega %d: %v", delivery.ID, err)
+	}
+
+	err := s.resumeRequestRepo.MarkAsFailed(resumeRequest.RequestID, processingErr, domain.ActorSystem)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		// La solicitud ya estaba finalizada; se conserva su estado
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Error al marcar solicitud como fallida: %v", err)
+	}
+}
+
+// lambdaProcessingError construye el error a guardar a partir del fallo reportado por Lambda.
+// Las versiones de Lambda que no envían el objeto "error" quedan con un código genérico, y
+// el código y la etapa se recortan al ancho de sus columnas.
+func lambdaProcessingError(lambdaResponse *dto.AWSLambdaResponse) domain.ProcessingError {
+	if lambdaResponse.Error == nil || lambdaResponse.Error.Code == "" {
+		return domain.NewProcessingError(domain.ErrorCodeLambdaFailed, "AWS Lambda reportó status: "+lambdaResponse.Status, domain.StageExtraction, false)
+	}
+
+	message := lambdaResponse.Error.Message
+	if message == "" {
+		message = "AWS Lambda reportó status: " + lambdaResponse.Status
+	}
+
+	return domain.NewProcessingError(lambdaResponse.Error.Code, message, lambdaResponse.Error.Stage, lambdaResponse.Error.Retryable).Truncated()
+}
+
+// sanitizeStructuredData limpia las fechas inválidas y retorna la estructura tipada
+func sanitizeStructuredData(structuredData dto.CVProcessedData) (*dto.CVProcessedData, error) {
+	structuredDataMap, err := utils.SanitizeStructuredData(structuredData)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convertir de vuelta a la estructura tipada
+	structuredDataBytes, _ := json.Marshal(structuredDataMap)
+	var sanitized dto.CVProcessedData
+	if err := json.Unmarshal(structuredDataBytes, &sanitized); err != nil {
+		return nil, fmt.Errorf("error al convertir datos sanitizados: %w", err)
+	}
+
+	return &sanitized, nil
+}
+
+// logStructuredData registra un resumen legible del CV procesado
+func logStructuredData(requestID uuid.UUID, userID string, lambdaResponse *dto.AWSLambdaResponse, data *dto.CVProcessedData) {
+	jsonPretty, _ := json.MarshalIndent(data, "", "  ")
+	log.Printf("✅ CV procesado correctamente:")
+	log.Printf("   🆔 Request ID: %s", requestID)
+	log.Printf("   👤 User ID: %s", userID)
+	log.Printf("   📄 Input: %s", lambdaResponse.InputFile)
+	log.Printf("   📄 Output: %s", lambdaResponse.OutputFile)
+	log.Printf("   ⏱️  Tiempo: %dms", lambdaResponse.ProcessingTimeMs)
+	log.Printf("   👤 Nombre: %s", data.Header.Name)
+	log.Printf("   📧 Email: %s", data.Header.Contact.Email)
+	log.Printf("   📞 Teléfono: %s", data.Header.Contact.Phone)
+	log.Printf("   🎓 Educación: %d registros", len(data.Education))
+	log.Printf("   💼 Experiencia: %d registros", len(data.ProfessionalExperience))
+	log.Printf("   🏆 Certificaciones: %d registros", len(data.Certifications))
+	log.Printf("   🚀 Proyectos: %d registros", len(data.Projects))
+	log.Printf("   🛠️  Skills: %d registros", len(data.TechnicalSkills.Skills))
+	log.Printf("\n📋 Datos completos:\n%s", string(jsonPretty))
+}
+
+// resultFingerprint calcula el SHA-256 del contenido del resultado. Dos entregas con el
+// mismo status, archivo de salida, datos estructurados y error son el mismo resultado.
+func resultFingerprint(lambdaResponse *dto.AWSLambdaResponse) string {
+	content, _ := json.Marshal(struct {
+		Status         string              `json:"status"`
+		OutputFile     string              `json:"output_file"`
+		StructuredData dto.CVProcessedData `json:"structured_data"`
+		Error          *dto.AWSLambdaError `json:"error,omitempty"`
+	}{
+		Status:         lambdaResponse.Status,
+		OutputFile:     lambdaResponse.OutputFile,
+		StructuredData: lambdaResponse.StructuredData,
+		Error:          lambdaResponse.Error,
+	})
+
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
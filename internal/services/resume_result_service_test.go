@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestLambdaProcessingErrorFitsErrorColumns(t *testing.T) {
+	response := &dto.AWSLambdaResponse{
+		Status: dto.LambdaStatusError,
+		Error: &dto.AWSLambdaError{
+			Code:    strings.Repeat("CÓDIGO_", 20),
+			Message: "Fallo en la extracción",
+			Stage:   strings.Repeat("extracción ", 10),
+		},
+	}
+
+	processingErr := lambdaProcessingError(response)
+	if n := utf8.RuneCountInString(processingErr.Code); n != domain.MaxErrorFieldLength {
+		t.Errorf("código con %d caracteres, se esperaban %d", n, domain.MaxErrorFieldLength)
+	}
+	if n := utf8.RuneCountInString(processingErr.Stage); n != domain.MaxErrorFieldLength {
+		t.Errorf("etapa con %d caracteres, se esperaban %d", n, domain.MaxErrorFieldLength)
+	}
+	if !utf8.ValidString(processingErr.Code) || !utf8.ValidString(processingErr.Stage) {
+		t.Error("el recorte no debe partir caracteres UTF-8")
+	}
+	if processingErr.Message != "Fallo en la extracción" {
+		t.Errorf("el mensaje no se recorta: %q", processingErr.Message)
+	}
+
+	response.Error = &dto.AWSLambdaError{Code: "CORRUPT_PDF", Stage: "download"}
+	if processingErr := lambdaProcessingError(response); processingErr.Code != "CORRUPT_PDF" || processingErr.Stage != "download" {
+		t.Errorf("los valores cortos se guardan tal como llegan: %+v", processingErr)
+	}
+}
+
+func TestProcessResultRejectsUnknownStatus(t *testing.T) {
+	// El status se valida antes de tocar la base de datos
+	service := &ResumeResultService{}
+
+	for _, status := range []string{"", "completed", strings.Repeat("x", 100)} {
+		_, err := service.ProcessResult(uuid.New(), &dto.AWSLambdaResponse{Status: status})
+		var fiberErr *fiber.Error
+		if !errors.As(err, &fiberErr) || fiberErr.Code != fiber.StatusBadRequest {
+			t.Errorf("status %q: se esperaba 400, se obtuvo %v", status, err)
+		}
+	}
+}
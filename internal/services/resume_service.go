@@ -1,160 +1,731 @@
 package services
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
-	"net/http"
+	"os"
+	"path"
 	"path/filepath"
 	"resume-backend-service/internal/domain"
 	"resume-backend-service/internal/dto"
 	"resume-backend-service/internal/repository"
-	"resume-backend-service/pkg/client"
 	"resume-backend-service/pkg/converter"
+	"resume-backend-service/pkg/pdfinspect"
+	"resume-backend-service/pkg/storage"
 	"resume-backend-service/pkg/utils"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type ResumeService struct {
-	presignedURLClient  *client.PresignedURLClient
+	storage             storage.Storage
+	unitOfWork          *repository.UnitOfWork
 	resumeRequestRepo   *repository.ResumeRequestRepository
+	ingestionJobRepo    *repository.IngestionJobRepository
+	processedResumeRepo *repository.ProcessedResumeRepository
+	resumeVersionRepo   *repository.ResumeVersionRepository
+	quotaService        *QuotaService
+	spoolDir            string
 }
 
-func NewResumeService(presignedURLClient *client.PresignedURLClient, resumeRequestRepo *repository.ResumeRequestRepository) *ResumeService {
+// NewResumeService crea el servicio. Los archivos recibidos se reciben en spoolDir y se
+// guardan con su trabajo de ingesta en la base de datos, hasta que la cola los convierte
+// y los sube al almacenamiento (S3 o local). spoolDir es local a cada instancia.
+func NewResumeService(store storage.Storage, unitOfWork *repository.UnitOfWork, resumeRequestRepo *repository.ResumeRequestRepository, ingestionJobRepo *repository.IngestionJobRepository, processedResumeRepo *repository.ProcessedResumeRepository, resumeVersionRepo *repository.ResumeVersionRepository, quotaService *QuotaService, spoolDir string) *ResumeService {
 	return &ResumeService{
-		presignedURLClient:  presignedURLClient,
+		storage:             store,
+		unitOfWork:          unitOfWork,
 		resumeRequestRepo:   resumeRequestRepo,
+		ingestionJobRepo:    ingestionJobRepo,
+		processedResumeRepo: processedResumeRepo,
+		resumeVersionRepo:   resumeVersionRepo,
+		quotaService:        quotaService,
+		spoolDir:            spoolDir,
 	}
 }
 
-// Regla de Negocio: Extensiones permitidas (Definidas en el Servicio)
-var allowedExtensions = map[string]bool{
-	".pdf":  true,
-	".txt":  true,
-	".docx": true,
-	// Nota: .doc (formato antiguo) no está soportado sin LibreOffice
+// Regla de Negocio: Extensiones permitidas. Son las que tiene registradas el
+// conversor, así una extensión nueva no requiere cambios en el servicio
+var allowedExtensions = extensionSet(converter.SupportedExtensions())
+
+// allowedFormats es la lista de formatos que se muestra en los errores de validación
+var allowedFormats = strings.Join(converter.SupportedExtensions(), ", ")
+
+// extensionSet arma el conjunto de búsqueda de una lista de extensiones
+func extensionSet(extensions []string) map[string]bool {
+	set := make(map[string]bool, len(extensions))
+	for _, ext := range extensions {
+		set[ext] = true
+	}
+	return set
 }
 
-func (s *ResumeService) ProcessResume(userID string, instructions string, language string, fileHeader *multipart.FileHeader) (dto.ResumeProcessorResponseDTO, error) {
+// maxImagePages es la cantidad máxima de imágenes (páginas) en una misma subida
+const maxImagePages = 20
 
-	// 1. Validación de Formato (Mantenido en el Service)
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	if !allowedExtensions[ext] {
-		// Retornamos un error de Fiber que el handler puede mapear a 400 Bad Request
-		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusBadRequest, "Formato de archivo no permitido. Permite: .pdf, .txt, .docx")
+// ProcessResume registra la solicitud y encola su ingesta. La conversión y la subida a S3
+// las hace el pool de workers (ver IngestResume), así la respuesta no espera a servicios externos.
+// Se recibe un solo archivo o varias imágenes JPEG/PNG, una por página del CV (ej: fotos de un
+// CV impreso); estas se guardan en el spool en un directorio y se unen en un único PDF.
+// Con force se procesa el archivo aunque el usuario ya tenga un resultado para el mismo
+// contenido (ver IngestResume).
+func (s *ResumeService) ProcessResume(userID string, instructions string, language string, force bool, fileHeaders []*multipart.FileHeader) (dto.ResumeProcessorResponseDTO, error) {
+	// 1-3. Validar los archivos y crear la solicitud
+	upload, err := s.prepareUpload(userID, instructions, language, force, multipartUploads(fileHeaders))
+	if err != nil {
+		return dto.ResumeProcessorResponseDTO{}, err
+	}
+	resumeRequest := upload.request
+
+	// 4. Guardar el archivo en el spool (se lee como stream, sin cargarlo en memoria)
+	spoolPath, err := s.saveToSpool(resumeRequest.RequestID.String(), upload.files)
+	if err != nil {
+		log.Printf("❌ Error al guardar archivo en el spool: %v", err)
+		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+	// El trabajo de ingesta lleva su copia del archivo (ver enqueueUpload)
+	defer os.RemoveAll(spoolPath)
+
+	// 5. Validar la cuota del usuario, guardar solicitud (estado: pending) y encolar la
+	// ingesta en la misma transacción
+	err = s.unitOfWork.Do(func(tx *sql.Tx) error {
+		return s.enqueueUpload(tx, resumeRequest, spoolPath)
+	})
+	if err != nil {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			return dto.ResumeProcessorResponseDTO{}, fiberErr
+		}
+		log.Printf("❌ Error al guardar solicitud: %v", err)
+		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+
+	log.Printf("📝 Solicitud encolada: request_id=%s, user_id=%s, filename=%s, tipo=%s, archivos=%d", resumeRequest.RequestID, userID, resumeRequest.OriginalFilename, resumeRequest.DetectedMIMEType, len(upload.files))
+
+	// 6. Retorno de DTO de éxito CON REQUEST_ID
+	return dto.ResumeProcessorResponseDTO{
+		Status:    "accepted",
+		Message:   "Solicitud encolada para procesamiento.",
+		RequestID: resumeRequest.RequestID.String(),
+	}, nil
+}
+
+// uploadFile es un archivo recibido: una parte del formulario multipart o un archivo
+// extraído de un ZIP (ver BatchService)
+type uploadFile struct {
+	Filename string
+	Size     int64
+	open     func() (multipart.File, error)
+}
+
+// Open abre el contenido del archivo
+func (f uploadFile) Open() (multipart.File, error) {
+	return f.open()
+}
+
+// multipartUploads adapta las partes del formulario multipart
+func multipartUploads(fileHeaders []*multipart.FileHeader) []uploadFile {
+	files := make([]uploadFile, len(fileHeaders))
+	for i, fileHeader := range fileHeaders {
+		files[i] = uploadFile{Filename: fileHeader.Filename, Size: fileHeader.Size, open: fileHeader.Open}
+	}
+	return files
+}
+
+// diskUpload adapta un archivo en disco recibido como filename
+func diskUpload(filename, filePath string, size int64) uploadFile {
+	return uploadFile{
+		Filename: filename,
+		Size:     size,
+		open: func() (multipart.File, error) {
+			return os.Open(filePath)
+		},
+	}
+}
+
+// preparedUpload es una subida validada con su solicitud, lista para el spool
+type preparedUpload struct {
+	request *domain.ResumeRequest
+	files   []uploadFile
+}
+
+// prepareUpload valida el formato, el contenido y el tamaño de los archivos de un CV y
+// crea su solicitud (sin guardarla). Los errores son *fiber.Error.
+func (s *ResumeService) prepareUpload(userID string, instructions string, language string, force bool, files []uploadFile) (*preparedUpload, error) {
+	if len(files) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Campo 'file' requerido.")
+	}
+	if len(files) > maxImagePages {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("Se permiten como máximo %d imágenes por CV.", maxImagePages))
+	}
+
+	// 1. Validar formato y contenido real de cada archivo (magic bytes) contra su extensión
+	var mimeType string
+	var totalSize int64
+	for _, file := range files {
+		detected, err := validateUpload(file)
+		if err != nil {
+			return nil, err
+		}
+		// Solo las imágenes se pueden subir en varias partes
+		if len(files) > 1 && detected != converter.MIMEJPEG && detected != converter.MIMEPNG {
+			return nil, fiber.NewError(fiber.StatusBadRequest,
+				"Solo se pueden enviar varios archivos si todos son imágenes (.jpg, .png).")
+		}
+		if mimeType == "" {
+			mimeType = detected
+		}
+		totalSize += file.Size
+	}
+
+	// 2. Validar el tamaño máximo de archivo (el total de las imágenes)
+	if err := s.quotaService.CheckFileSize(totalSize); err != nil {
+		return nil, err
 	}
 
-	// 2. Crear solicitud de procesamiento con request_id
+	// Los PDF se envían tal cual a la Lambda: se rechazan aquí los cifrados, dañados o
+	// con demasiadas páginas, que fallarían recién al procesarse
+	var pdfInfo *pdfinspect.Info
+	if mimeType == converter.MIMEPDF {
+		var err error
+		if pdfInfo, err = s.inspectUploadPDF(files[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Crear solicitud de procesamiento con request_id. En las subidas de varias
+	// imágenes se registra el nombre de la primera
+	file := files[0]
+	ext := strings.ToLower(filepath.Ext(file.Filename))
 	resumeRequest := domain.NewResumeRequest(
 		userID,
-		fileHeader.Filename,
+		file.Filename,
 		ext,
-		fileHeader.Size,
+		totalSize,
 		language,
 		instructions,
 	)
+	resumeRequest.DetectedMIMEType = mimeType
+	resumeRequest.ForceReprocess = force
 
-	// 3. Guardar solicitud en base de datos (estado: pending)
-	if err := s.resumeRequestRepo.Create(resumeRequest); err != nil {
-		log.Printf("❌ Error al guardar solicitud: %v", err)
-		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	// Codificación de los archivos de texto plano, para diagnosticar caracteres mal convertidos
+	if strings.HasPrefix(mimeType, converter.MIMEText) {
+		textEncoding, err := detectUploadEncoding(file)
+		if err != nil {
+			log.Printf("❌ Error al leer archivo recibido: %v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+		}
+		resumeRequest.TextEncoding = textEncoding
+	}
+	if pdfInfo != nil {
+		resumeRequest.PageCount = pdfInfo.Pages
+		resumeRequest.PDFProducer = truncateRunes(pdfInfo.Producer, maxPDFProducerLength)
 	}
 
-	log.Printf("📝 Solicitud creada: request_id=%s, user_id=%s, filename=%s", resumeRequest.RequestID, userID, fileHeader.Filename)
+	return &preparedUpload{request: resumeRequest, files: files}, nil
+}
 
-	// 4. Convertir archivo a PDF (si no lo es ya)
-	pdfBytes, pdfFilename, err := converter.ConvertToPDF(fileHeader)
+// enqueueUpload valida la cuota del usuario, guarda la solicitud (estado: pending) y
+// encola la ingesta del archivo del spool dentro de tx. El archivo se copia a la base de
+// datos con el trabajo, así lo puede ejecutar un worker de cualquier instancia.
+func (s *ResumeService) enqueueUpload(tx *sql.Tx, resumeRequest *domain.ResumeRequest, spoolPath string) error {
+	if err := s.quotaService.ReserveUpload(tx, resumeRequest.UserID, resumeRequest.FileSizeBytes); err != nil {
+		return err
+	}
+	if err := s.resumeRequestRepo.WithTx(tx).Create(resumeRequest); err != nil {
+		return err
+	}
+
+	name, err := filepath.Rel(s.spoolDir, spoolPath)
 	if err != nil {
-		log.Printf("Error al convertir archivo a PDF: %v", err)
-		// Marcar como fallida
-		s.resumeRequestRepo.MarkAsFailed(resumeRequest.RequestID, "Error al convertir archivo a PDF")
-		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar el archivo.")
+		return err
+	}
+	job := domain.NewIngestionJob(resumeRequest.RequestID, filepath.ToSlash(name))
+	job.BatchID = resumeRequest.BatchID
+	ingestionJobRepo := s.ingestionJobRepo.WithTx(tx)
+	if err := ingestionJobRepo.Create(job); err != nil {
+		return err
 	}
+	return storeSpool(ingestionJobRepo, job, spoolPath)
+}
 
-	log.Printf("Archivo convertido a PDF exitosamente: %s (%d bytes)", pdfFilename, len(pdfBytes))
+// IngestResume ejecuta un trabajo de la cola de ingesta: convierte el archivo del spool a
+// PDF y lo sube a S3. Si el usuario ya tiene una solicitud completada con el mismo PDF,
+// idioma e instrucciones (y no pidió force), se copia su versión activa en lugar de volver
+// a procesarlo. Los errores se retornan como domain.ProcessingError; Retryable indica si
+// el trabajo puede reintentarse. ctx acota las llamadas a servicios externos.
+func (s *ResumeService) IngestResume(ctx context.Context, job *domain.IngestionJob) error {
+	resumeRequest, err := s.resumeRequestRepo.FindByRequestID(job.RequestID)
+	if err != nil {
+		log.Printf("❌ Error al leer solicitud %s: %v", job.RequestID, err)
+		return domain.NewProcessingError(domain.ErrorCodePersistFailed, "Error al leer la solicitud", domain.StagePersistence, true)
+	}
 
-	// 5. Obtener URL firmada del servicio de presigned URLs
-	// IMPORTANTE: Se envía request_id para que sea incluido en la firma de la presigned URL
-	// Sanitizar instructions para metadata S3 (eliminar acentos, max 1500 chars)
-	sanitizedInstructions := utils.SanitizeForS3Metadata(instructions, 1500)
-	
-	log.Printf("🔑 Solicitando URL firmada - RequestID: %s, Filename: %s, Language: %s",
-		resumeRequest.RequestID, pdfFilename, language)
-
-	presignedResp, err := s.presignedURLClient.GetUploadURL(
-		pdfFilename,
-		"application/pdf",
-		resumeRequest.RequestID.String(),
-		language,
-		sanitizedInstructions,
-	)
+	// La solicitud pudo avanzar o fallar (p. ej. por el reaper) mientras el trabajo esperaba
+	if resumeRequest.Status != domain.StatusPending {
+		log.Printf("ℹ️  Solicitud %s ya no está pendiente (estado=%s), se omite la ingesta", job.RequestID, resumeRequest.Status)
+		return nil
+	}
+
+	// 1. Copiar el archivo del trabajo al spool local y convertirlo a PDF (si no lo es ya)
+	// El PDF se lee como stream (archivo del spool o temporal) en lugar de cargarse en memoria
+	spoolPath, cleanup, err := s.loadSpool(job)
 	if err != nil {
-		log.Printf("❌ Error al obtener URL firmada: %v", err)
-		s.resumeRequestRepo.MarkAsFailed(resumeRequest.RequestID, "Error al obtener URL firmada")
-		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al preparar la subida del archivo.")
+		log.Printf("❌ Error al leer archivo del trabajo %d: %v", job.ID, err)
+		return domain.NewProcessingError(domain.ErrorCodePersistFailed, "Error al leer el archivo de la solicitud", domain.StagePersistence, true)
+	}
+	defer cleanup()
+
+	pdfFile, err := convertSpool(ctx, spoolPath, resumeRequest.OriginalFilename)
+	if ctx.Err() != nil {
+		// Se agotó el tiempo del intento (lease): la conversión se reintenta
+		if pdfFile != nil {
+			pdfFile.Close()
+		}
+		log.Printf("⚠️  Conversión interrumpida para %s: %v", job.RequestID, ctx.Err())
+		return domain.NewProcessingError(domain.ErrorCodeTimeout, "Se agotó el tiempo para convertir el archivo", domain.StageConversion, true)
+	}
+	if err != nil {
+		log.Printf("Error al convertir archivo a PDF: %v", err)
+		return domain.NewProcessingError(domain.ErrorCodeConversionFailed, "Error al convertir archivo a PDF", domain.StageConversion, false)
 	}
+	defer pdfFile.Close()
+	pdfFilename := pdfFile.Filename
 
-	log.Printf("URL firmada obtenida exitosamente (expira en: %s)", presignedResp.ExpiresIn)
+	log.Printf("Archivo convertido a PDF exitosamente: %s (%d bytes)", pdfFilename, pdfFile.Size)
 
-	// 6. Subir el PDF a S3 usando la URL firmada con los metadatos
-	if err := s.uploadToS3(presignedResp.URL, pdfBytes, resumeRequest.RequestID.String(), language, sanitizedInstructions); err != nil {
-		log.Printf("Error al subir archivo a S3: %v", err)
-		s.resumeRequestRepo.MarkAsFailed(resumeRequest.RequestID, "Error al subir archivo a S3")
-		return dto.ResumeProcessorResponseDTO{}, fiber.NewError(fiber.StatusInternalServerError, "Error al subir el archivo.")
+	// 2. Identificar el contenido: una subida repetida reutiliza el resultado anterior
+	contentHash, err := contentSHA256(pdfFile, pdfFile.Size)
+	if err != nil {
+		log.Printf("Error al leer PDF convertido: %v", err)
+		return domain.NewProcessingError(domain.ErrorCodeConversionFailed, "Error al leer el PDF convertido", domain.StageConversion, true)
+	}
+	var duplicate *duplicateResult
+	if !resumeRequest.ForceReprocess {
+		duplicate, err = s.findDuplicate(resumeRequest, contentHash)
+		if err != nil {
+			// Sin copia se procesa normalmente: solo se pierde el ahorro
+			log.Printf("⚠️  Error al reutilizar resultado para %s, se procesa el archivo: %v", resumeRequest.RequestID, err)
+		}
+	}
+	cloned, err := s.saveContentHash(resumeRequest, contentHash, duplicate)
+	if err != nil {
+		log.Printf("❌ Error al guardar hash de %s: %v", resumeRequest.RequestID, err)
+		return domain.NewProcessingError(domain.ErrorCodePersistFailed, "Error al guardar la solicitud", domain.StagePersistence, true)
+	}
+	if cloned {
+		return nil
 	}
 
-	log.Printf("Archivo subido exitosamente a S3: %s", pdfFilename)
+	// 3. Subir el PDF al almacenamiento con los metadatos que lee la Lambda
+	inputURL, err := s.uploadPDF(ctx, resumeRequest, pdfFilename, pdfFile, pdfFile.Size)
+	if err != nil {
+		return err
+	}
 
-	// 7. Marcar solicitud como subida (estado: uploaded)
-	// La URL de S3 se puede extraer del presignedResp.URL (quitar query params)
-	s3InputURL := strings.Split(presignedResp.URL, "?")[0]
-	if err := s.resumeRequestRepo.MarkAsUploaded(resumeRequest.RequestID, s3InputURL); err != nil {
+	// 4. Marcar solicitud como subida (estado: uploaded)
+	if err := s.resumeRequestRepo.MarkAsUploaded(resumeRequest.RequestID, inputURL, domain.ActorSystem); err != nil {
 		log.Printf("⚠️  Error al actualizar estado de solicitud: %v", err)
 		// No fallar la operación, solo log
 	}
 
-	// 8. Retorno de DTO de éxito CON REQUEST_ID
-	return dto.ResumeProcessorResponseDTO{
-		Status:    "accepted",
-		Message:   "Solicitud encolada para procesamiento.",
-		RequestID: resumeRequest.RequestID.String(),
-	}, nil
+	return nil
+}
+
+// uploadPDF sube el PDF al almacenamiento y retorna su URL. El contenido se envía como
+// stream desde la posición actual de content, sin cargarlo en memoria. Los errores se
+// retornan como domain.ProcessingError (reintentables).
+func (s *ResumeService) uploadPDF(ctx context.Context, resumeRequest *domain.ResumeRequest, pdfFilename string, content io.Reader, size int64) (string, error) {
+	// IMPORTANTE: El request_id viaja en los metadatos (en S3, incluido en la firma)
+	// Sanitizar instructions para metadata S3 (eliminar acentos, max 1500 chars)
+	metadata := storage.Metadata{
+		storage.MetaRequestID:    resumeRequest.RequestID.String(),
+		storage.MetaLanguage:     resumeRequest.Language,
+		storage.MetaInstructions: utils.SanitizeForS3Metadata(resumeRequest.Instructions, 1500),
+	}
+	key := path.Join(resumeRequest.RequestID.String(), pdfFilename)
+
+	log.Printf("🔑 Subiendo PDF - RequestID: %s, Key: %s, Language: %s",
+		resumeRequest.RequestID, key, resumeRequest.Language)
+
+	inputURL, err := s.storage.Put(ctx, key, content, size, "application/pdf", metadata)
+	if errors.Is(err, storage.ErrPresign) {
+		log.Printf("❌ Error al obtener URL firmada: %v", err)
+		return "", domain.NewProcessingError(domain.ErrorCodePresignFailed, "Error al obtener URL firmada", domain.StageUpload, true)
+	}
+	if err != nil {
+		log.Printf("Error al subir archivo: %v", err)
+		return "", domain.NewProcessingError(domain.ErrorCodeUploadFailed, "Error al subir archivo a S3", domain.StageUpload, true)
+	}
+
+	log.Printf("Archivo subido exitosamente: %s", inputURL)
+	return inputURL, nil
+}
+
+// duplicateResult es el resultado de otra solicitud del usuario con el mismo contenido,
+// listo para copiarse (ver findDuplicate)
+type duplicateResult struct {
+	source         *domain.ResumeRequest
+	structuredData *dto.CVProcessedData
+}
+
+// findDuplicate busca otra solicitud completada del usuario con el mismo contenido, idioma
+// e instrucciones y lee los datos de su versión activa. Retorna nil si no hay ninguna.
+func (s *ResumeService) findDuplicate(resumeRequest *domain.ResumeRequest, contentHash string) (*duplicateResult, error) {
+	source, err := s.resumeRequestRepo.FindCompletedDuplicate(
+		resumeRequest.UserID,
+		contentHash,
+		resumeRequest.Language,
+		resumeRequest.Instructions,
+		resumeRequest.RequestID,
+	)
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	sourceResume, err := s.processedResumeRepo.FindByRequestID(source.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer CV procesado de origen: %w", err)
+	}
+	if sourceResume.ActiveVersionID == nil {
+		return nil, nil
+	}
+	sourceVersion, err := s.resumeVersionRepo.GetVersionByID(*sourceResume.ActiveVersionID)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer versión activa de origen: %w", err)
+	}
+	structuredData, err := sourceVersion.GetStructuredData()
+	if err != nil {
+		return nil, fmt.Errorf("error al leer datos de la versión de origen: %w", err)
+	}
+
+	return &duplicateResult{source: source, structuredData: structuredData}, nil
+}
+
+// saveContentHash guarda el hash del contenido de la solicitud y, si hay un duplicado,
+// la completa con una copia de su versión activa, todo en la misma transacción: una
+// solicitud completada por copia siempre tiene su hash. La reutilización es automática
+// (force la evita). Retorna true si la solicitud se completó con la copia; si ya no está
+// pendiente no se modifica.
+func (s *ResumeService) saveContentHash(resumeRequest *domain.ResumeRequest, contentHash string, duplicate *duplicateResult) (bool, error) {
+	cloned := false
+	err := s.unitOfWork.Do(func(tx *sql.Tx) error {
+		requestRepo := s.resumeRequestRepo.WithTx(tx)
+
+		current, err := requestRepo.FindByRequestIDForUpdate(resumeRequest.RequestID)
+		if err != nil {
+			return err
+		}
+		if current.Status != domain.StatusPending {
+			return nil
+		}
+		if err := requestRepo.SetContentSHA256(resumeRequest.RequestID, contentHash); err != nil {
+			return err
+		}
+		if duplicate == nil {
+			return nil
+		}
+
+		// Mismo recorrido que un resultado de Lambda: processing → completed
+		if err := requestRepo.MarkAsProcessing(resumeRequest.RequestID, domain.ActorSystem); err != nil {
+			return err
+		}
+		if err := s.processedResumeRepo.WithTx(tx).Create(domain.NewProcessedResume(resumeRequest.RequestID, resumeRequest.UserID)); err != nil {
+			return err
+		}
+		if _, err := s.resumeVersionRepo.WithTx(tx).CreateVersion(resumeRequest.RequestID, resumeRequest.UserID, duplicate.structuredData, "Versión inicial (copia)", "system"); err != nil {
+			return fmt.Errorf("error al crear versión copiada: %w", err)
+		}
+		if err := requestRepo.MarkAsCloned(resumeRequest.RequestID, duplicate.source.RequestID, duplicate.source.S3OutputURL, domain.ActorSystem); err != nil {
+			return err
+		}
+		cloned = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if cloned {
+		log.Printf("♻️  Solicitud %s completada con el resultado de %s (mismo contenido, sha256=%s)", resumeRequest.RequestID, duplicate.source.RequestID, contentHash)
+	}
+	return cloned, nil
+}
+
+// contentSHA256 calcula el SHA-256 (hex) de los primeros size bytes de r, sin mover la
+// posición de lectura
+func contentSHA256(r io.ReaderAt, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(r, 0, size)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// uploadToS3 sube un archivo a S3 usando una URL firmada
-// Los headers de metadata DEBEN coincidir exactamente con los usados al generar la presigned URL
-func (s *ResumeService) uploadToS3(presignedURL string, fileData []byte, requestID, language, instructions string) error {
-	req, err := http.NewRequest("PUT", presignedURL, bytes.NewReader(fileData))
+// FailIngestion marca como fallida la solicitud de un trabajo de ingesta que no se
+// reintentará más
+func (s *ResumeService) FailIngestion(job *domain.IngestionJob, processingErr domain.ProcessingError) {
+	err := s.resumeRequestRepo.MarkAsFailed(job.RequestID, processingErr, domain.ActorSystem)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		// La solicitud ya estaba finalizada; se conserva su estado
+		return
+	}
 	if err != nil {
-		return fmt.Errorf("error al crear request de subida: %w", err)
+		log.Printf("❌ Error al marcar solicitud como fallida: %v", err)
+	}
+}
+
+// validateUpload valida la extensión del archivo recibido y que su contenido le
+// corresponda; retorna el tipo detectado
+func validateUpload(file uploadFile) (string, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !allowedExtensions[ext] {
+		// Retornamos un error de Fiber que el handler puede mapear a 400 Bad Request
+		return "", fiber.NewError(fiber.StatusBadRequest, "Formato de archivo no permitido. Permite: "+allowedFormats)
 	}
 
-	// Headers requeridos - DEBEN coincidir con los metadatos de la presigned URL
-	req.Header.Set("Content-Type", "application/pdf")
-	req.Header.Set("x-amz-meta-request-id", requestID)
-	req.Header.Set("x-amz-meta-language", language)
-	if instructions != "" {
-		req.Header.Set("x-amz-meta-instructions", instructions) // Ya sanitizado
+	mimeType, err := detectUploadType(file)
+	if err != nil {
+		log.Printf("❌ Error al leer archivo recibido: %v", err)
+		return "", fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+	if mimeType == converter.MIMEUnknown {
+		return "", fiber.NewError(fiber.StatusUnsupportedMediaType, "No se reconoce el contenido del archivo. Permite: "+allowedFormats)
 	}
+	if !converter.MatchesExtension(ext, mimeType) {
+		log.Printf("⚠️  Tipo de archivo no coincide: filename=%s, detectado=%s", file.Filename, mimeType)
+		return "", fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("El contenido del archivo (%s) no corresponde a la extensión %s.", mimeType, ext))
+	}
+	return mimeType, nil
+}
 
-	log.Printf("🔄 Subiendo a S3 - RequestID: %s, Size: %d bytes, Language: %s",
-		requestID, len(fileData), language)
+// maxPDFProducerLength es el largo de la columna pdf_producer
+const maxPDFProducerLength = 255
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// inspectUploadPDF revisa la estructura del PDF recibido y retorna su información.
+// Rechaza con 422 los PDF dañados, incompletos, cifrados o con más páginas que el máximo.
+func (s *ResumeService) inspectUploadPDF(upload uploadFile) (*pdfinspect.Info, error) {
+	file, err := upload.Open()
 	if err != nil {
-		return fmt.Errorf("error al ejecutar subida: %w", err)
+		log.Printf("❌ Error al leer archivo recibido: %v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+	defer file.Close()
+
+	info, err := pdfinspect.Inspect(file, upload.Size)
+	switch {
+	case errors.Is(err, pdfinspect.ErrTruncated):
+		log.Printf("🚫 PDF incompleto: filename=%s, %v", upload.Filename, err)
+		return nil, fiber.NewError(fiber.StatusUnprocessableEntity,
+			"El PDF está incompleto; la subida o descarga pudo haberse interrumpido. Vuelve a exportarlo y súbelo de nuevo.")
+	case errors.Is(err, pdfinspect.ErrNotPDF), errors.Is(err, pdfinspect.ErrMalformed):
+		log.Printf("🚫 PDF dañado: filename=%s, %v", upload.Filename, err)
+		return nil, fiber.NewError(fiber.StatusUnprocessableEntity,
+			"El PDF está dañado y no se puede leer. Vuelve a exportarlo y súbelo de nuevo.")
+	case err != nil:
+		log.Printf("❌ Error al inspeccionar PDF: %v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error al procesar solicitud.")
+	}
+
+	if info.Encrypted {
+		log.Printf("🚫 PDF cifrado: filename=%s", upload.Filename)
+		return nil, fiber.NewError(fiber.StatusUnprocessableEntity,
+			"El PDF está protegido con contraseña o cifrado. Quita la protección y súbelo de nuevo.")
+	}
+	if err := s.quotaService.CheckPageCount(info.Pages); err != nil {
+		log.Printf("🚫 PDF con demasiadas páginas: filename=%s, páginas=%d", upload.Filename, info.Pages)
+		return nil, err
+	}
+	return info, nil
+}
+
+// truncateRunes recorta el texto a limit caracteres sin cortar una runa
+func truncateRunes(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit])
+}
+
+// saveToSpool guarda la subida en el spool y retorna su ruta. Un archivo se guarda
+// como <request_id><ext>; varias imágenes, en el directorio <request_id> con una
+// imagen por página, numeradas en el orden recibido.
+func (s *ResumeService) saveToSpool(requestID string, files []uploadFile) (string, error) {
+	if len(files) == 1 {
+		ext := strings.ToLower(filepath.Ext(files[0].Filename))
+		spoolPath := filepath.Join(s.spoolDir, requestID+ext)
+		return spoolPath, saveUpload(files[0], spoolPath)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		log.Printf("❌ S3 Response Status: %d, Headers: %v", resp.StatusCode, resp.Header)
-		return fmt.Errorf("error al subir archivo a S3 (status %d)", resp.StatusCode)
+	spoolPath := filepath.Join(s.spoolDir, requestID)
+	if err := os.Mkdir(spoolPath, 0o700); err != nil {
+		return "", err
+	}
+	for i, file := range files {
+		ext := strings.ToLower(filepath.Ext(file.Filename))
+		if err := saveUpload(file, filepath.Join(spoolPath, fmt.Sprintf("%03d%s", i+1, ext))); err != nil {
+			os.RemoveAll(spoolPath)
+			return "", err
+		}
 	}
+	return spoolPath, nil
+}
 
-	log.Printf("✅ S3 Response Status: %d", resp.StatusCode)
+// storeSpool guarda con el trabajo el archivo del spool en spoolPath, o cada imagen del
+// directorio de una subida de varias páginas (ver saveToSpool)
+func storeSpool(ingestionJobRepo *repository.IngestionJobRepository, job *domain.IngestionJob, spoolPath string) error {
+	files := map[string]string{job.SpoolPath: spoolPath}
+	info, err := os.Stat(spoolPath)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(spoolPath)
+		if err != nil {
+			return err
+		}
+		files = make(map[string]string, len(entries))
+		for _, entry := range entries {
+			files[path.Join(job.SpoolPath, entry.Name())] = filepath.Join(spoolPath, entry.Name())
+		}
+	}
+
+	for name, filePath := range files {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		err = ingestionJobRepo.SaveSpoolFile(job.ID, name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// loadSpool copia los archivos del trabajo a un directorio temporal del spool local y
+// retorna la ruta del archivo (o del directorio de imágenes) junto con la función que
+// elimina la copia
+func (s *ResumeService) loadSpool(job *domain.IngestionJob) (string, func(), error) {
+	names, err := s.ingestionJobRepo.ListSpoolFiles(job.ID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("el trabajo %d no tiene archivos en el spool", job.ID)
+	}
+
+	dir, err := os.MkdirTemp(s.spoolDir, "job-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	for _, name := range names {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(filePath, dir+string(filepath.Separator)) {
+			cleanup()
+			return "", nil, fmt.Errorf("ruta inválida en el spool: %s", name)
+		}
+		if err := copySpoolFile(s.ingestionJobRepo, job.ID, name, filePath); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(job.SpoolPath)), cleanup, nil
+}
+
+// copySpoolFile escribe en destPath el archivo name del trabajo
+func copySpoolFile(ingestionJobRepo *repository.IngestionJobRepository, jobID int64, name, destPath string) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o700); err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := ingestionJobRepo.CopySpoolFile(jobID, name, dst); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// convertSpool convierte a PDF el archivo del spool, o las imágenes del directorio
+// de una subida de varias páginas (ver saveToSpool). ctx interrumpe la conversión.
+func convertSpool(ctx context.Context, spoolPath, filename string) (*converter.PDFFile, error) {
+	info, err := os.Stat(spoolPath)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return converter.ConvertToPDF(ctx, spoolPath, filename)
+	}
+
+	// ReadDir retorna las entradas ordenadas por nombre: el orden de las páginas
+	entries, err := os.ReadDir(spoolPath)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		pages = append(pages, filepath.Join(spoolPath, entry.Name()))
+	}
+	return converter.ConvertImagesToPDF(ctx, pages, filename)
+}
+
+// detectUploadType identifica el tipo del archivo recibido por su contenido
+func detectUploadType(upload uploadFile) (string, error) {
+	file, err := upload.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return converter.DetectMIMEType(file, upload.Size), nil
+}
+
+// detectUploadEncoding identifica la codificación de un archivo de texto recibido
+func detectUploadEncoding(upload uploadFile) (string, error) {
+	file, err := upload.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return converter.DetectTextEncoding(file, upload.Size), nil
+}
+
+// saveUpload copia el archivo recibido a destPath
+func saveUpload(upload uploadFile, destPath string) error {
+	src, err := upload.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(destPath)
+		return err
+	}
+	return dst.Close()
+}
+
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/pkg/client"
+	"resume-backend-service/pkg/converter"
+	"resume-backend-service/pkg/httpx"
+	"resume-backend-service/pkg/storage"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// fakeS3Storage crea el driver s3presign contra un servidor de prueba que simula el
+// servicio de presigned URLs (POST /presign) y el bucket (PUT /bucket/...)
+func fakeS3Storage(tb testing.TB, handleUpload http.HandlerFunc) storage.Storage {
+	tb.Helper()
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/presign", func(w http.ResponseWriter, r *http.Request) {
+		var req dto.PresignedURLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(dto.PresignedURLResponse{URL: server.URL + "/bucket/" + req.Metadata.RequestID + "/" + req.Filename})
+	})
+	mux.HandleFunc("/bucket/", handleUpload)
+	server = httptest.NewServer(mux)
+	tb.Cleanup(server.Close)
+
+	presignedURLClient := client.NewPresignedURLClient(server.URL+"/presign", httpx.New("presign", httpx.Options{}))
+	return storage.NewPresignedS3Storage(presignedURLClient, httpx.New("s3-upload", httpx.Options{}))
+}
+
+func TestUploadPDFStreamsWithContentLength(t *testing.T) {
+	const content = "%PDF-1.4 contenido de prueba"
+	resumeRequest := domain.NewResumeRequest("user-1", "cv.docx", ".docx", 100, "esp", "")
+
+	store := fakeS3Storage(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(content)) || len(r.TransferEncoding) > 0 {
+			t.Errorf("Content-Length=%d Transfer-Encoding=%v", r.ContentLength, r.TransferEncoding)
+		}
+		if string(body) != content {
+			t.Errorf("body = %q", body)
+		}
+		if r.Header.Get("x-amz-meta-request-id") != resumeRequest.RequestID.String() {
+			t.Errorf("metadata request-id = %q", r.Header.Get("x-amz-meta-request-id"))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	service := &ResumeService{storage: store}
+	inputURL, err := service.uploadPDF(context.Background(), resumeRequest, "cv.pdf", strings.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("uploadPDF: %v", err)
+	}
+	if !strings.HasSuffix(inputURL, "/bucket/"+resumeRequest.RequestID.String()+"/cv.pdf") {
+		t.Errorf("URL = %q", inputURL)
+	}
+}
+
+// BenchmarkUploadPDF mide la memoria por subida (B/op): debe mantenerse acotada
+// sin importar el tamaño del archivo
+func BenchmarkUploadPDF(b *testing.B) {
+	const size = 10 * 1024 * 1024
+
+	store := fakeS3Storage(b, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	service := &ResumeService{storage: store}
+	resumeRequest := domain.NewResumeRequest("user-1", "cv.pdf", ".pdf", size, "esp", "")
+	content := strings.NewReader(strings.Repeat("0", size))
+
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		content.Seek(0, io.SeekStart)
+		if _, err := service.uploadPDF(context.Background(), resumeRequest, "cv.pdf", content, size); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// imageUploads arma los archivos de un formulario multipart con el campo 'file'
+// repetido, como los envía un cliente al subir varias imágenes
+func imageUploads(t *testing.T, names ...string) []uploadFile {
+	t.Helper()
+
+	img := image.NewGray(image.Rect(0, 0, 40, 60))
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, name := range names {
+		part, err := writer.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(name, ".png") {
+			err = png.Encode(part, img)
+		} else {
+			err = jpeg.Encode(part, img, nil)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	writer.Close()
+
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return multipartUploads(form.File["file"])
+}
+
+func TestSpoolImagePagesConvertsToSinglePDF(t *testing.T) {
+	service := &ResumeService{spoolDir: t.TempDir()}
+	uploads := imageUploads(t, "pagina-1.jpg", "pagina-2.png", "pagina-3.jpeg")
+	for _, upload := range uploads {
+		if _, err := validateUpload(upload); err != nil {
+			t.Fatalf("validateUpload(%s): %v", upload.Filename, err)
+		}
+	}
+
+	spoolPath, err := service.saveToSpool("req-1", uploads)
+	if err != nil {
+		t.Fatalf("saveToSpool: %v", err)
+	}
+	entries, _ := os.ReadDir(spoolPath)
+	if len(entries) != 3 || entries[0].Name() != "001.jpg" || entries[2].Name() != "003.jpeg" {
+		t.Fatalf("spool inesperado: %v", entries)
+	}
+
+	pdfFile, err := convertSpool(context.Background(), spoolPath, "pagina-1.jpg")
+	if err != nil {
+		t.Fatalf("convertSpool: %v", err)
+	}
+	defer pdfFile.Close()
+	content, _ := io.ReadAll(pdfFile)
+	if pdfFile.Filename != "pagina-1.pdf" || !bytes.HasPrefix(content, []byte("%PDF-")) {
+		t.Errorf("PDF inesperado: filename=%q", pdfFile.Filename)
+	}
+}
+
+func TestValidateUploadRejectsMismatchedImage(t *testing.T) {
+	upload := imageUploads(t, "foto.png")[0]
+	upload.Filename = "foto.jpg"
+	_, err := validateUpload(upload)
+	if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusUnsupportedMediaType {
+		t.Errorf("se esperaba 415, se obtuvo %v", err)
+	}
+}
+
+func TestValidateUploadListsRegisteredFormats(t *testing.T) {
+	upload := imageUploads(t, "foto.png")[0]
+	upload.Filename = "foto.gif"
+	_, err := validateUpload(upload)
+	fiberErr, ok := err.(*fiber.Error)
+	if !ok || fiberErr.Code != fiber.StatusBadRequest {
+		t.Fatalf("se esperaba 400, se obtuvo %v", err)
+	}
+	// El mensaje se arma desde el registro del conversor
+	for _, ext := range converter.SupportedExtensions() {
+		if !strings.Contains(fiberErr.Message, ext) {
+			t.Errorf("el mensaje %q no incluye %s", fiberErr.Message, ext)
+		}
+	}
+}
+
+// pdfUpload arma un archivo de formulario con un PDF de pages páginas
+func pdfUpload(t *testing.T, pages int, protect bool, truncate bool) uploadFile {
+	t.Helper()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetProducer("Microsoft® Word para Microsoft 365", true)
+	if protect {
+		pdf.SetProtection(0, "", "propietario")
+	}
+	for i := 0; i < pages; i++ {
+		pdf.AddPage()
+	}
+	var content bytes.Buffer
+	if err := pdf.Output(&content); err != nil {
+		t.Fatal(err)
+	}
+	data := content.Bytes()
+	if truncate {
+		data = data[:len(data)-200]
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, _ := writer.CreateFormFile("file", "cv.pdf")
+	part.Write(data)
+	writer.Close()
+
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return multipartUploads(form.File["file"])[0]
+}
+
+func TestInspectUploadPDF(t *testing.T) {
+	service := &ResumeService{quotaService: NewQuotaService(nil, domain.QuotaLimits{}, 0, 5)}
+
+	info, err := service.inspectUploadPDF(pdfUpload(t, 2, false, false))
+	if err != nil {
+		t.Fatalf("inspectUploadPDF: %v", err)
+	}
+	if info.Pages != 2 || info.Producer != "Microsoft® Word para Microsoft 365" {
+		t.Errorf("info inesperada: %+v", info)
+	}
+
+	tests := []struct {
+		name    string
+		upload  uploadFile
+		message string
+	}{
+		{"demasiadas páginas", pdfUpload(t, 6, false, false), "El PDF tiene 6 páginas y supera el máximo permitido de 5."},
+		{"cifrado", pdfUpload(t, 1, true, false), "El PDF está protegido con contraseña o cifrado. Quita la protección y súbelo de nuevo."},
+		{"incompleto", pdfUpload(t, 1, false, true), "El PDF está incompleto; la subida o descarga pudo haberse interrumpido. Vuelve a exportarlo y súbelo de nuevo."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.inspectUploadPDF(tt.upload)
+			fiberErr, ok := err.(*fiber.Error)
+			if !ok || fiberErr.Code != fiber.StatusUnprocessableEntity || fiberErr.Message != tt.message {
+				t.Errorf("se esperaba 422 %q, se obtuvo %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestContentSHA256DoesNotMoveReader(t *testing.T) {
+	content := strings.NewReader("%PDF-1.4 contenido")
+	content.Seek(5, io.SeekStart)
+
+	hash, err := contentSHA256(content, content.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sha256("%PDF-1.4 contenido")
+	if want := "69e44107d4f1743c89f02a83230404bea2de8204c4422c455e2e7d3e4b4ba3d6"; hash != want {
+		t.Errorf("hash = %q", hash)
+	}
+	if offset, _ := content.Seek(0, io.SeekCurrent); offset != 5 {
+		t.Errorf("la posición de lectura cambió a %d", offset)
+	}
+}
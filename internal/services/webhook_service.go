@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/pkg/netguard"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Regla de Negocio: límites de webhooks por usuario
+const (
+	maxWebhooksPerUser       = 10
+	webhookDeliveryLogLimit  = 100
+	webhookSecretPrefix      = "whsec_"
+	webhookSecretRandomBytes = 32
+	webhookResolveTimeout    = 5 * time.Second
+)
+
+// WebhookService administra las suscripciones de webhooks y encola los eventos a entregar
+type WebhookService struct {
+	webhookRepo       *repository.WebhookRepository
+	allowInsecureURLs bool
+}
+
+func NewWebhookService(webhookRepo *repository.WebhookRepository, allowInsecureURLs bool) *WebhookService {
+	return &WebhookService{
+		webhookRepo:       webhookRepo,
+		allowInsecureURLs: allowInsecureURLs,
+	}
+}
+
+// CreateSubscription registra un webhook y retorna su secreto de firma (solo esta vez)
+func (s *WebhookService) CreateSubscription(userID string, req dto.CreateWebhookRequest) (dto.CreateWebhookResponse, error) {
+	if err := s.validateURL(req.URL); err != nil {
+		return dto.CreateWebhookResponse{}, err
+	}
+
+	eventTypes, err := normalizeEventTypes(req.EventTypes)
+	if err != nil {
+		return dto.CreateWebhookResponse{}, err
+	}
+
+	existing, err := s.webhookRepo.FindSubscriptionsByUserID(userID)
+	if err != nil {
+		log.Printf("❌ Error al contar webhooks del usuario: %v", err)
+		return dto.CreateWebhookResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al registrar webhook.")
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return dto.CreateWebhookResponse{}, fiber.NewError(fiber.StatusConflict, "Se alcanzó el máximo de webhooks por usuario.")
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		log.Printf("❌ Error al generar secreto de webhook: %v", err)
+		return dto.CreateWebhookResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al registrar webhook.")
+	}
+
+	subscription := domain.NewWebhookSubscription(userID, req.URL, eventTypes, secret)
+	if err := s.webhookRepo.CreateSubscription(subscription); err != nil {
+		log.Printf("❌ Error al guardar webhook: %v", err)
+		return dto.CreateWebhookResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al registrar webhook.")
+	}
+
+	log.Printf("🪝 Webhook registrado: id=%s, user_id=%s, eventos=%v", subscription.ID, userID, eventTypes)
+
+	return dto.CreateWebhookResponse{
+		Status:       "success",
+		Message:      "Webhook registrado correctamente. Guarda el secreto: no se volverá a mostrar.",
+		Subscription: toWebhookSubscriptionItem(subscription),
+		Secret:       secret,
+	}, nil
+}
+
+// ListSubscriptions obtiene los webhooks del usuario
+func (s *WebhookService) ListSubscriptions(userID string) (dto.WebhookListResponse, error) {
+	subscriptions, err := s.webhookRepo.FindSubscriptionsByUserID(userID)
+	if err != nil {
+		log.Printf("❌ Error al obtener webhooks: %v", err)
+		return dto.WebhookListResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener webhooks.")
+	}
+
+	items := make([]dto.WebhookSubscriptionItem, len(subscriptions))
+	for i, subscription := range subscriptions {
+		items[i] = toWebhookSubscriptionItem(subscription)
+	}
+
+	return dto.WebhookListResponse{
+		Status:        "success",
+		Total:         len(items),
+		Subscriptions: items,
+	}, nil
+}
+
+// DeleteSubscription elimina un webhook del usuario
+func (s *WebhookService) DeleteSubscription(userID string, subscriptionID uuid.UUID) error {
+	err := s.webhookRepo.DeleteSubscription(subscriptionID, userID)
+	if err == sql.ErrNoRows {
+		return fiber.NewError(fiber.StatusNotFound, "Webhook no encontrado.")
+	}
+	if err != nil {
+		log.Printf("❌ Error al eliminar webhook: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Error al eliminar webhook.")
+	}
+
+	log.Printf("🪝 Webhook eliminado: id=%s, user_id=%s", subscriptionID, userID)
+	return nil
+}
+
+// ListDeliveries obtiene las últimas entregas de un webhook del usuario
+func (s *WebhookService) ListDeliveries(userID string, subscriptionID uuid.UUID) (dto.WebhookDeliveryListResponse, error) {
+	subscription, err := s.webhookRepo.FindSubscriptionByID(subscriptionID)
+	if err != nil || subscription.UserID != userID {
+		return dto.WebhookDeliveryListResponse{}, fiber.NewError(fiber.StatusNotFound, "Webhook no encontrado.")
+	}
+
+	deliveries, err := s.webhookRepo.FindDeliveriesBySubscriptionID(subscriptionID, webhookDeliveryLogLimit)
+	if err != nil {
+		log.Printf("❌ Error al obtener entregas de webhook: %v", err)
+		return dto.WebhookDeliveryListResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error al obtener entregas.")
+	}
+
+	items := make([]dto.WebhookDeliveryItem, len(deliveries))
+	for i, delivery := range deliveries {
+		items[i] = dto.WebhookDeliveryItem{
+			ID:                 delivery.ID,
+			EventID:            delivery.EventID.String(),
+			EventType:          delivery.EventType,
+			Status:             string(delivery.Status),
+			Attempts:           delivery.Attempts,
+			LastResponseStatus: delivery.LastResponseStatus,
+			LastError:          delivery.LastError,
+			Payload:            delivery.Payload,
+			CreatedAt:          delivery.CreatedAt,
+			DeliveredAt:        delivery.DeliveredAt,
+		}
+		if delivery.Status == domain.WebhookDeliveryPending {
+			nextAttemptAt := delivery.NextAttemptAt
+			items[i].NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	return dto.WebhookDeliveryListResponse{
+		Status:     "success",
+		Total:      len(items),
+		Deliveries: items,
+	}, nil
+}
+
+// Publish encola un evento del outbox para todos los webhooks activos del usuario que lo
+// escuchan. Los eventos que no se exponen por webhook se ignoran. El event_id del outbox
+// se conserva, así un reenvío del dispatcher no duplica entregas.
+func (s *WebhookService) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	if !domain.IsValidWebhookEventType(event.EventType) {
+		return nil
+	}
+
+	payload, err := json.Marshal(domain.WebhookEvent{
+		ID:        event.EventID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt.UTC(),
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("error al serializar evento de webhook %s: %w", event.EventType, err)
+	}
+
+	count, err := s.webhookRepo.EnqueueEvent(event.UserID, event.EventID, event.EventType, payload)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Printf("🪝 Evento %s encolado para %d webhook(s): event_id=%s", event.EventType, count, event.EventID)
+	}
+	return nil
+}
+
+// validateURL exige una URL absoluta https (http solo si se permite en la configuración)
+// cuyo host sea público: se rechazan los nombres internos y los que resuelven a loopback,
+// redes privadas, link-local o la metadata de la nube. El cliente vuelve a revisar la IP al
+// conectar, por si el DNS cambia después de registrar el webhook.
+func (s *WebhookService) validateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "URL de webhook inválida.")
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if !s.allowInsecureURLs {
+			return fiber.NewError(fiber.StatusBadRequest, "La URL del webhook debe usar https.")
+		}
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "La URL del webhook debe usar https.")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+	defer cancel()
+
+	if err := netguard.CheckHost(ctx, net.DefaultResolver, parsed.Hostname()); err != nil {
+		if errors.Is(err, netguard.ErrDisallowedAddress) {
+			return fiber.NewError(fiber.StatusBadRequest, "La URL del webhook debe apuntar a una dirección pública.")
+		}
+		return fiber.NewError(fiber.StatusBadRequest, "No se pudo resolver el host de la URL del webhook.")
+	}
+
+	return nil
+}
+
+// normalizeEventTypes valida el filtro de eventos y elimina duplicados
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Debe indicar al menos un evento. Permitidos: "+strings.Join(domain.WebhookEventTypes, ", "))
+	}
+
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, eventType := range eventTypes {
+		if !domain.IsValidWebhookEventType(eventType) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Evento no soportado: "+eventType+". Permitidos: "+strings.Join(domain.WebhookEventTypes, ", "))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+
+	return normalized, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(buf), nil
+}
+
+func toWebhookSubscriptionItem(subscription *domain.WebhookSubscription) dto.WebhookSubscriptionItem {
+	return dto.WebhookSubscriptionItem{
+		ID:                  subscription.ID.String(),
+		URL:                 subscription.URL,
+		EventTypes:          subscription.EventTypes,
+		Active:              subscription.Active,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		DisabledAt:          subscription.DisabledAt,
+		CreatedAt:           subscription.CreatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		allowInsecure bool
+		allowed       bool
+	}{
+		{"Public https", "https://93.184.216.34/hooks", false, true},
+		{"Public IPv6", "https://[2606:4700::1111]/hooks", false, true},
+		{"Insecure not allowed", "http://93.184.216.34/hooks", false, false},
+		{"Insecure allowed", "http://93.184.216.34/hooks", true, true},
+		{"Other scheme", "ftp://93.184.216.34/hooks", true, false},
+		{"Relative", "/hooks", false, false},
+		{"Loopback", "https://127.0.0.1/hooks", false, false},
+		{"Loopback IPv6", "https://[::1]:8443/hooks", false, false},
+		{"Localhost", "http://localhost:8080/hooks", true, false},
+		{"Private 10/8", "https://10.0.0.5/hooks", false, false},
+		{"Private 172.16/12", "https://172.20.1.1/hooks", false, false},
+		{"Private 192.168/16", "https://192.168.0.1/hooks", false, false},
+		{"Link-local metadata", "http://169.254.169.254/latest/meta-data/", true, false},
+		{"Unspecified", "https://0.0.0.0/hooks", false, false},
+		{"Internal hostname", "https://metadata.google.internal/computeMetadata/v1/", false, false},
+		{"Unresolvable", "https://webhooks.invalid/hooks", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewWebhookService(nil, tt.allowInsecure)
+
+			err := service.validateURL(tt.url)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("validateURL(%q) = %v, se esperaba válida", tt.url, err)
+				}
+				return
+			}
+			if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusBadRequest {
+				t.Errorf("validateURL(%q) = %v, se esperaba 400", tt.url, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/internal/services"
+	"sync"
+	"time"
+)
+
+// Parámetros de reintento de la cola de ingesta
+const (
+	ingestionBackoffBase    = 10 * time.Second
+	ingestionBackoffMax     = 5 * time.Minute
+	maxIngestionErrorLength = 1000
+)
+
+// IngestionWorker ejecuta la cola de ingesta con un pool de goroutines. Cada una reserva
+// trabajos con SKIP LOCKED, así varias instancias del servicio comparten la misma cola.
+// Los archivos de los trabajos terminados se eliminan con MarkSucceeded y MarkDead.
+type IngestionWorker struct {
+	ingestionJobRepo *repository.IngestionJobRepository
+	resumeService    *services.ResumeService
+	concurrency      int
+	interval         time.Duration
+	lease            time.Duration
+	maxAttempts      int
+	maxPerBatch      int
+	stop             chan struct{}
+	wg               sync.WaitGroup
+}
+
+// NewIngestionWorker crea el pool. Cada trabajo se intenta hasta maxAttempts veces; lease es
+// el tiempo máximo de un intento antes de que otro worker lo considere abandonado.
+// maxPerBatch acota los trabajos de un mismo lote que se ejecutan a la vez (0 = sin límite).
+func NewIngestionWorker(ingestionJobRepo *repository.IngestionJobRepository, resumeService *services.ResumeService, concurrency int, interval, lease time.Duration, maxAttempts, maxPerBatch int) *IngestionWorker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &IngestionWorker{
+		ingestionJobRepo: ingestionJobRepo,
+		resumeService:    resumeService,
+		concurrency:      concurrency,
+		interval:         interval,
+		lease:            lease,
+		maxAttempts:      maxAttempts,
+		maxPerBatch:      maxPerBatch,
+		stop:             make(chan struct{}),
+	}
+}
+
+// Start lanza las goroutines del pool en segundo plano
+func (w *IngestionWorker) Start() {
+	log.Printf("📥 Cola de ingesta iniciada (workers=%d, intervalo=%s, intentos=%d, por lote=%d)", w.concurrency, w.interval, w.maxAttempts, w.maxPerBatch)
+
+	for i := 0; i < w.concurrency; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+
+			ticker := time.NewTicker(w.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-w.stop:
+					return
+				case <-ticker.C:
+					w.runQueued()
+				}
+			}
+		}()
+	}
+}
+
+// Stop detiene el pool y espera a que terminen los trabajos en curso
+func (w *IngestionWorker) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+	log.Println("📥 Cola de ingesta detenida")
+}
+
+// runQueued ejecuta trabajos hasta vaciar la cola
+func (w *IngestionWorker) runQueued() {
+	for {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+
+		job, err := w.ingestionJobRepo.ClaimNext(w.lease, w.maxPerBatch)
+		if err != nil {
+			log.Printf("❌ Error al obtener trabajos de ingesta: %v", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+
+		w.run(job)
+	}
+}
+
+// run ejecuta un trabajo y registra su resultado: terminado, reintento o dead-letter
+func (w *IngestionWorker) run(job *domain.IngestionJob) {
+	var ingestErr error
+	if job.Attempts > w.maxAttempts {
+		// El último intento se interrumpió (p. ej. el proceso se cayó durante la subida)
+		ingestErr = domain.NewProcessingError(domain.ErrorCodeUploadFailed, "Se agotaron los intentos de ingesta", domain.StageUpload, false)
+	} else {
+		// El intento no puede pasar del lease: después otro worker puede reservar el trabajo
+		ctx, cancel := context.WithTimeout(context.Background(), w.lease)
+		ingestErr = w.resumeService.IngestResume(ctx, job)
+		cancel()
+	}
+
+	if ingestErr == nil {
+		if err := w.ingestionJobRepo.MarkSucceeded(job.ID); err != nil {
+			log.Printf("❌ Error al registrar trabajo de ingesta %d: %v", job.ID, err)
+			return
+		}
+		log.Printf("✅ Ingesta completada: job_id=%d, request_id=%s, intento=%d", job.ID, job.RequestID, job.Attempts)
+		return
+	}
+
+	var processingErr domain.ProcessingError
+	if !errors.As(ingestErr, &processingErr) {
+		processingErr = domain.NewProcessingError(domain.ErrorCodeUploadFailed, ingestErr.Error(), domain.StageUpload, true)
+	}
+
+	lastError := processingErr.Error()
+	if len(lastError) > maxIngestionErrorLength {
+		lastError = lastError[:maxIngestionErrorLength]
+	}
+
+	if processingErr.Retryable && job.Attempts < w.maxAttempts {
+		nextAttemptAt := time.Now().Add(ingestionBackoff(job.Attempts))
+		if err := w.ingestionJobRepo.MarkRetry(job.ID, lastError, nextAttemptAt); err != nil {
+			log.Printf("❌ Error al reprogramar trabajo de ingesta %d: %v", job.ID, err)
+			return
+		}
+		log.Printf("⚠️  Ingesta fallida (intento %d/%d), se reintenta a las %s: request_id=%s, error=%s",
+			job.Attempts, w.maxAttempts, nextAttemptAt.Format(time.RFC3339), job.RequestID, lastError)
+		return
+	}
+
+	if err := w.ingestionJobRepo.MarkDead(job.ID, lastError); err != nil {
+		log.Printf("❌ Error al mover trabajo de ingesta %d a dead-letter: %v", job.ID, err)
+		return
+	}
+	w.resumeService.FailIngestion(job, processingErr)
+	log.Printf("💀 Ingesta descartada tras %d intento(s): request_id=%s, error=%s", job.Attempts, job.RequestID, lastError)
+}
+
+// ingestionBackoff retorna la espera antes del siguiente intento: 10s, 20s, 40s... hasta 5m
+func ingestionBackoff(attempts int) time.Duration {
+	backoff := ingestionBackoffBase
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= ingestionBackoffMax {
+			return ingestionBackoffMax
+		}
+	}
+	return backoff
+}
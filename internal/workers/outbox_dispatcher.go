@@ -0,0 +1,157 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"resume-backend-service/internal/outbox"
+	"resume-backend-service/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parámetros de publicación del outbox
+const (
+	outboxBatchSize      = 100
+	outboxBackoffBase    = time.Second
+	outboxBackoffMax     = 5 * time.Minute
+	outboxPublishTimeout = 10 * time.Second
+	maxOutboxErrorLength = 1000
+)
+
+// OutboxDispatcher publica los eventos del outbox en orden por request_id. Un evento se
+// marca como publicado solo después de que el publisher lo aceptó (al menos una vez).
+type OutboxDispatcher struct {
+	unitOfWork *repository.UnitOfWork
+	outboxRepo *repository.OutboxRepository
+	publisher  outbox.Publisher
+	interval   time.Duration
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// NewOutboxDispatcher crea el dispatcher, que revisa el outbox cada interval
+func NewOutboxDispatcher(unitOfWork *repository.UnitOfWork, outboxRepo *repository.OutboxRepository, publisher outbox.Publisher, interval time.Duration) *OutboxDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxDispatcher{
+		unitOfWork: unitOfWork,
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		interval:   interval,
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+}
+
+// Start lanza el dispatcher en segundo plano
+func (d *OutboxDispatcher) Start() {
+	log.Printf("📤 Dispatcher del outbox iniciado (intervalo=%s)", d.interval)
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-ticker.C:
+				d.dispatchPending()
+			}
+		}
+	}()
+}
+
+// Stop detiene el dispatcher y espera a que termine el lote en curso
+func (d *OutboxDispatcher) Stop() {
+	d.cancel()
+	<-d.done
+	log.Println("📤 Dispatcher del outbox detenido")
+}
+
+// dispatchPending publica lotes hasta vaciar el outbox
+func (d *OutboxDispatcher) dispatchPending() {
+	for d.ctx.Err() == nil {
+		processed, err := d.dispatchBatch()
+		if err != nil {
+			log.Printf("❌ Error al publicar eventos del outbox: %v", err)
+			return
+		}
+		if processed < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// dispatchBatch publica un lote dentro de una transacción con el lock del dispatcher, para
+// que solo una instancia publique a la vez. Si un evento falla, los siguientes de la misma
+// solicitud esperan a que se reintente. Retorna cuántos eventos se leyeron.
+func (d *OutboxDispatcher) dispatchBatch() (int, error) {
+	processed := 0
+
+	err := d.unitOfWork.Do(func(tx *sql.Tx) error {
+		outboxRepo := d.outboxRepo.WithTx(tx)
+
+		acquired, err := outboxRepo.TryLockDispatcher()
+		if err != nil || !acquired {
+			return err
+		}
+
+		events, err := outboxRepo.FindPending(outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		processed = len(events)
+
+		blocked := make(map[uuid.UUID]bool)
+		for _, event := range events {
+			if blocked[event.RequestID] {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(d.ctx, outboxPublishTimeout)
+			publishErr := d.publisher.Publish(ctx, event)
+			cancel()
+
+			if publishErr == nil {
+				if err := outboxRepo.MarkPublished(event.ID); err != nil {
+					return err
+				}
+				continue
+			}
+
+			blocked[event.RequestID] = true
+			lastError := publishErr.Error()
+			if len(lastError) > maxOutboxErrorLength {
+				lastError = lastError[:maxOutboxErrorLength]
+			}
+			nextAttemptAt := time.Now().Add(outboxBackoff(event.Attempts + 1))
+			log.Printf("⚠️  Error al publicar evento %s (%s), reintento a las %s: %v",
+				event.EventID, event.EventType, nextAttemptAt.Format(time.RFC3339), publishErr)
+			if err := outboxRepo.MarkFailed(event.ID, lastError, nextAttemptAt); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return processed, err
+}
+
+// outboxBackoff retorna la espera antes del siguiente intento: 1s, 2s, 4s... hasta 5m
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBackoffBase
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxBackoffMax {
+			return outboxBackoffMax
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,85 @@
+package workers
+
+import (
+	"log"
+	"resume-backend-service/internal/repository"
+	"time"
+)
+
+// staleRequestBatchSize limita cuántas solicitudes se marcan como fallidas por iteración
+const staleRequestBatchSize = 100
+
+// StaleRequestReaper marca como fallidas las solicitudes cuyo callback nunca llegó
+type StaleRequestReaper struct {
+	resumeRequestRepo *repository.ResumeRequestRepository
+	interval          time.Duration
+	timeout           time.Duration
+	stop              chan struct{}
+	done              chan struct{}
+}
+
+// NewStaleRequestReaper crea el worker. Cada interval busca solicitudes en
+// pending/uploaded/processing con más antigüedad que timeout; las pending que la cola de
+// ingesta todavía tiene en cola o en curso se omiten.
+func NewStaleRequestReaper(resumeRequestRepo *repository.ResumeRequestRepository, interval, timeout time.Duration) *StaleRequestReaper {
+	return &StaleRequestReaper{
+		resumeRequestRepo: resumeRequestRepo,
+		interval:          interval,
+		timeout:           timeout,
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+}
+
+// Start lanza el worker en segundo plano
+func (r *StaleRequestReaper) Start() {
+	log.Printf("🧹 Reaper de solicitudes iniciado (intervalo=%s, timeout=%s)", r.interval, r.timeout)
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.reap()
+			}
+		}
+	}()
+}
+
+// Stop detiene el worker y espera a que termine la iteración en curso
+func (r *StaleRequestReaper) Stop() {
+	close(r.stop)
+	<-r.done
+	log.Println("🧹 Reaper de solicitudes detenido")
+}
+
+// reap procesa lotes hasta que no queden solicitudes vencidas
+func (r *StaleRequestReaper) reap() {
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		requests, err := r.resumeRequestRepo.FailStaleRequests(r.timeout, staleRequestBatchSize)
+		if err != nil {
+			log.Printf("❌ Error al marcar solicitudes vencidas: %v", err)
+			return
+		}
+
+		for _, request := range requests {
+			log.Printf("⏰ Solicitud %s marcada como fallida por timeout (%s sin resultado)", request.RequestID, r.timeout)
+		}
+
+		if len(requests) < staleRequestBatchSize {
+			return
+		}
+	}
+}
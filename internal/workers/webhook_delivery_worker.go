@@ -0,0 +1,171 @@
+package workers
+
+import (
+	"errors"
+	"log"
+	"resume-backend-service/internal/domain"
+	"resume-backend-service/internal/repository"
+	"resume-backend-service/pkg/client"
+	"sync"
+	"time"
+)
+
+// Parámetros de entrega de webhooks
+const (
+	webhookDeliveryBatchSize = 20
+	webhookBackoffBase       = 30 * time.Second
+	webhookBackoffMax        = 6 * time.Hour
+	maxWebhookErrorLength    = 1000
+)
+
+// WebhookDeliveryWorker envía las entregas pendientes de webhooks y reintenta
+// las fallidas con backoff exponencial
+type WebhookDeliveryWorker struct {
+	webhookRepo   *repository.WebhookRepository
+	webhookClient *client.WebhookClient
+	interval      time.Duration
+	lease         time.Duration
+	maxAttempts   int
+	disableAfter  int
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewWebhookDeliveryWorker crea el worker. Cada entrega se intenta hasta maxAttempts veces
+// y una suscripción se desactiva tras disableAfter intentos fallidos seguidos.
+func NewWebhookDeliveryWorker(webhookRepo *repository.WebhookRepository, webhookClient *client.WebhookClient, interval, timeout time.Duration, maxAttempts, disableAfter int) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		webhookRepo:   webhookRepo,
+		webhookClient: webhookClient,
+		interval:      interval,
+		// La reserva dura más que el envío, así no se duplica mientras sigue en curso
+		lease:        2 * timeout,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start lanza el worker en segundo plano
+func (w *WebhookDeliveryWorker) Start() {
+	log.Printf("🪝 Worker de webhooks iniciado (intervalo=%s, intentos=%d)", w.interval, w.maxAttempts)
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.deliverDue()
+			}
+		}
+	}()
+}
+
+// Stop detiene el worker y espera a que terminen los envíos en curso
+func (w *WebhookDeliveryWorker) Stop() {
+	close(w.stop)
+	<-w.done
+	log.Println("🪝 Worker de webhooks detenido")
+}
+
+// deliverDue envía lotes de entregas vencidas hasta vaciar la cola
+func (w *WebhookDeliveryWorker) deliverDue() {
+	for {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+
+		deliveries, err := w.webhookRepo.ClaimDueDeliveries(webhookDeliveryBatchSize, w.lease)
+		if err != nil {
+			log.Printf("❌ Error al obtener entregas de webhook: %v", err)
+			return
+		}
+
+		// Enviar el lote en paralelo para que un destino lento no frene al resto
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(delivery *domain.WebhookDelivery) {
+				defer wg.Done()
+				w.deliver(delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookDeliveryBatchSize {
+			return
+		}
+	}
+}
+
+// deliver envía una entrega y registra su resultado
+func (w *WebhookDeliveryWorker) deliver(delivery *domain.WebhookDelivery) {
+	status, sendErr := w.webhookClient.Send(delivery.URL, delivery.Secret, delivery.EventID.String(), delivery.EventType, delivery.Payload)
+	if sendErr == nil {
+		if err := w.webhookRepo.MarkDeliverySucceeded(delivery.ID, status); err != nil {
+			log.Printf("❌ Error al registrar entrega %d: %v", delivery.ID, err)
+		}
+		log.Printf("✅ Webhook entregado: delivery_id=%d, evento=%s, status=%d", delivery.ID, delivery.EventType, status)
+		return
+	}
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	var nextAttemptAt *time.Time
+	if delivery.Attempts < w.maxAttempts {
+		next := time.Now().Add(webhookBackoff(delivery.Attempts))
+		nextAttemptAt = &next
+	}
+
+	// Al usuario solo se le muestra el motivo genérico; el detalle queda en los logs
+	lastError := sendErr.Error()
+	if len(lastError) > maxWebhookErrorLength {
+		lastError = lastError[:maxWebhookErrorLength]
+	}
+	logDetail := lastError
+	var webhookErr *client.WebhookError
+	if errors.As(sendErr, &webhookErr) {
+		logDetail = webhookErr.Detail()
+	}
+
+	disabled, err := w.webhookRepo.MarkDeliveryFailed(delivery.ID, responseStatus, lastError, nextAttemptAt, w.disableAfter)
+	if err != nil {
+		log.Printf("❌ Error al registrar fallo de entrega %d: %v", delivery.ID, err)
+		return
+	}
+
+	if nextAttemptAt != nil {
+		log.Printf("⚠️  Webhook fallido (intento %d/%d), se reintenta a las %s: delivery_id=%d, error=%v",
+			delivery.Attempts, w.maxAttempts, nextAttemptAt.Format(time.RFC3339), delivery.ID, logDetail)
+	} else {
+		log.Printf("❌ Webhook descartado tras %d intentos: delivery_id=%d, error=%v", delivery.Attempts, delivery.ID, logDetail)
+	}
+
+	if disabled {
+		log.Printf("🚫 Webhook %s desactivado tras %d fallos consecutivos", delivery.SubscriptionID, w.disableAfter)
+	}
+}
+
+// webhookBackoff retorna la espera antes del siguiente intento: 30s, 1m, 2m, 4m... hasta 6h
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBackoffBase
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookBackoffMax {
+			return webhookBackoffMax
+		}
+	}
+	return backoff
+}
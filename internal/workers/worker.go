@@ -0,0 +1,10 @@
+package workers
+
+// Worker es un proceso en segundo plano que se inicia con la aplicación
+// y debe detenerse limpiamente al apagarla
+type Worker interface {
+	// Start lanza el worker en su propia goroutine y retorna de inmediato
+	Start()
+	// Stop detiene el worker y espera a que termine la iteración en curso
+	Stop()
+}
@@ -0,0 +1,38 @@
+-- ============================================================================
+-- MIGRATION 003: Add Callback Deliveries
+-- Descripción: Registro de entregas del callback de AWS Lambda (idempotencia)
+-- Fecha: 2025-12-08
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: callback_deliveries
+-- Propósito: Registrar cada resultado recibido por request_id y cuántas veces
+--            llegó, para que los reintentos de Lambda no tengan efectos duplicados
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS callback_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Solicitud a la que pertenece el resultado
+    request_id UUID NOT NULL REFERENCES resume_requests(request_id) ON DELETE CASCADE,
+
+    -- SHA-256 del contenido del resultado (status + output_file + structured_data)
+    payload_hash CHAR(64) NOT NULL,
+
+    -- Status reportado por Lambda en esta entrega
+    lambda_status VARCHAR(20) NOT NULL,
+
+    -- Cantidad de veces que llegó este mismo resultado
+    delivery_count INT NOT NULL DEFAULT 1,
+
+    -- Resultado de procesar la entrega (NULL mientras se procesa)
+    outcome VARCHAR(20),
+
+    -- Timestamps
+    first_received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(request_id, payload_hash),
+    CONSTRAINT valid_delivery_outcome CHECK (outcome IN ('applied', 'rejected', 'ignored', 'error'))
+);
+
+CREATE INDEX idx_callback_deliveries_request_id ON callback_deliveries(request_id);
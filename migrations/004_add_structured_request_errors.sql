@@ -0,0 +1,21 @@
+-- ============================================================================
+-- MIGRATION 004: Add Structured Request Errors
+-- Descripción: Detalle estructurado de los fallos de procesamiento
+-- Fecha: 2025-12-09
+-- ============================================================================
+
+-- Código de error estable (ej: CORRUPT_PDF, LAMBDA_TIMEOUT, UPLOAD_FAILED)
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS error_code VARCHAR(50);
+
+-- Etapa del pipeline donde ocurrió el fallo (ej: conversion, upload, extraction)
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS error_stage VARCHAR(50);
+
+-- Indica si reintentar la misma solicitud puede funcionar (fallo transitorio)
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS error_retryable BOOLEAN;
+
+-- Índice para analizar fallos por código
+CREATE INDEX IF NOT EXISTS idx_resume_requests_error_code ON resume_requests(error_code)
+WHERE error_code IS NOT NULL;
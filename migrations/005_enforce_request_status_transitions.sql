@@ -0,0 +1,55 @@
+-- ============================================================================
+-- MIGRATION 005: Enforce Resume Request Status Transitions
+-- Descripción: Máquina de estados de resume_requests validada en la base de datos
+-- Fecha: 2025-12-10
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: resume_request_status_transitions
+-- Propósito: Transiciones de estado permitidas (debe coincidir con
+--            allowedTransitions en internal/domain/resume_request.go)
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS resume_request_status_transitions (
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    PRIMARY KEY (from_status, to_status)
+);
+
+INSERT INTO resume_request_status_transitions (from_status, to_status) VALUES
+    ('pending', 'uploaded'),
+    ('pending', 'processing'),
+    ('pending', 'failed'),
+    ('uploaded', 'processing'),
+    ('uploaded', 'failed'),
+    ('processing', 'completed'),
+    ('processing', 'failed')
+ON CONFLICT DO NOTHING;
+
+-- ----------------------------------------------------------------------------
+-- TRIGGER: Rechazar transiciones no permitidas, incluso desde escritores
+--          externos a la aplicación
+-- ----------------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION enforce_resume_request_status_transition()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = OLD.status THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM resume_request_status_transitions
+        WHERE from_status = OLD.status AND to_status = NEW.status
+    ) THEN
+        RAISE EXCEPTION 'Transición de estado inválida para %: % -> %',
+            OLD.request_id, OLD.status, NEW.status
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_enforce_resume_request_status_transition
+    BEFORE UPDATE OF status ON resume_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION enforce_resume_request_status_transition();
@@ -0,0 +1,56 @@
+-- ============================================================================
+-- MIGRATION 006: Create Resume Request Events
+-- Descripción: Historial de transiciones de estado de cada solicitud (timeline)
+-- Fecha: 2025-12-11
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: resume_request_events
+-- Propósito: Registrar cada cambio de estado con su actor y detalle, para
+--            diagnosticar solicitudes sin tener que revisar los logs
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS resume_request_events (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- Solicitud a la que pertenece el evento
+    request_id UUID NOT NULL REFERENCES resume_requests(request_id) ON DELETE CASCADE,
+
+    -- Transición (from_status es NULL en la creación)
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+
+    -- Quién provocó el cambio
+    actor VARCHAR(20) NOT NULL,
+
+    -- Información adicional (ej: detalle del error, archivo de salida)
+    detail JSONB,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT valid_event_actor CHECK (actor IN ('user', 'system', 'callback', 'reaper'))
+);
+
+CREATE INDEX idx_resume_request_events_request_id ON resume_request_events(request_id, created_at);
+
+-- ----------------------------------------------------------------------------
+-- BACKFILL: Reconstruir el historial aproximado de las solicitudes existentes
+-- a partir de sus timestamps
+-- ----------------------------------------------------------------------------
+INSERT INTO resume_request_events (request_id, from_status, to_status, actor, created_at)
+SELECT request_id, NULL, 'pending', 'user', created_at
+FROM resume_requests;
+
+INSERT INTO resume_request_events (request_id, from_status, to_status, actor, created_at)
+SELECT request_id, 'pending', 'uploaded', 'system', uploaded_at
+FROM resume_requests
+WHERE uploaded_at IS NOT NULL;
+
+INSERT INTO resume_request_events (request_id, from_status, to_status, actor, detail, created_at)
+SELECT request_id,
+       CASE WHEN uploaded_at IS NOT NULL THEN 'uploaded' ELSE 'pending' END,
+       status,
+       CASE WHEN status = 'completed' THEN 'callback' ELSE 'system' END,
+       CASE WHEN status = 'failed' THEN jsonb_build_object('message', error_message, 'code', error_code) END,
+       completed_at
+FROM resume_requests
+WHERE status IN ('completed', 'failed') AND completed_at IS NOT NULL;
@@ -0,0 +1,67 @@
+-- ============================================================================
+-- MIGRATION 007: Create Webhooks
+-- Descripción: Suscripciones de webhooks por usuario y registro de entregas
+-- Fecha: 2025-12-12
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: webhook_subscriptions
+-- Propósito: URLs registradas por cada usuario para recibir eventos del ciclo
+--            de vida de sus CVs
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id UUID PRIMARY KEY,
+
+    -- Dueño de la suscripción (subject del JWT)
+    user_id VARCHAR(255) NOT NULL,
+
+    -- Destino y filtro de eventos (ej: {resume.completed,resume.failed})
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+
+    -- Secreto para firmar los payloads con HMAC-SHA256
+    secret VARCHAR(100) NOT NULL,
+
+    -- Se desactiva automáticamente tras demasiados fallos consecutivos
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP WITH TIME ZONE,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_subscriptions_user_id ON webhook_subscriptions(user_id);
+
+-- ----------------------------------------------------------------------------
+-- TABLA: webhook_deliveries
+-- Propósito: Cola y registro de entregas. Cada evento genera una fila por
+--            suscripción interesada; el worker la reintenta con backoff
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+
+    -- Evento entregado (event_id se repite en todas las suscripciones del evento)
+    event_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- Estado de la entrega
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_response_status INT,
+    last_error TEXT,
+
+    -- Timestamps
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT valid_webhook_delivery_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+-- Búsqueda de entregas pendientes por el worker
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at DESC);
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- MIGRATION 008: Create Outbox Events
+-- Descripción: Outbox transaccional de eventos de dominio
+-- Fecha: 2025-12-13
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: outbox_events
+-- Propósito: Eventos de dominio (resume.*, version.*) escritos en la misma
+--            transacción que el cambio de estado. El dispatcher los publica
+--            al menos una vez y en orden por request_id
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+
+    -- ID estable del evento (los consumidores deduplican por este valor)
+    event_id UUID NOT NULL DEFAULT uuid_generate_v4() UNIQUE,
+
+    -- Agregado al que pertenece el evento; define el orden de publicación
+    request_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+
+    -- Tipo (ej: resume.completed, version.activated) y contenido del evento
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- Estado de publicación (published_at NULL = pendiente)
+    published_at TIMESTAMP WITH TIME ZONE,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Búsqueda de eventos pendientes por el dispatcher
+CREATE INDEX idx_outbox_events_pending ON outbox_events(id) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_request_pending ON outbox_events(request_id, id) WHERE published_at IS NULL;
+
+-- Los webhooks se encolan desde el outbox, que puede publicar un evento más de una vez
+CREATE UNIQUE INDEX idx_webhook_deliveries_subscription_event ON webhook_deliveries(subscription_id, event_id);
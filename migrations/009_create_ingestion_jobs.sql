@@ -0,0 +1,43 @@
+-- ============================================================================
+-- MIGRATION 009: Create Ingestion Jobs
+-- Descripción: Cola de ingesta asíncrona (conversión y subida a S3)
+-- Fecha: 2025-12-14
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: ingestion_jobs
+-- Propósito: Trabajo pendiente de cada archivo recibido en POST /resume. El
+--            handler solo guarda el archivo en el spool y encola el trabajo;
+--            el pool de workers lo convierte y lo sube a S3 con reintentos
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ingestion_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    request_id UUID NOT NULL UNIQUE REFERENCES resume_requests(request_id) ON DELETE CASCADE,
+
+    -- Ruta del archivo original en el directorio de spool
+    spool_path TEXT NOT NULL,
+
+    -- queued → running → succeeded | queued (reintento) | dead (dead-letter)
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'succeeded', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Un trabajo running con locked_until vencido quedó huérfano (el worker se cayó)
+    locked_until TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Búsqueda de trabajos listos para ejecutar y de trabajos huérfanos
+CREATE INDEX idx_ingestion_jobs_queued ON ingestion_jobs(next_attempt_at) WHERE status = 'queued';
+CREATE INDEX idx_ingestion_jobs_running ON ingestion_jobs(locked_until) WHERE status = 'running';
+
+-- Trigger para actualizar updated_at automáticamente
+CREATE TRIGGER update_ingestion_jobs_updated_at
+    BEFORE UPDATE ON ingestion_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+-- ============================================================================
+-- MIGRATION 010: Add Detected MIME Type
+-- Descripción: Tipo de archivo detectado por contenido (magic bytes)
+-- Fecha: 2025-12-15
+-- ============================================================================
+
+-- Tipo MIME detectado al recibir el archivo (ej: application/pdf,
+-- text/plain; charset=utf-16le). NULL en solicitudes anteriores a la detección
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS detected_mime_type VARCHAR(100);
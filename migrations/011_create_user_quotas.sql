@@ -0,0 +1,31 @@
+-- ============================================================================
+-- MIGRATION 011: Create User Quotas
+-- Descripción: Cuotas por usuario (solicitudes por día y almacenamiento)
+-- Fecha: 2025-12-16
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: user_quotas
+-- Propósito: Límites personalizados por usuario. Una columna NULL usa el valor
+--            por defecto de la configuración. La fila también sirve de lock para
+--            que dos subidas simultáneas del mismo usuario no superen la cuota
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id VARCHAR(255) PRIMARY KEY,
+
+    -- Límites (0 = sin límite, NULL = valor por defecto)
+    max_requests_per_day INT CHECK (max_requests_per_day >= 0),
+    max_storage_bytes BIGINT CHECK (max_storage_bytes >= 0),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Conteo de solicitudes del día por usuario
+CREATE INDEX IF NOT EXISTS idx_resume_requests_user_created_at ON resume_requests(user_id, created_at);
+
+-- Trigger para actualizar updated_at automáticamente
+CREATE TRIGGER update_user_quotas_updated_at
+    BEFORE UPDATE ON user_quotas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
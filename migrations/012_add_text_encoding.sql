@@ -0,0 +1,11 @@
+-- ============================================================================
+-- MIGRATION 012: Add Text Encoding
+-- Descripción: Codificación detectada en los archivos de texto plano
+-- Fecha: 2025-12-17
+-- ============================================================================
+
+-- Codificación del texto detectada al recibir el archivo (utf-8, utf-16le,
+-- utf-16be, windows-1252 o iso-8859-1), para diagnosticar caracteres mal
+-- convertidos. NULL en archivos que no son texto y en solicitudes anteriores
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS text_encoding VARCHAR(32);
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- MIGRATION 013: Add PDF Metadata
+-- Descripción: Páginas y generador de los PDF recibidos, para analítica
+-- Fecha: 2025-12-18
+-- ============================================================================
+
+-- Cantidad de páginas y programa que generó el PDF (/Producer de sus metadatos),
+-- obtenidos al inspeccionar el archivo antes de aceptarlo. NULL en archivos que
+-- no son PDF y en solicitudes anteriores
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS page_count INTEGER,
+ADD COLUMN IF NOT EXISTS pdf_producer VARCHAR(255);
@@ -0,0 +1,21 @@
+-- ============================================================================
+-- MIGRATION 014: Add Content Deduplication
+-- Descripción: Hash del PDF normalizado para reutilizar resultados de subidas repetidas
+-- Fecha: 2025-12-19
+-- ============================================================================
+
+-- SHA-256 (hex) del PDF que se envía a procesar (el recibido o el convertido), calculado
+-- por la cola de ingesta. NULL mientras no se ingiere y en solicitudes anteriores
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS content_sha256 CHAR(64),
+
+-- El usuario pidió procesar el archivo aunque ya tenga un resultado igual (campo 'force')
+ADD COLUMN IF NOT EXISTS force_reprocess BOOLEAN NOT NULL DEFAULT FALSE,
+
+-- Solicitud completada cuya versión activa se copió en lugar de reprocesar
+ADD COLUMN IF NOT EXISTS cloned_from_request_id UUID REFERENCES resume_requests(request_id) ON DELETE SET NULL;
+
+-- Búsqueda de un resultado previo del mismo usuario con el mismo contenido
+CREATE INDEX IF NOT EXISTS idx_resume_requests_content_sha256
+    ON resume_requests(user_id, content_sha256)
+    WHERE status = 'completed';
@@ -0,0 +1,49 @@
+-- ============================================================================
+-- MIGRATION 015: Create Resume Batches
+-- Descripción: Subida de varios CVs en una sola solicitud (varios archivos o un ZIP)
+-- Fecha: 2025-12-20
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: resume_batches
+-- Propósito: Lote creado por POST /resume/batch. Cada archivo aceptado es una
+--            solicitud de resume_requests con el batch_id del lote; el progreso
+--            se calcula con el estado de esas solicitudes
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS resume_batches (
+    batch_id UUID PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+
+    -- files: varias partes 'file' del formulario; zip: un archivo ZIP
+    source VARCHAR(10) NOT NULL CHECK (source IN ('files', 'zip')),
+
+    -- Archivos recibidos (aceptados + rechazados)
+    total_files INT NOT NULL,
+
+    -- Archivos rechazados sin crear solicitud: [{"filename": "...", "message": "..."}]
+    rejected JSONB NOT NULL DEFAULT '[]',
+
+    language VARCHAR(10) NOT NULL,
+    instructions TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_resume_batches_user_id ON resume_batches(user_id, created_at DESC);
+
+-- Lote de la solicitud (NULL en las subidas individuales)
+ALTER TABLE resume_requests
+ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES resume_batches(batch_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_resume_requests_batch_id
+    ON resume_requests(batch_id)
+    WHERE batch_id IS NOT NULL;
+
+-- Copia del lote en la cola de ingesta: acota los trabajos running de un mismo lote
+-- sin unir con resume_requests al reservar
+ALTER TABLE ingestion_jobs
+ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES resume_batches(batch_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_jobs_batch_running
+    ON ingestion_jobs(batch_id)
+    WHERE status = 'running' AND batch_id IS NOT NULL;
@@ -0,0 +1,14 @@
+-- ============================================================================
+-- MIGRATION 016: Scrub Webhook Delivery Errors
+-- Descripción: Quita de last_error el cuerpo de las respuestas y el detalle de red que
+--              se guardaban antes; ahora solo se guarda el status o un motivo genérico
+-- Fecha: 2025-12-21
+-- ============================================================================
+
+UPDATE webhook_deliveries
+SET last_error = split_part(last_error, ':', 1)
+WHERE last_error LIKE 'el destino respondió status %:%';
+
+UPDATE webhook_deliveries
+SET last_error = 'error de conexión con el destino'
+WHERE last_error LIKE 'error al enviar webhook:%';
@@ -0,0 +1,26 @@
+-- ============================================================================
+-- MIGRATION 017: Create Ingestion Spool Chunks
+-- Descripción: Guarda en la base de datos los archivos recibidos hasta que la cola de
+--              ingesta los procesa, así cualquier instancia puede ejecutar el trabajo
+-- Fecha: 2025-12-22
+-- ============================================================================
+
+-- ----------------------------------------------------------------------------
+-- TABLA: ingestion_spool_chunks
+-- Propósito: Contenido de los archivos de cada trabajo de ingesta, en partes de
+--            tamaño acotado para escribirlo y leerlo sin cargarlo completo en
+--            memoria. El worker que reserva el trabajo lo copia a su directorio
+--            de spool local; las partes se eliminan cuando el trabajo termina.
+--            ingestion_jobs.spool_path pasa a ser la ruta relativa (ver file_name)
+-- ----------------------------------------------------------------------------
+CREATE TABLE IF NOT EXISTS ingestion_spool_chunks (
+    job_id BIGINT NOT NULL REFERENCES ingestion_jobs(id) ON DELETE CASCADE,
+
+    -- Ruta relativa del archivo: <request_id><ext>, o <request_id>/<página><ext>
+    -- para las subidas de varias imágenes
+    file_name TEXT NOT NULL,
+    chunk_index INT NOT NULL,
+    data BYTEA NOT NULL,
+
+    PRIMARY KEY (job_id, file_name, chunk_index)
+);
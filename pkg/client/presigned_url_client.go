@@ -2,32 +2,33 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"resume-backend-service/internal/dto"
-	"time"
+	"resume-backend-service/pkg/httpx"
 )
 
 // PresignedURLClient maneja las llamadas al servicio de presigned URLs
 type PresignedURLClient struct {
 	baseURL    string
-	httpClient *http.Client
+	httpClient *httpx.Client
 }
 
-// NewPresignedURLClient crea una nueva instancia del cliente
-func NewPresignedURLClient(baseURL string) *PresignedURLClient {
+// NewPresignedURLClient crea una nueva instancia del cliente. Las llamadas usan
+// httpClient: timeout por intento, reintentos ante 5xx y errores de red, y el circuit
+// breaker que falla rápido mientras el servicio esté caído.
+func NewPresignedURLClient(baseURL string, httpClient *httpx.Client) *PresignedURLClient {
 	return &PresignedURLClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    baseURL,
+		httpClient: httpClient,
 	}
 }
 
 // GetUploadURL obtiene una URL firmada para subir un archivo a S3
-func (c *PresignedURLClient) GetUploadURL(filename, contentType, requestID, language, instructions string) (*dto.PresignedURLResponse, error) {
+func (c *PresignedURLClient) GetUploadURL(ctx context.Context, filename, contentType, requestID, language, instructions string) (*dto.PresignedURLResponse, error) {
 	// Construir el request
 	requestBody := dto.PresignedURLRequest{
 		Filename:    filename,
@@ -46,7 +47,8 @@ func (c *PresignedURLClient) GetUploadURL(filename, contentType, requestID, lang
 	}
 
 	// Crear la petición HTTP
-	req, err := http.NewRequest("POST", c.baseURL, bytes.NewBuffer(jsonData))
+	// bytes.NewReader permite releer el body si hay que reintentar
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("error al crear request HTTP: %w", err)
 	}
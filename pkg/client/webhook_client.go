@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"resume-backend-service/pkg/netguard"
+	"resume-backend-service/pkg/utils"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers que acompañan cada entrega de webhook
+const (
+	WebhookEventIDHeader   = "X-Webhook-Id"
+	WebhookEventTypeHeader = "X-Webhook-Event"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookResponseBytes limita cuánto del cuerpo de la respuesta se lee (y se descarta)
+// para poder reutilizar la conexión
+const maxWebhookResponseBytes = 512
+
+// WebhookError es el fallo de una entrega. Error() retorna solo un motivo genérico (se
+// guarda en la entrega y se muestra al usuario); el detalle queda en Cause para los logs,
+// para no exponer el cuerpo de la respuesta ni datos de la red del destino.
+type WebhookError struct {
+	Reason string
+	Cause  error
+}
+
+func (e *WebhookError) Error() string {
+	return e.Reason
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Cause
+}
+
+// Detail retorna el motivo junto con el error original, solo para logs
+func (e *WebhookError) Detail() string {
+	if e.Cause == nil {
+		return e.Reason
+	}
+	return e.Reason + ": " + e.Cause.Error()
+}
+
+// WebhookClient envía los eventos firmados a las URLs de los usuarios
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+// NewWebhookClient crea el cliente con el timeout indicado por entrega.
+// No sigue redirecciones: la URL registrada es la única que recibe el payload firmado.
+// Solo conecta a direcciones públicas: la IP se revisa al conectar, después de resolver
+// el DNS, y no se usa el proxy del entorno.
+func NewWebhookClient(timeout time.Duration) *WebhookClient {
+	return newWebhookClient(timeout, netguard.DialControl)
+}
+
+func newWebhookClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *WebhookClient {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}
+
+	return &WebhookClient{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:           dialer.DialContext,
+				ForceAttemptHTTP2:     true,
+				MaxIdleConns:          100,
+				IdleConnTimeout:       90 * time.Second,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ExpectContinueTimeout: 1 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send hace POST del payload a url firmándolo con secret (mismo esquema que el callback
+// de Lambda: HMAC-SHA256 de "<timestamp>.<body>"). Retorna el status HTTP recibido
+// (0 si no hubo respuesta) y un error si la entrega no fue 2xx.
+func (c *WebhookClient) Send(url, secret, eventID, eventType string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("error al crear request de webhook: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "resume-backend-service-webhooks/1.0")
+	req.Header.Set(WebhookEventIDHeader, eventID)
+	req.Header.Set(WebhookEventTypeHeader, eventType)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, utils.ComputeHMACSignature(secret, timestamp, payload))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, &WebhookError{Reason: sendFailureReason(err), Cause: err}
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxWebhookResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &WebhookError{Reason: fmt.Sprintf("el destino respondió status %d", resp.StatusCode)}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// sendFailureReason resume un error de red en un motivo que no revela la red del destino
+func sendFailureReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, netguard.ErrDisallowedAddress):
+		return "destino no permitido: la URL resuelve a una dirección interna"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout al enviar webhook"
+	default:
+		return "error de conexión con el destino"
+	}
+}
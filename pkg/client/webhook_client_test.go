@@ -0,0 +1,69 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"resume-backend-service/pkg/netguard"
+	"resume-backend-service/pkg/utils"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendRejectsInternalAddressesAtDialTime(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// httptest escucha en 127.0.0.1: el cliente por defecto no debe conectarse
+	status, err := NewWebhookClient(time.Second).Send(server.URL, "whsec_test", "evt-1", "resume.completed", []byte(`{}`))
+	if status != 0 || !errors.Is(err, netguard.ErrDisallowedAddress) {
+		t.Fatalf("status = %d, err = %v; se esperaba ErrDisallowedAddress", status, err)
+	}
+	if called {
+		t.Error("el webhook no debía llegar al servidor interno")
+	}
+	if strings.Contains(err.Error(), "127.0.0.1") {
+		t.Errorf("el error guardado no debe revelar la dirección: %q", err.Error())
+	}
+}
+
+func TestSendDoesNotExposeResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(w, "panic: token=sk_live_123 en /srv/app/handler.go")
+	}))
+	defer server.Close()
+
+	status, err := newWebhookClient(time.Second, nil).Send(server.URL, "whsec_test", "evt-1", "resume.completed", []byte(`{}`))
+	if status != http.StatusInternalServerError {
+		t.Errorf("status = %d, se esperaba 500", status)
+	}
+	if err == nil || err.Error() != "el destino respondió status 500" {
+		t.Errorf("error = %v, se esperaba solo el status", err)
+	}
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	payload := []byte(`{"event":"resume.completed"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if !utils.VerifyHMACSignature("whsec_test", timestamp, body, r.Header.Get(WebhookSignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	status, err := newWebhookClient(time.Second, nil).Send(server.URL, "whsec_test", "evt-1", "resume.completed", payload)
+	if err != nil || status != http.StatusNoContent {
+		t.Errorf("status = %d, err = %v", status, err)
+	}
+}
@@ -0,0 +1,289 @@
+package converter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// Valores especiales de la FAT del Compound File Binary (OLE2)
+const (
+	cfbMaxRegSect = 0xFFFFFFFA
+	cfbEndOfChain = 0xFFFFFFFE
+)
+
+// Tipos de entrada del directorio
+const (
+	cfbTypeStream = 2
+	cfbTypeRoot   = 5
+)
+
+const (
+	cfbHeaderSize   = 512
+	cfbDirEntrySize = 128
+	cfbHeaderDIFAT  = 109
+)
+
+var errInvalidCFB = errors.New("contenedor OLE2 inválido")
+
+// cfbFile lee los streams de un archivo Compound File Binary (el contenedor de los
+// documentos de Office 97-2003). La FAT, la mini FAT y el directorio se cargan al
+// abrirlo; los streams se leen completos, limitados a maxPartSize.
+type cfbFile struct {
+	r              io.ReaderAt
+	size           int64
+	sectorSize     int64
+	miniSectorSize int64
+	miniCutoff     int64
+	fat            []uint32
+	miniFAT        []uint32
+	entries        []cfbEntry
+	miniStream     []byte
+}
+
+// cfbEntry es una entrada del directorio (storage o stream)
+type cfbEntry struct {
+	name        string
+	objectType  byte
+	startSector uint32
+	size        int64
+}
+
+// openCFB lee el encabezado, la FAT y el directorio del contenedor
+func openCFB(r io.ReaderAt, size int64) (*cfbFile, error) {
+	header := make([]byte, cfbHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errInvalidCFB
+	}
+	if string(header[:8]) != string(ole2Magic) {
+		return nil, errInvalidCFB
+	}
+
+	sectorShift := binary.LittleEndian.Uint16(header[0x1E:])
+	miniShift := binary.LittleEndian.Uint16(header[0x20:])
+	if (sectorShift != 9 && sectorShift != 12) || miniShift != 6 {
+		return nil, errInvalidCFB
+	}
+	f := &cfbFile{
+		r:              r,
+		size:           size,
+		sectorSize:     1 << sectorShift,
+		miniSectorSize: 1 << miniShift,
+		miniCutoff:     int64(binary.LittleEndian.Uint32(header[0x38:])),
+	}
+
+	if err := f.loadFAT(header); err != nil {
+		return nil, err
+	}
+
+	dir, err := f.readChain(binary.LittleEndian.Uint32(header[0x30:]), -1)
+	if err != nil {
+		return nil, err
+	}
+	for offset := 0; offset+cfbDirEntrySize <= len(dir); offset += cfbDirEntrySize {
+		f.entries = append(f.entries, parseCFBEntry(dir[offset:offset+cfbDirEntrySize], f.sectorSize))
+	}
+	if len(f.entries) == 0 || f.entries[0].objectType != cfbTypeRoot {
+		return nil, errInvalidCFB
+	}
+
+	miniFAT, err := f.readChain(binary.LittleEndian.Uint32(header[0x3C:]), -1)
+	if err != nil {
+		return nil, err
+	}
+	f.miniFAT = bytesToUint32s(miniFAT)
+	return f, nil
+}
+
+// loadFAT arma la FAT con los sectores listados en el DIFAT: los primeros 109 en el
+// encabezado y el resto en una cadena de sectores DIFAT
+func (f *cfbFile) loadFAT(header []byte) error {
+	numFATSectors := int64(binary.LittleEndian.Uint32(header[0x2C:]))
+	if numFATSectors*f.sectorSize > f.size {
+		return errInvalidCFB
+	}
+
+	sectors := make([]uint32, 0, numFATSectors)
+	for i := 0; i < cfbHeaderDIFAT && int64(len(sectors)) < numFATSectors; i++ {
+		sectors = append(sectors, binary.LittleEndian.Uint32(header[0x4C+4*i:]))
+	}
+	next := binary.LittleEndian.Uint32(header[0x44:])
+	perSector := int(f.sectorSize/4) - 1
+	for visited := int64(0); int64(len(sectors)) < numFATSectors && next <= cfbMaxRegSect; visited++ {
+		if visited > f.size/f.sectorSize {
+			return errInvalidCFB
+		}
+		buf, err := f.readSector(next)
+		if err != nil {
+			return err
+		}
+		values := bytesToUint32s(buf)
+		for _, sector := range values[:perSector] {
+			if int64(len(sectors)) < numFATSectors {
+				sectors = append(sectors, sector)
+			}
+		}
+		next = values[perSector]
+	}
+	if int64(len(sectors)) < numFATSectors {
+		return errInvalidCFB
+	}
+
+	f.fat = make([]uint32, 0, numFATSectors*f.sectorSize/4)
+	for _, sector := range sectors {
+		buf, err := f.readSector(sector)
+		if err != nil {
+			return err
+		}
+		f.fat = append(f.fat, bytesToUint32s(buf)...)
+	}
+	return nil
+}
+
+// hasStream indica si el contenedor tiene un stream con ese nombre
+func (f *cfbFile) hasStream(name string) bool {
+	for _, entry := range f.entries {
+		if entry.objectType == cfbTypeStream && strings.EqualFold(entry.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// stream retorna el contenido del stream con ese nombre (sin distinguir
+// mayúsculas, como en el formato), o nil si no existe
+func (f *cfbFile) stream(name string) ([]byte, error) {
+	for _, entry := range f.entries {
+		if entry.objectType != cfbTypeStream || !strings.EqualFold(entry.name, name) {
+			continue
+		}
+		if entry.size > maxPartSize {
+			return nil, errPartTooLarge
+		}
+		if entry.size < f.miniCutoff {
+			return f.readMiniStream(entry)
+		}
+		return f.readChain(entry.startSector, entry.size)
+	}
+	return nil, nil
+}
+
+// readMiniStream lee un stream pequeño, guardado en sectores de 64 bytes dentro del
+// mini stream (el stream de la entrada raíz)
+func (f *cfbFile) readMiniStream(entry cfbEntry) ([]byte, error) {
+	if f.miniStream == nil {
+		root := f.entries[0]
+		if root.size > maxPartSize {
+			return nil, errPartTooLarge
+		}
+		miniStream, err := f.readChain(root.startSector, root.size)
+		if err != nil {
+			return nil, err
+		}
+		f.miniStream = miniStream
+	}
+
+	data := make([]byte, 0, entry.size)
+	sector := entry.startSector
+	for count := 0; int64(len(data)) < entry.size; count++ {
+		// Una cadena más larga que la mini FAT indica un ciclo
+		if sector > cfbMaxRegSect || int(sector) >= len(f.miniFAT) || count > len(f.miniFAT) {
+			return nil, errInvalidCFB
+		}
+		start := int64(sector) * f.miniSectorSize
+		end := start + f.miniSectorSize
+		if end > int64(len(f.miniStream)) {
+			return nil, errInvalidCFB
+		}
+		data = append(data, f.miniStream[start:end]...)
+		sector = f.miniFAT[sector]
+	}
+	return data[:entry.size], nil
+}
+
+// readChain lee una cadena de sectores de la FAT. size es el tamaño del stream, o -1
+// para leer la cadena completa (directorio, mini FAT).
+func (f *cfbFile) readChain(sector uint32, size int64) ([]byte, error) {
+	var data []byte
+	if size > 0 {
+		data = make([]byte, 0, size)
+	}
+	maxSectors := f.size / f.sectorSize
+	for count := int64(0); sector != cfbEndOfChain && (size < 0 || int64(len(data)) < size); count++ {
+		// Una cadena más larga que el archivo indica un ciclo
+		if sector > cfbMaxRegSect || count > maxSectors || int64(len(data)) > maxPartSize {
+			return nil, errInvalidCFB
+		}
+		buf, err := f.readSector(sector)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, buf...)
+		if int(sector) >= len(f.fat) {
+			return nil, errInvalidCFB
+		}
+		sector = f.fat[sector]
+	}
+	if size >= 0 {
+		if int64(len(data)) < size {
+			return nil, errInvalidCFB
+		}
+		data = data[:size]
+	}
+	return data, nil
+}
+
+func (f *cfbFile) readSector(sector uint32) ([]byte, error) {
+	offset := (int64(sector) + 1) * f.sectorSize
+	if offset+f.sectorSize > f.size {
+		return nil, fmt.Errorf("%w: sector %d fuera del archivo", errInvalidCFB, sector)
+	}
+	buf := make([]byte, f.sectorSize)
+	if _, err := f.r.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func parseCFBEntry(buf []byte, sectorSize int64) cfbEntry {
+	nameLen := int(binary.LittleEndian.Uint16(buf[0x40:]))
+	if nameLen > 64 {
+		nameLen = 64
+	}
+	units := bytesToUint16s(buf[:nameLen])
+	// El nombre termina en un carácter nulo
+	for len(units) > 0 && units[len(units)-1] == 0 {
+		units = units[:len(units)-1]
+	}
+
+	size := int64(binary.LittleEndian.Uint64(buf[0x78:]))
+	if sectorSize == 512 {
+		// En la versión 3 solo los 32 bits bajos son válidos
+		size &= 0xFFFFFFFF
+	}
+	return cfbEntry{
+		name:        string(utf16.Decode(units)),
+		objectType:  buf[0x42],
+		startSector: binary.LittleEndian.Uint32(buf[0x74:]),
+		size:        size,
+	}
+}
+
+func bytesToUint32s(buf []byte) []uint32 {
+	values := make([]uint32, len(buf)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(buf[4*i:])
+	}
+	return values
+}
+
+func bytesToUint16s(buf []byte) []uint16 {
+	values := make([]uint16, len(buf)/2)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(buf[2*i:])
+	}
+	return values
+}
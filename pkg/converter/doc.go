@@ -0,0 +1,301 @@
+package converter
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Constantes del File Information Block (FIB) de Word 97-2003
+const (
+	docIdent       = 0xA5EC
+	docMinNFib     = 0x00C1 // Word 97; las versiones anteriores usan otro formato
+	docFEncrypted  = 0x0100
+	docFWhichTable = 0x0200
+	docFibBaseSize = 32
+	docClxIndex    = 33 // Posición de fcClx/lcbClx en FibRgFcLcb97
+	docCcpTextIdx  = 3  // Posición de ccpText en FibRgLw97
+)
+
+// Caracteres especiales del texto de Word
+const (
+	docFieldBegin     = 0x13
+	docFieldSeparator = 0x14
+	docFieldEnd       = 0x15
+	docParagraphEnd   = 0x0D
+	docCellEnd        = 0x07
+	docPageBreak      = 0x0C
+	docLineBreak      = 0x0B
+	docNoBreakHyphen  = 0x1E
+	docOptionalHyphen = 0x1F
+)
+
+// docPiece es una entrada de la tabla de piezas: un rango de posiciones de texto
+// (CP) guardado a partir de fc, en cp1252 (comprimido) o UTF-16LE
+type docPiece struct {
+	cpStart, cpEnd uint32
+	fc             uint32
+	compressed     bool
+}
+
+// ParseDoc lee un documento de Word 97-2003 (.doc) y construye su modelo. Se lee el
+// contenedor OLE2 y la tabla de piezas del stream WordDocument para extraer el texto
+// del cuerpo, párrafo por párrafo; los hipervínculos se conservan. El formato de
+// párrafo y de caracteres (títulos, listas, negrita) y el encabezado y el pie no se
+// interpretan, y las celdas de las tablas quedan como párrafos.
+func ParseDoc(r io.ReaderAt, size int64) (*Document, error) {
+	cfb, err := openCFB(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	wordDoc, err := cfb.stream(docMainStream)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	if wordDoc == nil {
+		return nil, errors.New("error al leer archivo DOC: no contiene el stream WordDocument")
+	}
+
+	fib, err := parseDocFIB(wordDoc)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	table, err := cfb.stream(fib.tableStream)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	if uint64(fib.fcClx)+uint64(fib.lcbClx) > uint64(len(table)) {
+		return nil, errors.New("error al leer archivo DOC: tabla de piezas fuera del stream")
+	}
+
+	pieces, err := parseDocPieces(table[fib.fcClx : fib.fcClx+fib.lcbClx])
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	text, err := docText(wordDoc, pieces, fib.ccpText)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOC: %w", err)
+	}
+	return &Document{Body: docParagraphs(text)}, nil
+}
+
+// docFIB son los campos del FIB que se usan
+type docFIB struct {
+	tableStream   string
+	ccpText       uint32
+	fcClx, lcbClx uint32
+}
+
+// parseDocFIB lee el FIB al inicio del stream WordDocument. Sus secciones tienen
+// largo variable: cada una indica su cantidad de elementos.
+func parseDocFIB(wordDoc []byte) (docFIB, error) {
+	var fib docFIB
+	if len(wordDoc) < docFibBaseSize+2 || binary.LittleEndian.Uint16(wordDoc) != docIdent {
+		return fib, errors.New("no es un documento de Word")
+	}
+	if binary.LittleEndian.Uint16(wordDoc[2:]) < docMinNFib {
+		return fib, errors.New("versión de Word anterior a 97 no soportada")
+	}
+	flags := binary.LittleEndian.Uint16(wordDoc[0x0A:])
+	if flags&docFEncrypted != 0 {
+		return fib, errors.New("el documento está protegido con contraseña")
+	}
+	fib.tableStream = "0Table"
+	if flags&docFWhichTable != 0 {
+		fib.tableStream = "1Table"
+	}
+
+	// fibRgW (csw valores de 16 bits), fibRgLw (cslw de 32 bits) y fibRgFcLcb
+	// (cbRgFcLcb pares de 32 bits)
+	offset := docFibBaseSize
+	csw := int(binary.LittleEndian.Uint16(wordDoc[offset:]))
+	offset += 2 + 2*csw
+	if offset+2 > len(wordDoc) {
+		return fib, errors.New("FIB truncado")
+	}
+	cslw := int(binary.LittleEndian.Uint16(wordDoc[offset:]))
+	offset += 2
+	if cslw <= docCcpTextIdx || offset+4*cslw+2 > len(wordDoc) {
+		return fib, errors.New("FIB truncado")
+	}
+	fib.ccpText = binary.LittleEndian.Uint32(wordDoc[offset+4*docCcpTextIdx:])
+	offset += 4 * cslw
+
+	cbRgFcLcb := int(binary.LittleEndian.Uint16(wordDoc[offset:]))
+	offset += 2
+	if cbRgFcLcb <= docClxIndex || offset+8*cbRgFcLcb > len(wordDoc) {
+		return fib, errors.New("FIB truncado")
+	}
+	fib.fcClx = binary.LittleEndian.Uint32(wordDoc[offset+8*docClxIndex:])
+	fib.lcbClx = binary.LittleEndian.Uint32(wordDoc[offset+8*docClxIndex+4:])
+	return fib, nil
+}
+
+// parseDocPieces lee la tabla de piezas (Pcdt) del Clx, después de las
+// propiedades (Prc) que pueda tener al inicio
+func parseDocPieces(clx []byte) ([]docPiece, error) {
+	for i := 0; i < len(clx); {
+		switch clx[i] {
+		case 0x01:
+			if i+3 > len(clx) {
+				return nil, errors.New("Clx truncado")
+			}
+			cb := int(int16(binary.LittleEndian.Uint16(clx[i+1:])))
+			if cb < 0 {
+				return nil, errors.New("Clx inválido")
+			}
+			i += 3 + cb
+		case 0x02:
+			if i+5 > len(clx) {
+				return nil, errors.New("Clx truncado")
+			}
+			lcb := int(binary.LittleEndian.Uint32(clx[i+1:]))
+			plc := clx[i+5:]
+			if lcb < 4 || lcb > len(plc) || (lcb-4)%12 != 0 {
+				return nil, errors.New("tabla de piezas inválida")
+			}
+			// n+1 posiciones de texto seguidas de n descriptores de 8 bytes
+			n := (lcb - 4) / 12
+			pieces := make([]docPiece, n)
+			for k := range pieces {
+				fc := binary.LittleEndian.Uint32(plc[4*(n+1)+8*k+2:])
+				pieces[k] = docPiece{
+					cpStart:    binary.LittleEndian.Uint32(plc[4*k:]),
+					cpEnd:      binary.LittleEndian.Uint32(plc[4*(k+1):]),
+					fc:         fc &^ (1 << 30),
+					compressed: fc&(1<<30) != 0,
+				}
+			}
+			return pieces, nil
+		default:
+			return nil, errors.New("Clx inválido")
+		}
+	}
+	return nil, errors.New("el documento no tiene tabla de piezas")
+}
+
+// docText arma el texto del cuerpo (posiciones 0 a ccpText) desde las piezas. Las
+// piezas comprimidas usan cp1252 con un byte por carácter, las demás UTF-16LE.
+func docText(wordDoc []byte, pieces []docPiece, ccpText uint32) ([]rune, error) {
+	units := make([]uint16, 0, ccpText)
+	for _, piece := range pieces {
+		if piece.cpStart >= ccpText {
+			break
+		}
+		if piece.cpEnd < piece.cpStart {
+			return nil, errors.New("pieza de texto inválida")
+		}
+		count := uint64(min(piece.cpEnd, ccpText) - piece.cpStart)
+
+		if piece.compressed {
+			start := uint64(piece.fc / 2)
+			if start+count > uint64(len(wordDoc)) {
+				return nil, errors.New("pieza de texto fuera del documento")
+			}
+			for _, b := range wordDoc[start : start+count] {
+				units = append(units, uint16(charmap.Windows1252.DecodeByte(b)))
+			}
+			continue
+		}
+
+		start := uint64(piece.fc)
+		if start+2*count > uint64(len(wordDoc)) {
+			return nil, errors.New("pieza de texto fuera del documento")
+		}
+		units = append(units, bytesToUint16s(wordDoc[start:start+2*count])...)
+	}
+	return utf16.Decode(units), nil
+}
+
+// docField es un campo de Word: instrucción (ej: HYPERLINK "url") y resultado visible
+type docField struct {
+	inResult bool
+	instr    strings.Builder
+	link     string
+}
+
+// docParagraphs divide el texto en párrafos. De los campos se escribe solo el
+// resultado; el de un HYPERLINK queda como enlace.
+func docParagraphs(text []rune) []Block {
+	var (
+		blocks []Block
+		para   = &Paragraph{}
+		run    strings.Builder
+		fields []*docField
+	)
+	link := func() string {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if fields[i].link != "" {
+				return fields[i].link
+			}
+		}
+		return ""
+	}
+	flushRun := func() {
+		if run.Len() > 0 {
+			para.Runs = appendRun(para.Runs, Run{Text: run.String(), Link: link()})
+			run.Reset()
+		}
+	}
+	endParagraph := func(keepEmpty bool) {
+		flushRun()
+		if keepEmpty || !para.IsEmpty() {
+			blocks = append(blocks, para)
+		}
+		para = &Paragraph{}
+	}
+
+	for _, r := range text {
+		if n := len(fields); n > 0 && !fields[n-1].inResult && r != docFieldBegin &&
+			r != docFieldSeparator && r != docFieldEnd {
+			fields[n-1].instr.WriteRune(r)
+			continue
+		}
+
+		switch r {
+		case docFieldBegin:
+			flushRun()
+			fields = append(fields, &docField{})
+		case docFieldSeparator:
+			if n := len(fields); n > 0 {
+				field := fields[n-1]
+				field.inResult = true
+				if m := hyperlinkRe.FindStringSubmatch(field.instr.String()); m != nil {
+					field.link = m[1]
+				}
+			}
+		case docFieldEnd:
+			flushRun()
+			if n := len(fields); n > 0 {
+				fields = fields[:n-1]
+			}
+		case docParagraphEnd, docPageBreak:
+			endParagraph(true)
+		case docCellEnd:
+			// Fin de celda o de fila: las marcas de fin de fila dejan párrafos vacíos
+			endParagraph(false)
+		case docLineBreak:
+			run.WriteRune('\n')
+		case '\t':
+			run.WriteRune('\t')
+		case docNoBreakHyphen:
+			run.WriteRune('-')
+		case docOptionalHyphen:
+		default:
+			// Las marcas de imágenes, notas y objetos (0x01-0x08) no son texto
+			if r >= 0x20 {
+				run.WriteRune(r)
+			}
+		}
+	}
+	flushRun()
+	if !para.IsEmpty() {
+		blocks = append(blocks, para)
+	}
+	return blocks
+}
@@ -0,0 +1,310 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// cfbTestStream es un stream para buildCFB
+type cfbTestStream struct {
+	name string
+	data []byte
+}
+
+// buildCFB arma un contenedor OLE2 versión 3 (sectores de 512 bytes). Los streams
+// menores a 4096 bytes van en el mini stream, como los escribe Office.
+func buildCFB(tb testing.TB, streams ...cfbTestStream) []byte {
+	tb.Helper()
+
+	const (
+		sectorSize = 512
+		miniSize   = 64
+		cutoff     = 4096
+		endOfChain = 0xFFFFFFFE
+		noStream   = 0xFFFFFFFF
+	)
+
+	var sectors [][]byte
+	var fat []uint32
+	alloc := func(data []byte) uint32 {
+		if len(data) == 0 {
+			return endOfChain
+		}
+		start := uint32(len(sectors))
+		for offset := 0; offset < len(data); offset += sectorSize {
+			sector := make([]byte, sectorSize)
+			copy(sector, data[offset:])
+			sectors = append(sectors, sector)
+			fat = append(fat, uint32(len(sectors)))
+		}
+		fat[len(fat)-1] = endOfChain
+		return start
+	}
+
+	type dirEntry struct {
+		name       string
+		objectType byte
+		start      uint32
+		size       int
+	}
+	entries := []dirEntry{{name: "Root Entry", objectType: cfbTypeRoot}}
+	var mini []byte
+	var miniFAT []uint32
+	for _, stream := range streams {
+		entry := dirEntry{name: stream.name, objectType: cfbTypeStream, size: len(stream.data)}
+		if len(stream.data) >= cutoff {
+			entry.start = alloc(stream.data)
+		} else {
+			entry.start = uint32(len(mini) / miniSize)
+			for offset := 0; offset < len(stream.data); offset += miniSize {
+				chunk := make([]byte, miniSize)
+				copy(chunk, stream.data[offset:])
+				mini = append(mini, chunk...)
+				miniFAT = append(miniFAT, uint32(len(mini)/miniSize))
+			}
+			miniFAT[len(miniFAT)-1] = endOfChain
+		}
+		entries = append(entries, entry)
+	}
+	entries[0].start = alloc(mini)
+	entries[0].size = len(mini)
+	miniFATStart := alloc(appendUint32s(nil, miniFAT))
+
+	var dir []byte
+	for i, entry := range entries {
+		buf := make([]byte, cfbDirEntrySize)
+		name := utf16.Encode([]rune(entry.name))
+		for j, unit := range name {
+			binary.LittleEndian.PutUint16(buf[2*j:], unit)
+		}
+		binary.LittleEndian.PutUint16(buf[0x40:], uint16(2*(len(name)+1)))
+		buf[0x42] = entry.objectType
+		buf[0x43] = 1 // Negro
+		right, child := uint32(noStream), uint32(noStream)
+		if i == 0 && len(entries) > 1 {
+			child = 1
+		} else if i > 0 && i+1 < len(entries) {
+			right = uint32(i + 1)
+		}
+		binary.LittleEndian.PutUint32(buf[0x44:], noStream)
+		binary.LittleEndian.PutUint32(buf[0x48:], right)
+		binary.LittleEndian.PutUint32(buf[0x4C:], child)
+		binary.LittleEndian.PutUint32(buf[0x74:], entry.start)
+		binary.LittleEndian.PutUint64(buf[0x78:], uint64(entry.size))
+		dir = append(dir, buf...)
+	}
+	dirStart := alloc(dir)
+
+	// Los sectores de la FAT van al final y se marcan a sí mismos como FATSECT
+	fatStart := len(sectors)
+	numFAT := 1
+	for (fatStart+numFAT)*4 > numFAT*sectorSize {
+		numFAT++
+	}
+	for i := 0; i < numFAT; i++ {
+		fat = append(fat, 0xFFFFFFFD)
+	}
+	for len(fat)%(sectorSize/4) != 0 {
+		fat = append(fat, 0xFFFFFFFF)
+	}
+	fatBytes := appendUint32s(nil, fat)
+
+	header := make([]byte, cfbHeaderSize)
+	copy(header, ole2Magic)
+	binary.LittleEndian.PutUint16(header[0x18:], 0x3E)
+	binary.LittleEndian.PutUint16(header[0x1A:], 3)
+	binary.LittleEndian.PutUint16(header[0x1C:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[0x1E:], 9)
+	binary.LittleEndian.PutUint16(header[0x20:], 6)
+	binary.LittleEndian.PutUint32(header[0x2C:], uint32(numFAT))
+	binary.LittleEndian.PutUint32(header[0x30:], dirStart)
+	binary.LittleEndian.PutUint32(header[0x38:], cutoff)
+	binary.LittleEndian.PutUint32(header[0x3C:], miniFATStart)
+	binary.LittleEndian.PutUint32(header[0x40:], uint32((len(miniFAT)*4+sectorSize-1)/sectorSize))
+	binary.LittleEndian.PutUint32(header[0x44:], endOfChain)
+	for i := 0; i < cfbHeaderDIFAT; i++ {
+		sector := uint32(0xFFFFFFFF)
+		if i < numFAT {
+			sector = uint32(fatStart + i)
+		}
+		binary.LittleEndian.PutUint32(header[0x4C+4*i:], sector)
+	}
+
+	out := bytes.NewBuffer(header)
+	for _, sector := range sectors {
+		out.Write(sector)
+	}
+	out.Write(fatBytes)
+	return out.Bytes()
+}
+
+func appendUint32s(buf []byte, values []uint32) []byte {
+	for _, value := range values {
+		buf = binary.LittleEndian.AppendUint32(buf, value)
+	}
+	return buf
+}
+
+// docTestPiece es un fragmento de texto del documento, comprimido (cp1252) o UTF-16
+type docTestPiece struct {
+	text       string
+	compressed bool
+}
+
+// buildDoc arma un .doc de Word 97 con el texto en las piezas dadas. flags se suma a
+// los del FIB (ej: docFEncrypted).
+func buildDoc(tb testing.TB, flags uint16, pieces ...docTestPiece) []byte {
+	tb.Helper()
+
+	const (
+		csw       = 14
+		cslw      = 22
+		cbRgFcLcb = 0x5D
+	)
+	fib := make([]byte, docFibBaseSize+2+2*csw+2+4*cslw+2+8*cbRgFcLcb)
+	binary.LittleEndian.PutUint16(fib[0:], docIdent)
+	binary.LittleEndian.PutUint16(fib[2:], docMinNFib)
+	binary.LittleEndian.PutUint16(fib[0x0A:], docFWhichTable|flags)
+	offset := docFibBaseSize
+	binary.LittleEndian.PutUint16(fib[offset:], csw)
+	offset += 2 + 2*csw
+	binary.LittleEndian.PutUint16(fib[offset:], cslw)
+	rgLw := offset + 2
+	offset += 2 + 4*cslw
+	binary.LittleEndian.PutUint16(fib[offset:], cbRgFcLcb)
+	rgFcLcb := offset + 2
+
+	wordDoc := fib
+	cps := []uint32{0}
+	var pcds []byte
+	for _, piece := range pieces {
+		fc := uint32(len(wordDoc))
+		var count int
+		if piece.compressed {
+			encoded, err := charmap.Windows1252.NewEncoder().String(piece.text)
+			if err != nil {
+				tb.Fatal(err)
+			}
+			wordDoc = append(wordDoc, encoded...)
+			count = len(encoded)
+			fc = fc*2 | 1<<30
+		} else {
+			units := utf16.Encode([]rune(piece.text))
+			for _, unit := range units {
+				wordDoc = binary.LittleEndian.AppendUint16(wordDoc, unit)
+			}
+			count = len(units)
+		}
+		cps = append(cps, cps[len(cps)-1]+uint32(count))
+		pcd := make([]byte, 8)
+		binary.LittleEndian.PutUint32(pcd[2:], fc)
+		pcds = append(pcds, pcd...)
+	}
+	binary.LittleEndian.PutUint32(wordDoc[rgLw+4*docCcpTextIdx:], cps[len(cps)-1])
+	// Office rellena el stream; con 4096 bytes o más va en sectores normales
+	for len(wordDoc) < 4096 {
+		wordDoc = append(wordDoc, 0)
+	}
+
+	// Clx: una propiedad (Prc) que se debe saltar y la tabla de piezas (Pcdt)
+	clx := []byte{0x01, 0x02, 0x00, 0xAA, 0xBB, 0x02}
+	plc := appendUint32s(nil, cps)
+	plc = append(plc, pcds...)
+	clx = binary.LittleEndian.AppendUint32(clx, uint32(len(plc)))
+	clx = append(clx, plc...)
+	table := append(make([]byte, 16), clx...)
+	binary.LittleEndian.PutUint32(wordDoc[rgFcLcb+8*docClxIndex:], 16)
+	binary.LittleEndian.PutUint32(wordDoc[rgFcLcb+8*docClxIndex+4:], uint32(len(clx)))
+
+	return buildCFB(tb,
+		cfbTestStream{name: "WordDocument", data: wordDoc},
+		cfbTestStream{name: "1Table", data: table},
+	)
+}
+
+// docSample es un CV con piezas comprimidas y Unicode, un hipervínculo, una tabla,
+// saltos de línea y guiones especiales
+var docSample = []docTestPiece{
+	{text: "José Pérez\rDesarrolladora\tBackend · Madrid\r", compressed: true},
+	{text: "Łódź — Київ 😀\r", compressed: false},
+	{text: "Contacto: \x13 HYPERLINK \"mailto:jose@example.com\" \\o \"correo\"\x14jose@example.com\x15 \x01\r", compressed: true},
+	{text: "Go\x07PostgreSQL\x07\x07Página\x0cFin\x0bsegunda línea co\x1eautora opcio\x1fnal", compressed: true},
+}
+
+func TestParseDocExtractsParagraphs(t *testing.T) {
+	content := buildDoc(t, 0, docSample...)
+	doc, err := ParseDoc(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("ParseDoc: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"=== header ===",
+		"=== body ===",
+		"José Pérez",
+		`Desarrolladora\tBackend · Madrid`,
+		"Łódź — Київ 😀",
+		"Contacto: [jose@example.com](mailto:jose@example.com) ",
+		"Go",
+		"PostgreSQL",
+		"Página",
+		`Fin\nsegunda línea co-autora opcional`,
+		"=== footer ===",
+		"",
+	}, "\n")
+	if got := dumpDocument(doc); got != want {
+		t.Errorf("modelo inesperado:\n--- obtenido ---\n%s\n--- esperado ---\n%s", got, want)
+	}
+}
+
+func TestConvertToPDFRendersDoc(t *testing.T) {
+	content := buildDoc(t, 0, docSample...)
+	if got := DetectMIMEType(bytes.NewReader(content), int64(len(content))); got != MIMEDoc {
+		t.Fatalf("DetectMIMEType = %q, se esperaba %q", got, MIMEDoc)
+	}
+
+	result, err := ConvertToPDF(context.Background(), writeInput(t, content), "cv.doc")
+	if err != nil {
+		t.Fatalf("ConvertToPDF: %v", err)
+	}
+	defer result.Close()
+	pdf, err := io.ReadAll(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Filename != "cv.pdf" || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("PDF inesperado: filename=%q inicio=%q", result.Filename, pdf[:min(len(pdf), 16)])
+	}
+}
+
+func TestParseDocRejectsInvalidDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"no es OLE2", []byte("no es un documento")},
+		{"OLE2 sin WordDocument", buildCFB(t, cfbTestStream{name: "Workbook", data: []byte("xls")})},
+		{"protegido con contraseña", buildDoc(t, docFEncrypted, docSample...)},
+		{"encabezado OLE2 truncado", buildDoc(t, 0, docSample...)[:1024]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDoc(bytes.NewReader(tt.content), int64(len(tt.content))); err == nil {
+				t.Error("se esperaba error")
+			}
+		})
+	}
+
+	// Otros archivos OLE2 no se reportan como .doc
+	content := tests[1].content
+	if got := DetectMIMEType(bytes.NewReader(content), int64(len(content))); got != MIMEOLE2 {
+		t.Errorf("DetectMIMEType = %q, se esperaba %q", got, MIMEOLE2)
+	}
+}
@@ -0,0 +1,102 @@
+package converter
+
+import "strings"
+
+// Document es el modelo intermedio de un documento de texto enriquecido. Los parsers
+// (DOCX, etc.) lo construyen y el escritor de PDF lo renderiza.
+type Document struct {
+	Header []Block
+	Body   []Block
+	Footer []Block
+}
+
+// Block es un elemento de nivel de bloque: *Paragraph o *Table
+type Block interface {
+	isBlock()
+}
+
+// Paragraph es un párrafo, título o elemento de lista
+type Paragraph struct {
+	// HeadingLevel es 1 para el título principal, 2 para subtítulos, etc.; 0 si es texto normal
+	HeadingLevel int
+	// List es distinto de nil si el párrafo es un elemento de lista
+	List *ListItem
+	Runs []Run
+}
+
+// ListItem describe la posición de un párrafo dentro de una lista
+type ListItem struct {
+	Level   int    // Nivel de anidamiento, desde 0
+	Ordered bool   // Lista numerada (true) o con viñetas (false)
+	Marker  string // Viñeta o número ya formateado (ej: "•", "2.", "b)")
+}
+
+// Run es un fragmento de texto con el mismo formato. El texto puede contener
+// tabulaciones y saltos de línea.
+type Run struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Link   string // URL de destino si el texto es un hipervínculo
+}
+
+// Table es una tabla; cada celda contiene sus propios bloques
+type Table struct {
+	Rows []TableRow
+}
+
+// TableRow es una fila de una tabla
+type TableRow struct {
+	Cells []TableCell
+}
+
+// TableCell es una celda de una tabla
+type TableCell struct {
+	Blocks []Block
+}
+
+func (*Paragraph) isBlock() {}
+func (*Table) isBlock()     {}
+
+// Text retorna el texto del párrafo sin formato
+func (p *Paragraph) Text() string {
+	var sb strings.Builder
+	for _, run := range p.Runs {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
+
+// IsEmpty indica si el párrafo no tiene texto visible
+func (p *Paragraph) IsEmpty() bool {
+	return strings.TrimSpace(p.Text()) == ""
+}
+
+// ColumnCount retorna la cantidad máxima de celdas por fila
+func (t *Table) ColumnCount() int {
+	count := 0
+	for _, row := range t.Rows {
+		if len(row.Cells) > count {
+			count = len(row.Cells)
+		}
+	}
+	return count
+}
+
+// PlainText retorna el texto de los bloques, un párrafo por línea
+func PlainText(blocks []Block) string {
+	var lines []string
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *Paragraph:
+			lines = append(lines, b.Text())
+		case *Table:
+			for _, row := range b.Rows {
+				for _, cell := range row.Cells {
+					lines = append(lines, PlainText(cell.Blocks))
+				}
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
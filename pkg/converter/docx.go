@@ -0,0 +1,1051 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Namespaces de WordprocessingML (transicional y estricto)
+const (
+	wordNamespace       = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	wordStrictNamespace = "http://purl.oclc.org/ooxml/wordprocessingml/main"
+)
+
+// ParseDocx lee un archivo DOCX y construye su modelo de documento. document.xml y
+// las partes de encabezado y pie de página se recorren en streaming con encoding/xml.
+func ParseDocx(r io.ReaderAt, size int64) (*Document, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo DOCX: %w", err)
+	}
+
+	p := &docxParser{
+		pkg:      newZipPackage(archive),
+		counters: make(map[string][]int),
+	}
+
+	mainPart := p.mainDocumentPart()
+	if !p.pkg.has(mainPart) {
+		return nil, fmt.Errorf("error al leer archivo DOCX: no contiene %s", mainPart)
+	}
+
+	// Estilos y numeración son opcionales
+	styles, err := p.loadStyles(path.Join(path.Dir(mainPart), "styles.xml"))
+	if err != nil {
+		return nil, err
+	}
+	p.styles = styles
+	numbering, err := p.loadNumbering(path.Join(path.Dir(mainPart), "numbering.xml"))
+	if err != nil {
+		return nil, err
+	}
+	p.numbering = numbering
+
+	doc := &Document{}
+	body, err := p.parsePart(mainPart)
+	if err != nil {
+		return nil, err
+	}
+	doc.Body = body
+
+	// Encabezados y pies referenciados por las secciones del documento
+	mainRels := p.rels
+	for _, ref := range p.headerRefs {
+		blocks, err := p.parseReferencedPart(mainRels, ref)
+		if err != nil {
+			return nil, err
+		}
+		doc.Header = append(doc.Header, blocks...)
+	}
+	for _, ref := range p.footerRefs {
+		blocks, err := p.parseReferencedPart(mainRels, ref)
+		if err != nil {
+			return nil, err
+		}
+		doc.Footer = append(doc.Footer, blocks...)
+	}
+
+	return doc, nil
+}
+
+// docxParser mantiene el estado del recorrido: partes del archivo, relaciones de la
+// parte actual, estilos, numeración y contadores de listas.
+type docxParser struct {
+	pkg       zipPackage
+	rels      map[string]docxRelationship
+	styles    map[string]*docxStyle
+	numbering *docxNumbering
+
+	// counters guarda el último número usado por lista (numId) y nivel
+	counters map[string][]int
+
+	headerRefs []string
+	footerRefs []string
+}
+
+type docxRelationship struct {
+	Type   string
+	Target string
+	// External indica que Target es una URL y no una parte del paquete
+	External bool
+}
+
+// docxStyle es un estilo de párrafo o de carácter de styles.xml
+type docxStyle struct {
+	Name       string
+	BasedOn    string
+	OutlineLvl *int
+	NumID      string
+	Ilvl       int
+	Bold       *bool
+	Italic     *bool
+}
+
+type docxNumbering struct {
+	// nums asocia cada numId con su abstractNumId
+	nums     map[string]string
+	abstract map[string]map[int]docxListLevel
+}
+
+type docxListLevel struct {
+	Format string // numFmt: bullet, decimal, lowerLetter, upperRoman, none...
+	Text   string // lvlText, ej: "%1." o "%1.%2)"
+	Start  int
+}
+
+// runProps es el formato de un run (o el heredado de un estilo)
+type runProps struct {
+	Bold   *bool
+	Italic *bool
+	Style  string
+}
+
+// paragraphProps son las propiedades de un párrafo relevantes para el modelo
+type paragraphProps struct {
+	Style      string
+	OutlineLvl *int
+	NumID      *string
+	Ilvl       *int
+}
+
+// --- Partes del paquete ---
+
+// mainDocumentPart retorna la ruta de document.xml según _rels/.rels
+func (p *docxParser) mainDocumentPart() string {
+	rels, err := p.loadRels("_rels/.rels", "")
+	if err == nil {
+		for _, rel := range rels {
+			if strings.HasSuffix(rel.Type, "/officeDocument") && !rel.External {
+				return rel.Target
+			}
+		}
+	}
+	return "word/document.xml"
+}
+
+// relsPath retorna la ruta del archivo de relaciones de una parte
+func relsPath(part string) string {
+	return path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+}
+
+// loadRels lee un archivo de relaciones. Los destinos internos se resuelven
+// relativos al directorio de la parte de origen (baseDir).
+func (p *docxParser) loadRels(name, baseDir string) (map[string]docxRelationship, error) {
+	rc, err := p.pkg.open(name, "DOCX")
+	if err != nil || rc == nil {
+		return map[string]docxRelationship{}, err
+	}
+	defer rc.Close()
+
+	var parsed struct {
+		Relationships []struct {
+			ID         string `xml:"Id,attr"`
+			Type       string `xml:"Type,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("error al leer %s del DOCX: %w", name, err)
+	}
+
+	rels := make(map[string]docxRelationship, len(parsed.Relationships))
+	for _, rel := range parsed.Relationships {
+		external := strings.EqualFold(rel.TargetMode, "External")
+		target := rel.Target
+		if !external {
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join(baseDir, target)
+			}
+		}
+		rels[rel.ID] = docxRelationship{Type: rel.Type, Target: target, External: external}
+	}
+	return rels, nil
+}
+
+// loadStyles lee los estilos de párrafo y de carácter de styles.xml
+func (p *docxParser) loadStyles(name string) (map[string]*docxStyle, error) {
+	styles := make(map[string]*docxStyle)
+	rc, err := p.pkg.open(name, "DOCX")
+	if err != nil || rc == nil {
+		return styles, err
+	}
+	defer rc.Close()
+
+	type onOff struct {
+		Val *string `xml:"val,attr"`
+	}
+	type valAttr struct {
+		Val string `xml:"val,attr"`
+	}
+	var parsed struct {
+		Styles []struct {
+			ID      string   `xml:"styleId,attr"`
+			Name    *valAttr `xml:"name"`
+			BasedOn *valAttr `xml:"basedOn"`
+			PPr     struct {
+				OutlineLvl *valAttr `xml:"outlineLvl"`
+				NumPr      struct {
+					Ilvl  *valAttr `xml:"ilvl"`
+					NumID *valAttr `xml:"numId"`
+				} `xml:"numPr"`
+			} `xml:"pPr"`
+			RPr struct {
+				Bold   *onOff `xml:"b"`
+				Italic *onOff `xml:"i"`
+			} `xml:"rPr"`
+		} `xml:"style"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("error al leer estilos del DOCX: %w", err)
+	}
+
+	for _, s := range parsed.Styles {
+		style := &docxStyle{}
+		if s.Name != nil {
+			style.Name = s.Name.Val
+		}
+		if s.BasedOn != nil {
+			style.BasedOn = s.BasedOn.Val
+		}
+		if s.PPr.OutlineLvl != nil {
+			if lvl, err := strconv.Atoi(s.PPr.OutlineLvl.Val); err == nil {
+				style.OutlineLvl = &lvl
+			}
+		}
+		if s.PPr.NumPr.NumID != nil {
+			style.NumID = s.PPr.NumPr.NumID.Val
+			if s.PPr.NumPr.Ilvl != nil {
+				style.Ilvl, _ = strconv.Atoi(s.PPr.NumPr.Ilvl.Val)
+			}
+		}
+		if s.RPr.Bold != nil {
+			style.Bold = boolPtr(onOffValue(s.RPr.Bold.Val))
+		}
+		if s.RPr.Italic != nil {
+			style.Italic = boolPtr(onOffValue(s.RPr.Italic.Val))
+		}
+		styles[s.ID] = style
+	}
+	return styles, nil
+}
+
+// loadNumbering lee las definiciones de listas de numbering.xml
+func (p *docxParser) loadNumbering(name string) (*docxNumbering, error) {
+	numbering := &docxNumbering{
+		nums:     make(map[string]string),
+		abstract: make(map[string]map[int]docxListLevel),
+	}
+	rc, err := p.pkg.open(name, "DOCX")
+	if err != nil || rc == nil {
+		return numbering, err
+	}
+	defer rc.Close()
+
+	type valAttr struct {
+		Val string `xml:"val,attr"`
+	}
+	var parsed struct {
+		Abstract []struct {
+			ID     string `xml:"abstractNumId,attr"`
+			Levels []struct {
+				Ilvl   int      `xml:"ilvl,attr"`
+				Start  *valAttr `xml:"start"`
+				NumFmt *valAttr `xml:"numFmt"`
+				Text   *valAttr `xml:"lvlText"`
+			} `xml:"lvl"`
+		} `xml:"abstractNum"`
+		Nums []struct {
+			ID         string  `xml:"numId,attr"`
+			AbstractID valAttr `xml:"abstractNumId"`
+		} `xml:"num"`
+	}
+	if err := xml.NewDecoder(rc).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("error al leer numeración del DOCX: %w", err)
+	}
+
+	for _, a := range parsed.Abstract {
+		levels := make(map[int]docxListLevel, len(a.Levels))
+		for _, l := range a.Levels {
+			level := docxListLevel{Format: "decimal", Start: 1}
+			if l.NumFmt != nil {
+				level.Format = l.NumFmt.Val
+			}
+			if l.Text != nil {
+				level.Text = l.Text.Val
+			}
+			if l.Start != nil {
+				if start, err := strconv.Atoi(l.Start.Val); err == nil {
+					level.Start = start
+				}
+			}
+			levels[l.Ilvl] = level
+		}
+		numbering.abstract[a.ID] = levels
+	}
+	for _, n := range parsed.Nums {
+		numbering.nums[n.ID] = n.AbstractID.Val
+	}
+	return numbering, nil
+}
+
+// parseReferencedPart parsea un encabezado o pie referenciado desde la parte principal
+func (p *docxParser) parseReferencedPart(mainRels map[string]docxRelationship, relID string) ([]Block, error) {
+	rel, ok := mainRels[relID]
+	if !ok || rel.External {
+		return nil, nil
+	}
+	return p.parsePart(rel.Target)
+}
+
+// parsePart recorre una parte (document.xml, headerN.xml, footerN.xml) y retorna sus bloques
+func (p *docxParser) parsePart(name string) ([]Block, error) {
+	rels, err := p.loadRels(relsPath(name), path.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	p.rels = rels
+
+	rc, err := p.pkg.open(name, "DOCX")
+	if err != nil || rc == nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error al leer %s del DOCX: %w", name, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		// Raíz de la parte: w:document contiene w:body; w:hdr y w:ftr contienen bloques
+		switch start.Name.Local {
+		case "document":
+			continue
+		case "body", "hdr", "ftr":
+			parsed, err := p.parseBlocks(d)
+			if err != nil {
+				return nil, fmt.Errorf("error al leer %s del DOCX: %w", name, err)
+			}
+			blocks = append(blocks, parsed...)
+		default:
+			if err := d.Skip(); err != nil {
+				return nil, fmt.Errorf("error al leer %s del DOCX: %w", name, err)
+			}
+		}
+	}
+}
+
+// --- Recorrido de contenido ---
+
+// parseBlocks lee los bloques de un contenedor (body, celda, cuadro de texto...)
+// hasta el cierre del contenedor
+func (p *docxParser) parseBlocks(d *xml.Decoder) ([]Block, error) {
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return blocks, nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "p"):
+				para, extra, err := p.parseParagraph(d)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, para)
+				blocks = append(blocks, extra...)
+			case isWord(t.Name, "tbl"):
+				table, err := p.parseTable(d)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, table)
+			case isWord(t.Name, "sectPr"):
+				if err := p.parseSectionRefs(d); err != nil {
+					return nil, err
+				}
+			case isContentWrapper(t.Name):
+				// Controles de contenido y marcado personalizado: su contenido cuenta
+				nested, err := p.parseBlocks(d)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, nested...)
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// parseSectionRefs registra los encabezados y pies referenciados por una sección
+func (p *docxParser) parseSectionRefs(d *xml.Decoder) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "headerReference"):
+				p.headerRefs = appendUnique(p.headerRefs, attrValue(t, "id"))
+			case isWord(t.Name, "footerReference"):
+				p.footerRefs = appendUnique(p.footerRefs, attrValue(t, "id"))
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseParagraph lee un w:p. Los cuadros de texto anclados en el párrafo se
+// retornan aparte, como bloques que siguen al párrafo.
+func (p *docxParser) parseParagraph(d *xml.Decoder) (*Paragraph, []Block, error) {
+	para := &Paragraph{}
+	var props paragraphProps
+	var extra []Block
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			p.applyParagraphProps(para, props)
+			return para, extra, nil
+		case xml.StartElement:
+			if isWord(t.Name, "pPr") {
+				props, err = p.parseParagraphProps(d)
+				if err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			if err := p.parseInline(d, t, para, props.Style, "", &extra); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+}
+
+// parseInline procesa un elemento dentro de un párrafo: runs, hipervínculos,
+// revisiones y controles de contenido
+func (p *docxParser) parseInline(d *xml.Decoder, start xml.StartElement, para *Paragraph, paraStyle, link string, extra *[]Block) error {
+	switch {
+	case isWord(start.Name, "r"):
+		runs, boxes, err := p.parseRun(d, paraStyle, link)
+		if err != nil {
+			return err
+		}
+		for _, run := range runs {
+			para.Runs = appendRun(para.Runs, run)
+		}
+		*extra = append(*extra, boxes...)
+		return nil
+	case isWord(start.Name, "hyperlink"):
+		target := link
+		if rel, ok := p.rels[attrValue(start, "id")]; ok && rel.External {
+			target = rel.Target
+		}
+		return p.parseInlineChildren(d, para, paraStyle, target, extra)
+	case isWord(start.Name, "del"), isWord(start.Name, "moveFrom"):
+		// Texto eliminado en control de cambios
+		return d.Skip()
+	case isWord(start.Name, "ins"), isWord(start.Name, "moveTo"), isWord(start.Name, "smartTag"),
+		isWord(start.Name, "fldSimple"), isContentWrapper(start.Name):
+		return p.parseInlineChildren(d, para, paraStyle, link, extra)
+	default:
+		return d.Skip()
+	}
+}
+
+// parseInlineChildren procesa los hijos de un elemento en línea hasta su cierre
+func (p *docxParser) parseInlineChildren(d *xml.Decoder, para *Paragraph, paraStyle, link string, extra *[]Block) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			if err := p.parseInline(d, t, para, paraStyle, link, extra); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseRun lee un w:r. Retorna sus fragmentos de texto y el contenido de los
+// cuadros de texto que contenga.
+func (p *docxParser) parseRun(d *xml.Decoder, paraStyle, link string) ([]Run, []Block, error) {
+	var props runProps
+	var text strings.Builder
+	var boxes []Block
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if text.Len() == 0 {
+				return nil, boxes, nil
+			}
+			run := Run{
+				Text:   text.String(),
+				Bold:   p.resolveFormat(props.Bold, props.Style, paraStyle, func(s *docxStyle) *bool { return s.Bold }),
+				Italic: p.resolveFormat(props.Italic, props.Style, paraStyle, func(s *docxStyle) *bool { return s.Italic }),
+				Link:   link,
+			}
+			return []Run{run}, boxes, nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "rPr"):
+				props, err = parseRunProps(d)
+			case isWord(t.Name, "t"):
+				err = readText(d, &text)
+			case isWord(t.Name, "tab"), isWord(t.Name, "ptab"):
+				text.WriteString("\t")
+				err = d.Skip()
+			case isWord(t.Name, "br"), isWord(t.Name, "cr"):
+				text.WriteString("\n")
+				err = d.Skip()
+			case isWord(t.Name, "noBreakHyphen"):
+				text.WriteString("-")
+				err = d.Skip()
+			case isWord(t.Name, "rPrChange"), isWord(t.Name, "delText"), isWord(t.Name, "instrText"):
+				err = d.Skip()
+			default:
+				// Dibujos, formas VML y contenido alternativo pueden contener cuadros de texto
+				var found []Block
+				found, err = p.findTextBoxes(d)
+				boxes = append(boxes, found...)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+}
+
+// findTextBoxes busca w:txbxContent dentro de un dibujo. De mc:AlternateContent se
+// usa mc:Choice y se ignora mc:Fallback, que repite el mismo contenido.
+func (p *docxParser) findTextBoxes(d *xml.Decoder) ([]Block, error) {
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return blocks, nil
+		case xml.StartElement:
+			var found []Block
+			switch {
+			case isWord(t.Name, "txbxContent"):
+				found, err = p.parseBlocks(d)
+			case t.Name.Local == "Fallback":
+				err = d.Skip()
+			default:
+				found, err = p.findTextBoxes(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, found...)
+		}
+	}
+}
+
+// parseTable lee un w:tbl
+func (p *docxParser) parseTable(d *xml.Decoder) (*Table, error) {
+	table := &Table{}
+	rows, err := p.parseRows(d)
+	if err != nil {
+		return nil, err
+	}
+	table.Rows = rows
+	return table, nil
+}
+
+// parseRows lee las filas de una tabla (o de un control de contenido dentro de ella)
+func (p *docxParser) parseRows(d *xml.Decoder) ([]TableRow, error) {
+	var rows []TableRow
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return rows, nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "tr"):
+				cells, err := p.parseCells(d)
+				if err != nil {
+					return nil, err
+				}
+				rows = append(rows, TableRow{Cells: cells})
+			case isContentWrapper(t.Name):
+				nested, err := p.parseRows(d)
+				if err != nil {
+					return nil, err
+				}
+				rows = append(rows, nested...)
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// parseCells lee las celdas de una fila
+func (p *docxParser) parseCells(d *xml.Decoder) ([]TableCell, error) {
+	var cells []TableCell
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return cells, nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "tc"):
+				blocks, err := p.parseBlocks(d)
+				if err != nil {
+					return nil, err
+				}
+				cells = append(cells, TableCell{Blocks: blocks})
+			case isContentWrapper(t.Name):
+				nested, err := p.parseCells(d)
+				if err != nil {
+					return nil, err
+				}
+				cells = append(cells, nested...)
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+// --- Propiedades ---
+
+func (p *docxParser) parseParagraphProps(d *xml.Decoder) (paragraphProps, error) {
+	var props paragraphProps
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return props, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if depth == 0 {
+				return props, nil
+			}
+			depth--
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "numPr"):
+				// Se procesan los hijos (ilvl, numId) en las siguientes vueltas
+				depth++
+				continue
+			case isWord(t.Name, "pStyle"):
+				props.Style = attrValue(t, "val")
+			case isWord(t.Name, "outlineLvl"):
+				if lvl, err := strconv.Atoi(attrValue(t, "val")); err == nil {
+					props.OutlineLvl = &lvl
+				}
+			case isWord(t.Name, "ilvl"):
+				if lvl, err := strconv.Atoi(attrValue(t, "val")); err == nil {
+					props.Ilvl = &lvl
+				}
+			case isWord(t.Name, "numId"):
+				numID := attrValue(t, "val")
+				props.NumID = &numID
+			case isWord(t.Name, "sectPr"):
+				// Fin de una sección intermedia: puede referenciar otros encabezados
+				if err := p.parseSectionRefs(d); err != nil {
+					return props, err
+				}
+				continue
+			}
+			if err := d.Skip(); err != nil {
+				return props, err
+			}
+		}
+	}
+}
+
+func parseRunProps(d *xml.Decoder) (runProps, error) {
+	var props runProps
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return props, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return props, nil
+		case xml.StartElement:
+			switch {
+			case isWord(t.Name, "b"):
+				props.Bold = boolPtr(onOffAttr(t))
+			case isWord(t.Name, "i"):
+				props.Italic = boolPtr(onOffAttr(t))
+			case isWord(t.Name, "rStyle"):
+				props.Style = attrValue(t, "val")
+			}
+			if err := d.Skip(); err != nil {
+				return props, err
+			}
+		}
+	}
+}
+
+// applyParagraphProps resuelve el nivel de título y la lista del párrafo a partir
+// de sus propiedades directas y de su estilo
+func (p *docxParser) applyParagraphProps(para *Paragraph, props paragraphProps) {
+	style := props.Style
+	if style == "" {
+		style = p.defaultParagraphStyle()
+	}
+
+	// Nivel de título: outlineLvl directo, del estilo o por nombre del estilo
+	outline := props.OutlineLvl
+	if outline == nil {
+		outline = p.styleOutlineLevel(style)
+	}
+	if outline != nil && *outline >= 0 && *outline < 9 {
+		para.HeadingLevel = *outline + 1
+	}
+
+	// Lista: numPr directo o del estilo. numId 0 quita la numeración heredada.
+	numID, ilvl := p.styleNumbering(style)
+	if props.NumID != nil {
+		numID = *props.NumID
+	}
+	if props.Ilvl != nil {
+		ilvl = *props.Ilvl
+	}
+	if numID != "" && numID != "0" {
+		para.List = p.nextListItem(numID, ilvl)
+	}
+}
+
+// defaultParagraphStyle retorna el id del estilo "Normal"
+func (p *docxParser) defaultParagraphStyle() string {
+	if _, ok := p.styles["Normal"]; ok {
+		return "Normal"
+	}
+	return ""
+}
+
+// styleOutlineLevel retorna el nivel de esquema de un estilo, heredado por basedOn.
+// Los estilos "heading N" y "Title" se reconocen por nombre.
+func (p *docxParser) styleOutlineLevel(id string) *int {
+	for depth := 0; id != "" && depth < 10; depth++ {
+		style, ok := p.styles[id]
+		if !ok {
+			return nil
+		}
+		if style.OutlineLvl != nil {
+			return style.OutlineLvl
+		}
+		name := strings.ToLower(style.Name)
+		if name == "title" {
+			return intPtr(0)
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(name, "heading ")); err == nil && strings.HasPrefix(name, "heading ") {
+			return intPtr(n - 1)
+		}
+		id = style.BasedOn
+	}
+	return nil
+}
+
+// styleNumbering retorna la lista (numId, ilvl) definida por un estilo o sus padres
+func (p *docxParser) styleNumbering(id string) (string, int) {
+	for depth := 0; id != "" && depth < 10; depth++ {
+		style, ok := p.styles[id]
+		if !ok {
+			break
+		}
+		if style.NumID != "" {
+			return style.NumID, style.Ilvl
+		}
+		id = style.BasedOn
+	}
+	return "", 0
+}
+
+// resolveFormat determina si un run es negrita o cursiva: formato directo, luego el
+// estilo de carácter y finalmente el estilo del párrafo
+func (p *docxParser) resolveFormat(direct *bool, runStyle, paraStyle string, get func(*docxStyle) *bool) bool {
+	if direct != nil {
+		return *direct
+	}
+	for _, id := range []string{runStyle, paraStyle} {
+		for depth := 0; id != "" && depth < 10; depth++ {
+			style, ok := p.styles[id]
+			if !ok {
+				break
+			}
+			if v := get(style); v != nil {
+				return *v
+			}
+			id = style.BasedOn
+		}
+	}
+	return false
+}
+
+// nextListItem avanza el contador de la lista y retorna el elemento con su viñeta
+// o número ya formateado
+func (p *docxParser) nextListItem(numID string, ilvl int) *ListItem {
+	if ilvl < 0 || ilvl > 8 {
+		ilvl = 0
+	}
+	levels := p.numbering.abstract[p.numbering.nums[numID]]
+	level, ok := levels[ilvl]
+	if !ok {
+		level = docxListLevel{Format: "bullet"}
+	}
+	if level.Format == "none" {
+		return nil
+	}
+
+	// Avanzar el contador del nivel y reiniciar los niveles inferiores
+	counters, ok := p.counters[numID]
+	if !ok {
+		counters = make([]int, 9)
+		p.counters[numID] = counters
+	}
+	if counters[ilvl] == 0 {
+		counters[ilvl] = level.Start
+	} else {
+		counters[ilvl]++
+	}
+	for i := ilvl + 1; i < len(counters); i++ {
+		counters[i] = 0
+	}
+
+	if level.Format == "bullet" {
+		return &ListItem{Level: ilvl, Marker: "•"}
+	}
+
+	marker := level.Text
+	if marker == "" {
+		marker = "%" + strconv.Itoa(ilvl+1) + "."
+	}
+	for i := 0; i <= ilvl; i++ {
+		format := "decimal"
+		if l, ok := levels[i]; ok {
+			format = l.Format
+		}
+		value := counters[i]
+		if value == 0 {
+			value = 1
+		}
+		marker = strings.ReplaceAll(marker, "%"+strconv.Itoa(i+1), formatListNumber(value, format))
+	}
+	return &ListItem{Level: ilvl, Ordered: true, Marker: marker}
+}
+
+// formatListNumber formatea el número de un elemento de lista según numFmt
+func formatListNumber(n int, format string) string {
+	switch format {
+	case "lowerLetter":
+		return strings.ToLower(letterNumber(n))
+	case "upperLetter":
+		return letterNumber(n)
+	case "lowerRoman":
+		return strings.ToLower(romanNumber(n))
+	case "upperRoman":
+		return romanNumber(n)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// letterNumber convierte 1, 2... 26, 27 en A, B... Z, AA (como Word)
+func letterNumber(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
+
+func romanNumber(n int) string {
+	if n < 1 || n > 3999 {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(symbols[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
+
+// --- Funciones auxiliares ---
+
+// isWord indica si el elemento pertenece a WordprocessingML con el nombre local dado
+func isWord(name xml.Name, local string) bool {
+	return name.Local == local && (name.Space == wordNamespace || name.Space == wordStrictNamespace)
+}
+
+// isContentWrapper indica si el elemento solo envuelve contenido que debe recorrerse:
+// controles de contenido (w:sdt), marcado personalizado y contenido alternativo
+func isContentWrapper(name xml.Name) bool {
+	switch {
+	case isWord(name, "sdt"), isWord(name, "sdtContent"), isWord(name, "customXml"):
+		return true
+	case name.Local == "AlternateContent", name.Local == "Choice":
+		return true
+	}
+	return false
+}
+
+// readText agrega el texto de un w:t hasta su cierre
+func readText(d *xml.Decoder, sb *strings.Builder) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func attrValue(start xml.StartElement, local string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// onOffAttr interpreta una propiedad booleana (w:b, w:i): sin w:val es verdadera
+func onOffAttr(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "val" {
+			return onOffValue(&attr.Value)
+		}
+	}
+	return true
+}
+
+func onOffValue(val *string) bool {
+	if val == nil {
+		return true
+	}
+	switch *val {
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
+
+// appendRun agrega el run uniendo su texto al anterior si tienen el mismo formato;
+// Word suele partir el texto en varios runs (revisiones, ortografía)
+func appendRun(runs []Run, run Run) []Run {
+	if n := len(runs); n > 0 {
+		last := &runs[n-1]
+		if last.Bold == run.Bold && last.Italic == run.Italic && last.Link == run.Link {
+			last.Text += run.Text
+			return runs
+		}
+	}
+	return append(runs, run)
+}
+
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func intPtr(v int) *int { return &v }
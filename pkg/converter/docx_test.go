@@ -0,0 +1,189 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "actualiza los archivos golden de testdata")
+
+// docxTemplates son plantillas de CV reales reducidas: una columna con encabezado y
+// pie, dos columnas maquetadas con tabla y barra lateral en un cuadro de texto
+var docxTemplates = []string{"classic", "two-column", "textbox-sidebar"}
+
+// zipDocx empaqueta las partes XML de testdata/docx/<template> como un DOCX
+func zipDocx(tb testing.TB, template string) []byte {
+	tb.Helper()
+	return zipDir(tb, filepath.Join("testdata", "docx", template))
+}
+
+// zipDir empaqueta los archivos de un directorio (DOCX y ODT descomprimidos)
+func zipDir(tb testing.TB, root string) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		w, err := writer.Create(filepath.ToSlash(name))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseDocxGolden(t *testing.T) {
+	for _, template := range docxTemplates {
+		t.Run(template, func(t *testing.T) {
+			content := zipDocx(t, template)
+			doc, err := ParseDocx(bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				t.Fatalf("ParseDocx: %v", err)
+			}
+
+			assertGolden(t, filepath.Join("testdata", "docx", template+".golden"), dumpDocument(doc))
+		})
+	}
+}
+
+// assertGolden compara el modelo con el archivo golden; con -update lo reescribe
+func assertGolden(t *testing.T, golden, got string) {
+	t.Helper()
+
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("falta el archivo golden (ejecutar con -update): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("modelo distinto a %s:\n--- obtenido ---\n%s\n--- esperado ---\n%s", golden, got, want)
+	}
+}
+
+func TestConvertToPDFRendersDocxTemplates(t *testing.T) {
+	for _, template := range docxTemplates {
+		t.Run(template, func(t *testing.T) {
+			result, err := ConvertToPDF(context.Background(), writeInput(t, zipDocx(t, template)), "cv.docx")
+			if err != nil {
+				t.Fatalf("ConvertToPDF: %v", err)
+			}
+			defer result.Close()
+
+			content, err := io.ReadAll(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Filename != "cv.pdf" || int64(len(content)) != result.Size {
+				t.Errorf("PDF inesperado: filename=%q size=%d leídos=%d", result.Filename, result.Size, len(content))
+			}
+			if !bytes.HasPrefix(content, []byte("%PDF-")) {
+				t.Errorf("el resultado no es un PDF: %q", content[:min(len(content), 16)])
+			}
+		})
+	}
+}
+
+func TestParseDocxRejectsInvalidArchive(t *testing.T) {
+	content := []byte("no es un zip")
+	if _, err := ParseDocx(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Fatal("se esperaba error para un archivo que no es ZIP")
+	}
+
+	// ZIP válido sin word/document.xml
+	content = zipWith(t, "[Content_Types].xml")
+	if _, err := ParseDocx(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Fatal("se esperaba error para un DOCX sin document.xml")
+	}
+}
+
+// dumpDocument representa el modelo como texto estilo Markdown para los golden:
+// "#" por nivel de título, **negrita**, _cursiva_, [texto](url) y tablas indentadas
+func dumpDocument(doc *Document) string {
+	var sb strings.Builder
+	for _, section := range []struct {
+		name   string
+		blocks []Block
+	}{{"header", doc.Header}, {"body", doc.Body}, {"footer", doc.Footer}} {
+		fmt.Fprintf(&sb, "=== %s ===\n", section.name)
+		dumpBlocks(&sb, section.blocks, "")
+	}
+	return sb.String()
+}
+
+func dumpBlocks(sb *strings.Builder, blocks []Block, indent string) {
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *Paragraph:
+			sb.WriteString(indent)
+			if b.HeadingLevel > 0 {
+				sb.WriteString(strings.Repeat("#", b.HeadingLevel) + " ")
+			}
+			if b.List != nil {
+				kind := "bullet"
+				if b.List.Ordered {
+					kind = "ordered"
+				}
+				fmt.Fprintf(sb, "%s(%s %s) ", strings.Repeat("  ", b.List.Level), kind, b.List.Marker)
+			}
+			for _, run := range b.Runs {
+				sb.WriteString(dumpRun(run))
+			}
+			sb.WriteString("\n")
+		case *Table:
+			fmt.Fprintf(sb, "%s<table columns=%d>\n", indent, b.ColumnCount())
+			for i, row := range b.Rows {
+				fmt.Fprintf(sb, "%s  <row %d>\n", indent, i+1)
+				for j, cell := range row.Cells {
+					fmt.Fprintf(sb, "%s    <cell %d>\n", indent, j+1)
+					dumpBlocks(sb, cell.Blocks, indent+"      ")
+				}
+			}
+		}
+	}
+}
+
+func dumpRun(run Run) string {
+	text := strings.NewReplacer("\t", `\t`, "\n", `\n`).Replace(run.Text)
+	if run.Bold {
+		text = "**" + text + "**"
+	}
+	if run.Italic {
+		text = "_" + text + "_"
+	}
+	if run.Link != "" {
+		text = "[" + text + "](" + run.Link + ")"
+	}
+	return text
+}
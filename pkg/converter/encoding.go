@@ -0,0 +1,184 @@
+package converter
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Codificaciones de texto que reconoce DetectTextEncoding
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingUTF16LE     = "utf-16le"
+	EncodingUTF16BE     = "utf-16be"
+	EncodingWindows1252 = "windows-1252"
+	EncodingISO88591    = "iso-8859-1"
+)
+
+// minUTF16NULRatio es la proporción mínima de caracteres con un byte nulo para
+// reconocer UTF-16 sin BOM: en texto latino casi todos los caracteres lo tienen
+const minUTF16NULRatio = 0.2
+
+// DetectTextEncoding identifica la codificación de un archivo de texto por su BOM o,
+// si no lo tiene, por su contenido: UTF-16 por la posición de los bytes nulos,
+// UTF-8 si es válido y, si no, Windows-1252 cuando hay bytes entre 0x80 y 0x9F (que
+// en ISO-8859-1 son caracteres de control) o ISO-8859-1 en otro caso.
+func DetectTextEncoding(r io.ReaderAt, size int64) string {
+	head := make([]byte, sniffLength)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return EncodingUTF8
+	}
+	return detectTextEncoding(head[:n], int64(n) < size)
+}
+
+// detectTextEncoding es DetectTextEncoding sobre los primeros bytes del archivo. Si
+// el contenido sigue después de head (truncated), se tolera una runa UTF-8 cortada
+// al final.
+func detectTextEncoding(head []byte, truncated bool) string {
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		return EncodingUTF8
+	case bytes.HasPrefix(head, utf16LEBOM):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(head, utf16BEBOM):
+		return EncodingUTF16BE
+	}
+	if encodingName := detectUTF16(head); encodingName != "" {
+		return encodingName
+	}
+	if validUTF8(head, truncated) {
+		return EncodingUTF8
+	}
+	for _, b := range head {
+		if b >= 0x80 && b <= 0x9F {
+			return EncodingWindows1252
+		}
+	}
+	return EncodingISO88591
+}
+
+// detectUTF16 reconoce texto UTF-16 sin BOM: los caracteres latinos tienen un byte
+// nulo, siempre en la misma posición (el segundo en little endian), y el texto no
+// tiene caracteres de control binarios. Retorna "" si no parece UTF-16.
+func detectUTF16(head []byte) string {
+	pairs := len(head) / 2
+	if pairs < 2 {
+		return ""
+	}
+	var nulFirst, nulSecond int
+	for i := 0; i < 2*pairs; i += 2 {
+		if head[i] == 0 {
+			nulFirst++
+		}
+		if head[i+1] == 0 {
+			nulSecond++
+		}
+	}
+
+	var encodingName string
+	var order unicode.Endianness
+	switch {
+	case nulFirst == 0 && float64(nulSecond) >= minUTF16NULRatio*float64(pairs):
+		encodingName, order = EncodingUTF16LE, unicode.LittleEndian
+	case nulSecond == 0 && float64(nulFirst) >= minUTF16NULRatio*float64(pairs):
+		encodingName, order = EncodingUTF16BE, unicode.BigEndian
+	default:
+		return ""
+	}
+
+	for i := 0; i < 2*pairs; i += 2 {
+		unit := rune(head[i]) | rune(head[i+1])<<8
+		if order == unicode.BigEndian {
+			unit = rune(head[i])<<8 | rune(head[i+1])
+		}
+		if isBinaryControl(unit) {
+			return ""
+		}
+	}
+	return encodingName
+}
+
+// validUTF8 indica si los bytes son UTF-8 válido, tolerando una runa cortada al final
+// si el contenido sigue (truncated)
+func validUTF8(head []byte, truncated bool) bool {
+	if utf8.Valid(head) {
+		return true
+	}
+	if !truncated {
+		return false
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(head); i++ {
+		if utf8.RuneStart(head[len(head)-i]) {
+			return !utf8.FullRune(head[len(head)-i:]) && utf8.Valid(head[:len(head)-i])
+		}
+	}
+	return false
+}
+
+// textEncoding retorna la codificación de x/text para el nombre detectado. Los
+// decodificadores UTF descartan el BOM inicial.
+func textEncoding(encodingName string) encoding.Encoding {
+	switch encodingName {
+	case EncodingUTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	case EncodingUTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+	case EncodingWindows1252:
+		return charmap.Windows1252
+	case EncodingISO88591:
+		return charmap.ISO8859_1
+	default:
+		return unicode.UTF8BOM
+	}
+}
+
+// scanTextLines divide el texto en líneas terminadas en "\n", "\r\n" o "\r" (Mac
+// clásico). Se usa con bufio.Scanner.
+func scanTextLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// Falta saber si al "\r" le sigue un "\n"
+		return 0, nil, nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// normalizeTextLine prepara una línea de texto plano para el PDF: los saltos de
+// página y de línea Unicode pasan a ser saltos de línea, y se descartan los
+// caracteres de control (salvo tabulaciones) y los BOM en medio del texto
+func normalizeTextLine(line string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return r
+		case r == '\f' || r == '\v' || r == 0x85 || r == 0x2028 || r == 0x2029:
+			return '\n'
+		case r < 0x20 || (r >= 0x7F && r <= 0x9F) || r == 0xFEFF:
+			return -1
+		}
+		return r
+	}, line)
+}
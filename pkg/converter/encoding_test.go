@@ -0,0 +1,130 @@
+package converter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+// utf16Bytes codifica el texto en UTF-16, con o sin BOM
+func utf16Bytes(text string, bigEndian, bom bool) []byte {
+	units := utf16.Encode([]rune(text))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	out := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		if bigEndian {
+			out = append(out, byte(unit>>8), byte(unit))
+		} else {
+			out = append(out, byte(unit), byte(unit>>8))
+		}
+	}
+	return out
+}
+
+// encodingSample es un CV con acentos, comillas tipográficas y finales de línea de
+// Windows, junto a su contenido en cada codificación
+const encodingSample = "José “Pepe” Pérez\r\nDesarrollador – 5 años\r\n"
+
+var encodingFixtures = []struct {
+	name    string
+	content []byte
+	want    string
+}{
+	{"utf-8", []byte(encodingSample), EncodingUTF8},
+	{"utf-8 con BOM", append([]byte{0xEF, 0xBB, 0xBF}, encodingSample...), EncodingUTF8},
+	{"utf-16le con BOM", utf16Bytes(encodingSample, false, true), EncodingUTF16LE},
+	{"utf-16be con BOM", utf16Bytes(encodingSample, true, true), EncodingUTF16BE},
+	{"utf-16le sin BOM", utf16Bytes(encodingSample, false, false), EncodingUTF16LE},
+	{"utf-16be sin BOM", utf16Bytes(encodingSample, true, false), EncodingUTF16BE},
+	{"windows-1252", []byte("Jos\xe9 \x93Pepe\x94 P\xe9rez\r\nDesarrollador \x96 5 a\xf1os\r\n"), EncodingWindows1252},
+}
+
+func TestDetectTextEncoding(t *testing.T) {
+	tests := append(slices.Clone(encodingFixtures), []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"iso-8859-1", []byte("Jos\xe9 P\xe9rez\n"), EncodingISO88591},
+		{"ascii", []byte("Juan Perez\n"), EncodingUTF8},
+		{"vacío", nil, EncodingUTF8},
+		{"nulos en ambas posiciones no es utf-16", []byte{'M', 'Z', 0, 0, 3, 0, 0, 0, 4, 0}, EncodingUTF8},
+	}...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectTextEncoding(bytes.NewReader(tt.content), int64(len(tt.content))); got != tt.want {
+				t.Errorf("DetectTextEncoding = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+
+	// Una runa cortada en el límite de lo examinado no descarta UTF-8
+	content := append(bytes.Repeat([]byte("a"), sniffLength-1), "é"...)
+	if got := DetectTextEncoding(bytes.NewReader(content), int64(len(content))); got != EncodingUTF8 {
+		t.Errorf("DetectTextEncoding con runa cortada = %q, se esperaba %q", got, EncodingUTF8)
+	}
+}
+
+func TestDetectMIMETypeRecognizesUTF16WithoutBOM(t *testing.T) {
+	for _, tt := range []struct {
+		content []byte
+		want    string
+	}{
+		{utf16Bytes(encodingSample, false, false), MIMETextUTF16LE},
+		{utf16Bytes(encodingSample, true, false), MIMETextUTF16BE},
+	} {
+		if got := DetectMIMEType(bytes.NewReader(tt.content), int64(len(tt.content))); got != tt.want {
+			t.Errorf("DetectMIMEType = %q, se esperaba %q", got, tt.want)
+		}
+	}
+}
+
+func TestConvertTextToPDFDecodesEncodings(t *testing.T) {
+	for _, tt := range encodingFixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			pdf, err := convertTextToPDF(context.Background(), writeInput(t, tt.content))
+			if err != nil {
+				t.Fatalf("convertTextToPDF: %v", err)
+			}
+			text := extractPDFText(t, pdf)
+			for _, want := range []string{"José “Pepe” Pérez", "Desarrollador – 5 años"} {
+				if !strings.Contains(text, want) {
+					t.Errorf("falta %q en el PDF:\n%s", want, text)
+				}
+			}
+			if strings.ContainsRune(text, '\uFEFF') || strings.ContainsRune(text, '\r') {
+				t.Errorf("el PDF conserva el BOM o retornos de carro:\n%q", text)
+			}
+		})
+	}
+}
+
+func TestScanTextLines(t *testing.T) {
+	// Se lee de a un byte para probar un "\r" al final de lo leído
+	content := "uno\r\ndos\rtres\ncuatro\r\r\ncinco\r"
+	scanner := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(content)))
+	scanner.Split(scanTextLines)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	want := []string{"uno", "dos", "tres", "cuatro", "", "cinco"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("líneas = %q, se esperaba %q", lines, want)
+	}
+}
+
+func TestNormalizeTextLine(t *testing.T) {
+	got := normalizeTextLine("Nombre:\tJosé\x00\x07\fPágina 2\u2028fin\x7f\u0085\uFEFF")
+	if want := "Nombre:\tJosé\nPágina 2\nfin\n"; got != want {
+		t.Errorf("normalizeTextLine = %q, se esperaba %q", got, want)
+	}
+}
@@ -0,0 +1,293 @@
+package converter
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fuente Unicode embebida (DejaVu Sans, licencia en fonts/LICENSE). Cubre latín
+// extendido, griego, cirílico y muchos símbolos, a diferencia de las fuentes core
+// de gofpdf que solo cubren cp1252. No hay variante cursiva: el texto en cursiva
+// se escribe en recta.
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	dejaVuSans []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	dejaVuSansBold []byte
+)
+
+const pdfFontFamily = "DejaVuSans"
+
+// replacementRune se usa para los caracteres sin glifo en la fuente
+const replacementRune = '\uFFFD'
+
+// glyphSet indica qué caracteres del plano básico (BMP) tienen glifo. gofpdf
+// codifica el texto como UTF-16 sin pares sustitutos, así que los caracteres fuera
+// del BMP (emoji, por ejemplo) no se pueden escribir aunque la fuente los tenga.
+type glyphSet [0x10000 / 64]uint64
+
+func (s *glyphSet) add(r rune) {
+	if r >= 0 && r < 0x10000 {
+		s[r/64] |= 1 << (r % 64)
+	}
+}
+
+func (s *glyphSet) has(r rune) bool {
+	return r >= 0 && r < 0x10000 && s[r/64]&(1<<(r%64)) != 0
+}
+
+// Cobertura de cada variante, calculada una sola vez desde la tabla cmap
+var (
+	regularGlyphs = sync.OnceValue(func() *glyphSet { return mustGlyphSet(dejaVuSans) })
+	boldGlyphs    = sync.OnceValue(func() *glyphSet { return mustGlyphSet(dejaVuSansBold) })
+)
+
+// newPDF crea un documento A4 con la familia Unicode registrada. Cada variante
+// cuesta varios MB al cargarla en gofpdf, así que solo se registra la negrita
+// (style "B") si se pide.
+func newPDF(withBold bool) *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", dejaVuSans)
+	if withBold {
+		pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", dejaVuSansBold)
+	}
+	return pdf
+}
+
+// pdfText prepara el texto para escribirlo con la variante (negrita o no) de la
+// fuente. Los caracteres sin glifo se reemplazan de forma gradual: primero por su
+// forma de compatibilidad (ej: "𝐀" → "A", "Ｊ" → "J"), luego por sus caracteres
+// base sin los acentos que falten y, por último, por "�". Se descartan los
+// caracteres invisibles de control y de composición de emoji.
+//
+// El texto se compone en NFC ("e" + "◌́" → "é") y se descartan las marcas
+// combinantes que queden sueltas: gofpdf trata los glifos sin avance como si
+// tuvieran un ancho enorme y cortaría la línea en cada una.
+func pdfText(text string, bold bool) string {
+	glyphs := regularGlyphs()
+	if bold {
+		glyphs = boldGlyphs()
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+	runes := []rune(norm.NFC.String(text))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n' || r == '\t':
+			sb.WriteRune(r)
+		case isIgnorable(r):
+			// No se escriben
+		case unicode.Is(unicode.Mn, r):
+			// Marca combinante sin carácter precompuesto
+		case glyphs.has(r):
+			sb.WriteRune(r)
+		default:
+			// El carácter con sus marcas combinantes se reemplaza como una unidad
+			end := i + 1
+			for end < len(runes) && unicode.Is(unicode.Mn, runes[end]) {
+				end++
+			}
+			sb.WriteString(fallbackText(string(runes[i:end]), glyphs))
+			i = end - 1
+		}
+	}
+	return sb.String()
+}
+
+// isIgnorable indica si el carácter es invisible: controles, selectores de
+// variación, unión de emoji y modificadores de tono de piel
+func isIgnorable(r rune) bool {
+	return unicode.IsControl(r) ||
+		unicode.Is(unicode.Variation_Selector, r) ||
+		unicode.Is(unicode.Join_Control, r) ||
+		r == '\u200B' || r == '\uFEFF' ||
+		(r >= 0x1F3FB && r <= 0x1F3FF)
+}
+
+// fallbackText busca una alternativa escribible para un carácter sin glifo (y las
+// marcas combinantes que lo siguen)
+func fallbackText(cluster string, glyphs *glyphSet) string {
+	// Forma de compatibilidad completa
+	compat := norm.NFKC.String(cluster)
+	if compat != cluster && allCovered(compat, glyphs) {
+		return compat
+	}
+
+	// Caracteres base, descartando las marcas combinantes que no tengan glifo
+	var base strings.Builder
+	for _, d := range norm.NFKD.String(cluster) {
+		if glyphs.has(d) && !unicode.Is(unicode.Mn, d) {
+			base.WriteRune(d)
+		} else if !unicode.Is(unicode.Mn, d) {
+			base.Reset()
+			break
+		}
+	}
+	if base.Len() > 0 {
+		return base.String()
+	}
+
+	if glyphs.has(replacementRune) {
+		return string(replacementRune)
+	}
+	return "?"
+}
+
+func allCovered(text string, glyphs *glyphSet) bool {
+	for _, r := range text {
+		if !glyphs.has(r) {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// --- Lectura de la tabla cmap de TrueType ---
+
+var errInvalidFont = errors.New("fuente TrueType inválida")
+
+func mustGlyphSet(font []byte) *glyphSet {
+	set, err := parseGlyphSet(font)
+	if err != nil {
+		// Las fuentes están embebidas: un error aquí es un defecto del binario
+		panic(err)
+	}
+	return set
+}
+
+// parseGlyphSet lee los caracteres con glifo desde la subtabla cmap Unicode de la
+// fuente (formato 12 o, si no existe, formato 4)
+func parseGlyphSet(font []byte) (*glyphSet, error) {
+	cmap, err := findTable(font, "cmap")
+	if err != nil {
+		return nil, err
+	}
+	if len(cmap) < 4 {
+		return nil, errInvalidFont
+	}
+
+	var format4, format12 []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		record := 4 + i*8
+		if record+8 > len(cmap) {
+			return nil, errInvalidFont
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) {
+			return nil, errInvalidFont
+		}
+		subtable := cmap[offset:]
+		switch format := binary.BigEndian.Uint16(subtable); {
+		case format == 12 && (platform == 0 || (platform == 3 && encoding == 10)):
+			format12 = subtable
+		case format == 4 && (platform == 0 || (platform == 3 && encoding == 1)):
+			format4 = subtable
+		}
+	}
+
+	switch {
+	case format12 != nil:
+		return parseCmapFormat12(format12)
+	case format4 != nil:
+		return parseCmapFormat4(format4)
+	}
+	return nil, errInvalidFont
+}
+
+// findTable retorna el contenido de una tabla de la fuente
+func findTable(font []byte, tag string) ([]byte, error) {
+	if len(font) < 12 {
+		return nil, errInvalidFont
+	}
+	numTables := int(binary.BigEndian.Uint16(font[4:]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(font) {
+			break
+		}
+		if string(font[record:record+4]) != tag {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(font[record+8:]))
+		length := int(binary.BigEndian.Uint32(font[record+12:]))
+		if offset+length > len(font) {
+			return nil, errInvalidFont
+		}
+		return font[offset : offset+length], nil
+	}
+	return nil, errInvalidFont
+}
+
+func parseCmapFormat12(table []byte) (*glyphSet, error) {
+	if len(table) < 16 {
+		return nil, errInvalidFont
+	}
+	set := &glyphSet{}
+	numGroups := int(binary.BigEndian.Uint32(table[12:]))
+	for i := 0; i < numGroups; i++ {
+		group := 16 + i*12
+		if group+12 > len(table) {
+			return nil, errInvalidFont
+		}
+		start := rune(binary.BigEndian.Uint32(table[group:]))
+		end := rune(binary.BigEndian.Uint32(table[group+4:]))
+		startGlyph := binary.BigEndian.Uint32(table[group+8:])
+		for r := start; r <= end && r < 0x10000; r++ {
+			if startGlyph+uint32(r-start) != 0 {
+				set.add(r)
+			}
+		}
+	}
+	return set, nil
+}
+
+func parseCmapFormat4(table []byte) (*glyphSet, error) {
+	if len(table) < 14 {
+		return nil, errInvalidFont
+	}
+	segCount := int(binary.BigEndian.Uint16(table[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	deltas := startCodes + 2*segCount
+	rangeOffsets := deltas + 2*segCount
+	if rangeOffsets+2*segCount > len(table) {
+		return nil, errInvalidFont
+	}
+
+	set := &glyphSet{}
+	for i := 0; i < segCount; i++ {
+		end := int(binary.BigEndian.Uint16(table[endCodes+2*i:]))
+		start := int(binary.BigEndian.Uint16(table[startCodes+2*i:]))
+		delta := int(binary.BigEndian.Uint16(table[deltas+2*i:]))
+		rangeOffset := int(binary.BigEndian.Uint16(table[rangeOffsets+2*i:]))
+		for c := start; c <= end && c < 0xFFFF; c++ {
+			glyph := (c + delta) & 0xFFFF
+			if rangeOffset != 0 {
+				index := rangeOffsets + 2*i + rangeOffset + 2*(c-start)
+				if index+2 > len(table) {
+					return nil, errInvalidFont
+				}
+				glyph = int(binary.BigEndian.Uint16(table[index:]))
+				if glyph != 0 {
+					glyph = (glyph + delta) & 0xFFFF
+				}
+			}
+			if glyph != 0 {
+				set.add(rune(c))
+			}
+		}
+	}
+	return set, nil
+}
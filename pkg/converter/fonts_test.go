@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// scriptSamples son nombres y textos de CV en distintos sistemas de escritura
+var scriptSamples = []string{
+	"Español: José Pérez Núñez, Logroño",
+	"Polski: Zażółć gęślą jaźń, Łódź",
+	"Türkçe: Çağrı Öztürk, İstanbul Şişli",
+	"Ελληνικά: Γιώργος Παπαδόπουλος, Αθήνα",
+	"Русский: Анна Смирнова, Москва",
+	"Українська: Олена Ковальчук, Київ",
+	"Símbolos: C++ → Go • 100 € ✔",
+}
+
+// textShowRe encuentra los strings escritos con el operador Tj en los contenidos
+var textShowRe = regexp.MustCompile(`(?s)\(((?:\\.|[^\\)])*)\)\s*Tj`)
+
+// extractPDFText genera el PDF sin compresión y decodifica el texto escrito. Con
+// fuentes UTF-8, gofpdf escribe cada string como UTF-16BE; se retorna una línea por Tj.
+func extractPDFText(t *testing.T, pdf *gofpdf.Fpdf) string {
+	t.Helper()
+
+	pdf.SetCompression(false)
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatalf("error al generar PDF: %v", err)
+	}
+
+	var lines []string
+	for _, match := range textShowRe.FindAllSubmatch(buf.Bytes(), -1) {
+		raw := unescapePDFString(match[1])
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 0; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		lines = append(lines, string(utf16.Decode(units)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func unescapePDFString(s []byte) []byte {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'r':
+				out = append(out, '\r')
+			case 'n':
+				out = append(out, '\n')
+			default:
+				out = append(out, s[i])
+			}
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return out
+}
+
+func TestConvertTextToPDFRoundTripsScripts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cv.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(scriptSamples, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf, err := convertTextToPDF(context.Background(), path)
+	if err != nil {
+		t.Fatalf("convertTextToPDF: %v", err)
+	}
+	text := extractPDFText(t, pdf)
+	for _, sample := range scriptSamples {
+		if !strings.Contains(text, sample) {
+			t.Errorf("el PDF no contiene %q; texto extraído:\n%s", sample, text)
+		}
+	}
+}
+
+func TestRenderDocumentRoundTripsScripts(t *testing.T) {
+	doc := &Document{Header: []Block{&Paragraph{Runs: []Run{{Text: "Γιώργος — CV"}}}}}
+	for _, sample := range scriptSamples {
+		label, value, _ := strings.Cut(sample, ": ")
+		doc.Body = append(doc.Body,
+			&Paragraph{HeadingLevel: 2, Runs: []Run{{Text: label}}},
+			&Paragraph{List: &ListItem{Marker: "•"}, Runs: []Run{{Text: value, Bold: true}}},
+		)
+	}
+
+	text := extractPDFText(t, renderDocument(doc))
+	for _, sample := range append(scriptSamples, "Γιώργος — CV") {
+		label, value, found := strings.Cut(sample, ": ")
+		if !found {
+			value = label
+		}
+		if !strings.Contains(text, label) || !strings.Contains(text, value) {
+			t.Errorf("el PDF no contiene %q; texto extraído:\n%s", sample, text)
+		}
+	}
+}
+
+func TestPDFTextFallsBackForMissingGlyphs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"texto cubierto", "Łódź İzmir Αθήνα Москва", "Łódź İzmir Αθήνα Москва"},
+		{"emoji fuera del BMP", "Equipo 🚀", "Equipo �"},
+		{"selector de variación", "✔️ Hecho", "✔ Hecho"},
+		{"secuencia de emoji con tono de piel", "👍🏽", "�"},
+		{"letras matemáticas", "𝐀𝐧𝐚", "Ana"},
+		{"letras de ancho completo", "Ｊａｖａ", "Java"},
+		{"espacios invisibles", "Ana\u200bPérez\ufeff", "AnaPérez"},
+		{"marca combinante", "Pe\u0301rez Nguye\u0302\u0303n", "Pérez Nguyễn"},
+		{"controles", "a\x00b\x07c", "abc"},
+		{"UTF-8 inválido", "Ana\xffPérez", "Ana�Pérez"},
+		{"tabulaciones y saltos", "a\tb\nc", "a\tb\nc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, bold := range []bool{false, true} {
+				if got := pdfText(tt.in, bold); got != tt.want {
+					t.Errorf("pdfText(%q, bold=%v) = %q, se esperaba %q", tt.in, bold, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDocumentWritesFallbackText(t *testing.T) {
+	doc := &Document{Body: []Block{&Paragraph{Runs: []Run{{Text: "Ana 🚀 𝐏𝐞́𝐫𝐞𝐳"}}}}}
+
+	// Sin caracteres fuera del BMP, que gofpdf no puede codificar
+	text := extractPDFText(t, renderDocument(doc))
+	if !strings.Contains(text, "Ana � Pérez") {
+		t.Errorf("texto extraído inesperado: %q", text)
+	}
+}
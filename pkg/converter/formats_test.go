@@ -0,0 +1,169 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// formatFixtures son el mismo CV de ejemplo en cada formato de entrada: los
+// archivos viven en testdata/<formato> y el modelo esperado en <fixture>.golden
+var formatFixtures = []struct {
+	format string
+	name   string // Archivo (o directorio descomprimido, para ODT)
+	parse  ParseFunc
+}{
+	{"odt", "classic", ParseODT},
+	{"rtf", "classic.rtf", ParseRTF},
+	{"html", "classic.html", ParseHTML},
+	{"markdown", "classic.md", ParseMarkdown},
+}
+
+func readFixture(t *testing.T, format, name string) []byte {
+	t.Helper()
+
+	path := filepath.Join("testdata", format, name)
+	if format == "odt" {
+		return zipDir(t, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestParseFormatsGolden(t *testing.T) {
+	for _, fixture := range formatFixtures {
+		t.Run(fixture.format, func(t *testing.T) {
+			content := readFixture(t, fixture.format, fixture.name)
+			doc, err := fixture.parse(bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			base := strings.TrimSuffix(fixture.name, filepath.Ext(fixture.name))
+			assertGolden(t, filepath.Join("testdata", fixture.format, base+".golden"), dumpDocument(doc))
+		})
+	}
+}
+
+func TestConvertToPDFRendersFormats(t *testing.T) {
+	for _, fixture := range formatFixtures {
+		t.Run(fixture.format, func(t *testing.T) {
+			ext := filepath.Ext(fixture.name)
+			if ext == "" {
+				ext = "." + fixture.format
+			}
+			result, err := ConvertToPDF(context.Background(), writeInput(t, readFixture(t, fixture.format, fixture.name)), "cv"+ext)
+			if err != nil {
+				t.Fatalf("ConvertToPDF: %v", err)
+			}
+			defer result.Close()
+
+			content, err := io.ReadAll(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Filename != "cv.pdf" || !bytes.HasPrefix(content, []byte("%PDF-")) {
+				t.Errorf("PDF inesperado: filename=%q inicio=%q", result.Filename, content[:min(len(content), 16)])
+			}
+		})
+	}
+}
+
+func TestParseFormatsDecodeLegacyEncodings(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   ParseFunc
+		content []byte
+		want    string
+	}{
+		{"rtf con página de códigos 1251", ParseRTF, []byte(`{\rtf1\ansi\ansicpg1251 \'c0\'ed\'ed\'e0\par}`), "Анна"},
+		{"rtf con \\uc2", ParseRTF, []byte(`{\rtf1\ansi\uc2 Jos\u233??\par}`), "José"},
+		{"html con meta charset", ParseHTML, []byte(`<meta charset="windows-1252"><p>Jos` + "\xe9" + `</p>`), "José"},
+		{"html utf-16 con BOM", ParseHTML, []byte{0xFF, 0xFE, '<', 0, 'p', 0, '>', 0, 0xF1, 0}, "ñ"},
+		{"markdown windows-1252", ParseMarkdown, []byte("# Jos\xe9"), "José"},
+		{"markdown utf-8 con BOM", ParseMarkdown, []byte("\xef\xbb\xbf# José"), "José"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := tt.parse(bytes.NewReader(tt.content), int64(len(tt.content)))
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := PlainText(doc.Body); got != tt.want {
+				t.Errorf("texto = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormatsRejectInvalidInput(t *testing.T) {
+	content := []byte("no es un zip")
+	if _, err := ParseODT(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Error("se esperaba error para un ODT que no es ZIP")
+	}
+	content = zipWith(t, "mimetype")
+	if _, err := ParseODT(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Error("se esperaba error para un ODT sin content.xml")
+	}
+	content = []byte("Hola")
+	if _, err := ParseRTF(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Error("se esperaba error para un RTF sin {\\rtf")
+	}
+	content = []byte(`{\rtf1` + strings.Repeat("{", maxRTFDepth+1))
+	if _, err := ParseRTF(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Error("se esperaba error para un RTF con anidación excesiva")
+	}
+}
+
+func TestLookupFormat(t *testing.T) {
+	tests := []struct {
+		ext, mimeType string
+		want          string
+	}{
+		{".odt", MIMEODT, "odt"},
+		{".RTF", MIMERTF, "rtf"},
+		{".htm", MIMEHTML, "html"},
+		{".html", MIMETextUTF8, "html"},
+		{".md", MIMETextUTF8, "markdown"},
+		{".markdown", MIMEText, "markdown"},
+		{".txt", MIMEHTML, "txt"},
+		{".odt", MIMEZip, ""},
+		{".html", MIMEPDF, ""},
+		{".exe", MIMEUnknown, ""},
+	}
+	for _, tt := range tests {
+		format, ok := LookupFormat(tt.ext, tt.mimeType)
+		got := ""
+		if ok {
+			got = format.Name
+		}
+		if got != tt.want {
+			t.Errorf("LookupFormat(%q, %q) = %q, se esperaba %q", tt.ext, tt.mimeType, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterFormatRejectsDuplicates(t *testing.T) {
+	err := RegisterFormat(Format{Name: "otro-md", Extensions: []string{".MD"}, MIMETypes: []string{MIMETextUTF8}, Parse: ParseMarkdown})
+	if err == nil {
+		t.Error("se esperaba error al registrar de nuevo .md con texto UTF-8")
+	}
+	if err := RegisterFormat(Format{Name: "sin-parser", Extensions: []string{".xyz"}, MIMETypes: []string{MIMEUnknown}}); err == nil {
+		t.Error("se esperaba error para un formato sin parser")
+	}
+}
+
+func TestSupportedExtensions(t *testing.T) {
+	want := []string{".doc", ".docx", ".htm", ".html", ".jpeg", ".jpg", ".markdown", ".md", ".odt", ".pdf", ".png", ".rtf", ".txt"}
+	if got := SupportedExtensions(); !slices.Equal(got, want) {
+		t.Errorf("SupportedExtensions = %v, se esperaba %v", got, want)
+	}
+}
@@ -0,0 +1,282 @@
+package converter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// ParseHTML lee un documento HTML y construye su modelo. La codificación se
+// detecta por BOM o por la declaración <meta charset>; sin ellas se asume UTF-8.
+// Se ignoran scripts, estilos y el <head>; el encabezado y el pie del PDF quedan
+// vacíos.
+func ParseHTML(r io.ReaderAt, size int64) (*Document, error) {
+	head := make([]byte, min(size, 1024))
+	if _, err := r.ReadAt(head, 0); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error al leer archivo HTML: %w", err)
+	}
+	encoding, _, _ := charset.DetermineEncoding(head, "")
+	// BOMOverride descarta el BOM, que el decodificador de charset deja como texto
+	decoder := unicode.BOMOverride(encoding.NewDecoder())
+
+	root, err := html.Parse(transform.NewReader(io.NewSectionReader(r, 0, size), decoder))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo HTML: %w", err)
+	}
+
+	b := &htmlBuilder{}
+	b.walk(root, htmlContext{listLevel: -1})
+	b.flush()
+	return &Document{Body: b.blocks}, nil
+}
+
+// htmlContext es el formato heredado por los nodos hijos
+type htmlContext struct {
+	bold, italic bool
+	link         string
+	pre          bool
+	heading      int
+	listLevel    int  // Nivel de la lista que contiene al nodo, -1 fuera de listas
+	inListItem   bool // Los párrafos dentro de un <li> conservan su sangría
+}
+
+// htmlBuilder acumula los bloques de un contenedor (el documento o una celda)
+type htmlBuilder struct {
+	blocks []Block
+	para   *Paragraph
+	// marker es la viñeta del <li> actual, para su primer párrafo
+	marker *ListItem
+}
+
+// htmlSkipped son los elementos sin contenido visible
+var htmlSkipped = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true,
+	atom.Template: true, atom.Svg: true, atom.Math: true, atom.Iframe: true,
+	atom.Object: true, atom.Select: true, atom.Textarea: true, atom.Button: true,
+}
+
+// htmlBlocks son los elementos que empiezan un párrafo nuevo
+var htmlBlocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Header: true, atom.Footer: true, atom.Main: true, atom.Aside: true,
+	atom.Nav: true, atom.Blockquote: true, atom.Address: true, atom.Dl: true,
+	atom.Dt: true, atom.Dd: true, atom.Figure: true, atom.Figcaption: true,
+	atom.Hr: true, atom.Form: true, atom.Fieldset: true, atom.Details: true,
+	atom.Summary: true, atom.Center: true, atom.Li: true, atom.Caption: true,
+}
+
+var htmlHeadings = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+func (b *htmlBuilder) walk(n *html.Node, ctx htmlContext) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			b.text(c.Data, ctx)
+		case html.ElementNode:
+			b.element(c, ctx)
+		case html.DocumentNode:
+			b.walk(c, ctx)
+		}
+	}
+}
+
+func (b *htmlBuilder) element(n *html.Node, ctx htmlContext) {
+	if level, ok := htmlHeadings[n.DataAtom]; ok {
+		b.flush()
+		ctx.heading = level
+		b.walk(n, ctx)
+		b.flush()
+		return
+	}
+
+	switch {
+	case htmlSkipped[n.DataAtom]:
+	case n.DataAtom == atom.Br:
+		b.appendText("\n", ctx)
+	case n.DataAtom == atom.B || n.DataAtom == atom.Strong:
+		ctx.bold = true
+		b.walk(n, ctx)
+	case n.DataAtom == atom.I || n.DataAtom == atom.Em || n.DataAtom == atom.Cite:
+		ctx.italic = true
+		b.walk(n, ctx)
+	case n.DataAtom == atom.A:
+		if href := htmlAttr(n, "href"); href != "" && !strings.HasPrefix(href, "#") &&
+			!strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			ctx.link = href
+		}
+		b.walk(n, ctx)
+	case n.DataAtom == atom.Img:
+		// Sin imágenes: el texto alternativo las reemplaza
+		if alt := htmlAttr(n, "alt"); alt != "" {
+			b.text(alt, ctx)
+		}
+	case n.DataAtom == atom.Ul || n.DataAtom == atom.Ol:
+		b.flush()
+		b.list(n, ctx)
+	case n.DataAtom == atom.Table:
+		b.flush()
+		if table := b.table(n, ctx); len(table.Rows) > 0 {
+			b.blocks = append(b.blocks, table)
+		}
+	case n.DataAtom == atom.Pre:
+		b.flush()
+		ctx.pre = true
+		b.walk(n, ctx)
+		b.flush()
+	case htmlBlocks[n.DataAtom]:
+		b.flush()
+		b.walk(n, ctx)
+		b.flush()
+	default:
+		// Elementos en línea (span, code, small...) y desconocidos
+		b.walk(n, ctx)
+	}
+}
+
+// list procesa un <ul> u <ol>. Los números respetan start, type y value.
+func (b *htmlBuilder) list(n *html.Node, ctx htmlContext) {
+	level := ctx.listLevel + 1
+	ordered := n.DataAtom == atom.Ol
+	format := htmlAttr(n, "type")
+	counter := 0
+	if start, err := strconv.Atoi(htmlAttr(n, "start")); err == nil {
+		counter = start - 1
+	}
+
+	itemCtx := ctx
+	itemCtx.listLevel = level
+	itemCtx.inListItem = true
+	itemCtx.heading = 0
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			// Texto suelto o listas anidadas directamente en la lista
+			if c.Type == html.ElementNode {
+				b.element(c, itemCtx)
+			} else if c.Type == html.TextNode {
+				b.text(c.Data, itemCtx)
+			}
+			continue
+		}
+
+		counter++
+		if value, err := strconv.Atoi(htmlAttr(c, "value")); err == nil {
+			counter = value
+		}
+		item := &ListItem{Level: level, Ordered: ordered, Marker: "•"}
+		if ordered {
+			item.Marker = formatNumberStyle(counter, format) + "."
+		}
+
+		b.flush()
+		b.marker = item
+		b.walk(c, itemCtx)
+		b.flush()
+		b.marker = nil
+	}
+}
+
+// table construye una tabla con las filas de <thead>, <tbody> y <tfoot>
+func (b *htmlBuilder) table(n *html.Node, ctx htmlContext) *Table {
+	table := &Table{}
+	cellCtx := htmlContext{listLevel: -1, link: ctx.link}
+
+	var rows func(n *html.Node)
+	rows = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				rows(c)
+			case atom.Tr:
+				var row TableRow
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != html.ElementNode || (cell.DataAtom != atom.Td && cell.DataAtom != atom.Th) {
+						continue
+					}
+					sub := &htmlBuilder{}
+					cellCtx.bold = cell.DataAtom == atom.Th
+					sub.walk(cell, cellCtx)
+					sub.flush()
+					row.Cells = append(row.Cells, TableCell{Blocks: sub.blocks})
+				}
+				if len(row.Cells) > 0 {
+					table.Rows = append(table.Rows, row)
+				}
+			}
+		}
+	}
+	rows(n)
+	return table
+}
+
+// text agrega texto de un nodo; fuera de <pre> los espacios se colapsan como en
+// el navegador
+func (b *htmlBuilder) text(text string, ctx htmlContext) {
+	if !ctx.pre {
+		text = collapseWhitespace(text)
+	}
+	b.appendText(text, ctx)
+}
+
+func (b *htmlBuilder) appendText(text string, ctx htmlContext) {
+	if b.para == nil {
+		if strings.TrimSpace(text) == "" && text != "\n" {
+			return
+		}
+		b.para = &Paragraph{HeadingLevel: ctx.heading}
+		switch {
+		case b.marker != nil:
+			b.para.List = b.marker
+			b.marker = nil
+		case ctx.inListItem:
+			b.para.List = &ListItem{Level: ctx.listLevel}
+		}
+		if !ctx.pre {
+			text = strings.TrimLeft(text, " ")
+		}
+	}
+	// Un espacio al inicio del texto sobra si el anterior ya termina en espacio
+	if n := len(b.para.Runs); !ctx.pre && n > 0 && strings.HasPrefix(text, " ") &&
+		(strings.HasSuffix(b.para.Runs[n-1].Text, " ") || strings.HasSuffix(b.para.Runs[n-1].Text, "\n")) {
+		text = text[1:]
+	}
+	if text == "" {
+		return
+	}
+	b.para.Runs = appendRun(b.para.Runs, Run{Text: text, Bold: ctx.bold, Italic: ctx.italic, Link: ctx.link})
+}
+
+// flush cierra el párrafo actual
+func (b *htmlBuilder) flush() {
+	if b.para == nil {
+		return
+	}
+	trimParagraph(b.para)
+	if !b.para.IsEmpty() {
+		b.blocks = append(b.blocks, b.para)
+	}
+	b.para = nil
+}
+
+// --- Funciones auxiliares ---
+
+func htmlAttr(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,383 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Límites de la conversión de imágenes
+const (
+	imageMargin      = 10.0 // Margen de la página (mm)
+	imageJPEGQuality = 90
+	maxImagePixels   = 60_000_000 // Evita decodificar imágenes enormes (ej: 10000x10000)
+	mmPerInch        = 25.4
+)
+
+// ImageOptions configura la conversión de imágenes (fotos o escaneos de un CV) a PDF
+type ImageOptions struct {
+	MaxDPI   int    // Resolución máxima en la página; las imágenes con más se reducen
+	PageSize string // Tamaño de página: "A4", "Letter" o "Legal"
+}
+
+// imageOptions es la configuración vigente (ver SetImageOptions)
+var imageOptions = ImageOptions{MaxDPI: 200, PageSize: "A4"}
+
+// imagePageSizes son los tamaños de página aceptados, en mm y en orientación vertical
+var imagePageSizes = map[string]gofpdf.SizeType{
+	"a4":     {Wd: 210, Ht: 297},
+	"letter": {Wd: 215.9, Ht: 279.4},
+	"legal":  {Wd: 215.9, Ht: 355.6},
+}
+
+// SetImageOptions cambia la configuración de la conversión de imágenes. Debe
+// llamarse durante la inicialización del programa.
+func SetImageOptions(opts ImageOptions) error {
+	if opts.MaxDPI < 72 {
+		return fmt.Errorf("resolución máxima de imágenes inválida: %d DPI (mínimo 72)", opts.MaxDPI)
+	}
+	if _, ok := imagePageSizes[strings.ToLower(opts.PageSize)]; !ok {
+		return fmt.Errorf("tamaño de página inválido: %q (se acepta A4, Letter o Legal)", opts.PageSize)
+	}
+	imageOptions = opts
+	return nil
+}
+
+// ConvertImagesToPDF arma un PDF con una página por imagen (JPEG o PNG), en el orden
+// recibido. Se usa para los CVs fotografiados o escaneados en varias páginas; con
+// una sola imagen el resultado es el mismo que el de ConvertToPDF. filename define
+// el nombre del PDF resultante. El llamador debe cerrar el PDFFile. Si ctx se cancela,
+// la conversión se interrumpe y se retorna ctx.Err().
+func ConvertImagesToPDF(ctx context.Context, inputPaths []string, filename string) (*PDFFile, error) {
+	if len(inputPaths) == 0 {
+		return nil, errors.New("no hay imágenes para convertir")
+	}
+	for _, inputPath := range inputPaths {
+		if err := checkImageType(inputPath); err != nil {
+			return nil, err
+		}
+	}
+
+	pdf, err := renderImages(ctx, inputPaths)
+	if err != nil {
+		return nil, err
+	}
+	newFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".pdf"
+	return writeTempPDF(pdf, newFilename)
+}
+
+// checkImageType verifica por contenido que el archivo sea JPEG o PNG
+func checkImageType(inputPath string) error {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("error al abrir imagen: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error al leer imagen: %w", err)
+	}
+
+	if mimeType := DetectMIMEType(file, info.Size()); mimeType != MIMEJPEG && mimeType != MIMEPNG {
+		return fmt.Errorf("el contenido del archivo (%s) no es una imagen JPEG o PNG", mimeType)
+	}
+	return nil
+}
+
+// convertImageToPDF convierte una sola imagen; es el conversor del registro para
+// JPEG y PNG
+func convertImageToPDF(ctx context.Context, inputPath string) (*gofpdf.Fpdf, error) {
+	return renderImages(ctx, []string{inputPath})
+}
+
+// renderImages agrega cada imagen en su propia página. La orientación de la página
+// sigue a la de la imagen (horizontal o vertical) y la imagen se escala para
+// ocupar el área útil, centrada. ctx se revisa antes de cada página.
+func renderImages(ctx context.Context, inputPaths []string) (*gofpdf.Fpdf, error) {
+	opts := imageOptions
+	pageSize := imagePageSizes[strings.ToLower(opts.PageSize)]
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	for i, inputPath := range inputPaths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := loadPageImage(inputPath, pageSize, opts.MaxDPI)
+		if err != nil {
+			return nil, fmt.Errorf("error al convertir imagen %d: %w", i+1, err)
+		}
+
+		orientation := "P"
+		if page.landscape {
+			orientation = "L"
+		}
+		pdf.AddPageFormat(orientation, pageSize)
+
+		name := fmt.Sprintf("page-%d", i+1)
+		options := gofpdf.ImageOptions{ImageType: page.imageType}
+		pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(page.data))
+		pdf.ImageOptions(name, page.x, page.y, page.width, page.height, false, options, 0, "")
+		if err := pdf.Error(); err != nil {
+			return nil, fmt.Errorf("error al agregar imagen %d al PDF: %w", i+1, err)
+		}
+	}
+	return pdf, nil
+}
+
+// pageImage es una imagen lista para el PDF: su contenido codificado y su posición
+// en la página (mm)
+type pageImage struct {
+	data                []byte
+	imageType           string // "JPG" o "PNG", como lo espera gofpdf
+	landscape           bool
+	x, y, width, height float64
+}
+
+// loadPageImage lee la imagen, la endereza según su orientación EXIF y la reduce si
+// excede maxDPI en el tamaño que ocupa en la página. Un JPEG que no necesita cambios
+// se usa tal cual; los PNG siempre se vuelven a codificar porque gofpdf no acepta
+// PNG entrelazados ni de 16 bits.
+func loadPageImage(inputPath string, pageSize gofpdf.SizeType, maxDPI int) (*pageImage, error) {
+	data, err := os.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer imagen: %w", err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen inválida: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return nil, fmt.Errorf("imagen demasiado grande: %dx%d píxeles", config.Width, config.Height)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegEXIFOrientation(data)
+	}
+	// Las orientaciones 5 a 8 giran la imagen 90°: se intercambian ancho y alto
+	width, height := config.Width, config.Height
+	if orientation >= 5 {
+		width, height = height, width
+	}
+
+	page := &pageImage{landscape: width > height}
+	pageWidth, pageHeight := pageSize.Wd, pageSize.Ht
+	if page.landscape {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+	scale := math.Min((pageWidth-2*imageMargin)/float64(width), (pageHeight-2*imageMargin)/float64(height))
+	page.width = float64(width) * scale
+	page.height = float64(height) * scale
+	page.x = (pageWidth - page.width) / 2
+	page.y = (pageHeight - page.height) / 2
+
+	// Cantidad de píxeles que caben en el ancho ocupado a la resolución máxima
+	targetWidth, targetHeight := width, height
+	if maxWidth := int(page.width / mmPerInch * float64(maxDPI)); width > maxWidth {
+		targetWidth = maxWidth
+		targetHeight = max(1, int(math.Round(float64(height)*float64(maxWidth)/float64(width))))
+	}
+
+	if format == "jpeg" && orientation == 1 && targetWidth == width {
+		page.data, page.imageType = data, "JPG"
+		return page, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen inválida: %w", err)
+	}
+	if orientation >= 5 {
+		targetWidth, targetHeight = targetHeight, targetWidth
+	}
+	img := orientImage(resampleImage(src, targetWidth, targetHeight), orientation)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageJPEGQuality})
+		page.imageType = "JPG"
+	} else {
+		// Las imágenes opacas se guardan como PNG RGB de 8 bits
+		err = png.Encode(&buf, img)
+		page.imageType = "PNG"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al codificar imagen: %w", err)
+	}
+	page.data = buf.Bytes()
+	return page, nil
+}
+
+// resampleImage reduce la imagen a width x height promediando los píxeles de cada
+// área (o la copia, si tiene el mismo tamaño). La transparencia se aplana sobre
+// fondo blanco, como se vería impresa.
+func resampleImage(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	at := func(x, y int) (uint32, uint32, uint32, uint32) {
+		return src.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+	}
+	if rgba64, ok := src.(image.RGBA64Image); ok {
+		// Evita reservar un color.Color por píxel
+		at = func(x, y int) (uint32, uint32, uint32, uint32) {
+			c := rgba64.RGBA64At(bounds.Min.X+x, bounds.Min.Y+y)
+			return uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := at(sx, sy)
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			// Los colores son premultiplicados: sobre blanco se suma lo que falta de alfa
+			white := 0xFFFF - a/n
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r/n + white) >> 8),
+				G: uint8((g/n + white) >> 8),
+				B: uint8((b/n + white) >> 8),
+				A: 0xFF,
+			})
+		}
+	}
+	return dst
+}
+
+// orientImage aplica la orientación EXIF (1 a 8) para que la imagen quede derecha
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			// Píxel de origen que corresponde a (x, y) en la imagen derecha
+			var sx, sy int
+			switch orientation {
+			case 2: // Espejo horizontal
+				sx, sy = width-1-x, y
+			case 3: // Rotada 180°
+				sx, sy = width-1-x, height-1-y
+			case 4: // Espejo vertical
+				sx, sy = x, height-1-y
+			case 5: // Transpuesta
+				sx, sy = y, x
+			case 6: // Requiere girar 90° en sentido horario
+				sx, sy = y, height-1-x
+			case 7: // Transversa
+				sx, sy = width-1-y, height-1-x
+			case 8: // Requiere girar 90° en sentido antihorario
+				sx, sy = width-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// jpegEXIFOrientation retorna la orientación (1 a 8) del bloque EXIF del JPEG, o 1
+// si no la tiene. Se recorren los segmentos hasta el inicio de los datos de imagen.
+func jpegEXIFOrientation(data []byte) int {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// Bytes de relleno entre segmentos
+			i++
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			return 1
+		case marker >= 0xD0 && marker <= 0xD8 || marker == 0x01:
+			// Marcadores sin longitud
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation lee la etiqueta Orientation (0x0112) del primer IFD del bloque
+// TIFF del EXIF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:]) != 42 {
+		return 1
+	}
+
+	ifd := int64(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > int64(len(tiff)) {
+		return 1
+	}
+	count := int64(order.Uint16(tiff[ifd:]))
+	for k := int64(0); k < count; k++ {
+		entry := ifd + 2 + 12*k
+		if entry+12 > int64(len(tiff)) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) != 0x0112 {
+			continue
+		}
+		// Tipo SHORT: el valor ocupa los primeros 2 bytes del campo
+		if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+			return value
+		}
+		return 1
+	}
+	return 1
+}
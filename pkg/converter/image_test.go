@@ -0,0 +1,230 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// testImage arma una imagen con la mitad izquierda roja y la derecha azul, para
+// comprobar hacia dónde quedó girada
+func testImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBA{R: 0xFF, A: 0xFF}
+			if x >= width/2 {
+				c = color.NRGBA{B: 0xFF, A: 0xFF}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// encodeJPEG codifica la imagen y, si orientation > 0, agrega un bloque EXIF con esa
+// orientación justo después del inicio del archivo, como lo hacen las cámaras
+func encodeJPEG(tb testing.TB, img image.Image, orientation uint16, order binary.AppendByteOrder) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		tb.Fatal(err)
+	}
+	if orientation == 0 {
+		return buf.Bytes()
+	}
+
+	// Encabezado TIFF con un IFD de una entrada: Orientation (SHORT, 1 valor)
+	tiff := []byte("II")
+	if order == binary.BigEndian {
+		tiff = []byte("MM")
+	}
+	tiff = order.AppendUint16(tiff, 42)
+	tiff = order.AppendUint32(tiff, 8)
+	tiff = order.AppendUint16(tiff, 1)
+	tiff = order.AppendUint16(tiff, 0x0112)
+	tiff = order.AppendUint16(tiff, 3)
+	tiff = order.AppendUint32(tiff, 1)
+	tiff = order.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	content := buf.Bytes()
+	return append(append(append([]byte{}, content[:2]...), app1...), content[2:]...)
+}
+
+func encodePNG(tb testing.TB, img image.Image) []byte {
+	tb.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pdfPageCount cuenta los objetos de página del PDF generado
+func pdfPageCount(pdf []byte) int {
+	return len(regexp.MustCompile(`/Type /Page\b[^s]`).FindAll(pdf, -1))
+}
+
+func TestJPEGEXIFOrientation(t *testing.T) {
+	img := testImage(8, 4)
+	tests := []struct {
+		name    string
+		content []byte
+		want    int
+	}{
+		{"sin EXIF", encodeJPEG(t, img, 0, nil), 1},
+		{"little endian", encodeJPEG(t, img, 6, binary.LittleEndian), 6},
+		{"big endian", encodeJPEG(t, img, 8, binary.BigEndian), 8},
+		{"valor inválido", encodeJPEG(t, img, 42, binary.LittleEndian), 1},
+		{"truncado", encodeJPEG(t, img, 3, binary.LittleEndian)[:12], 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegEXIFOrientation(tt.content); got != tt.want {
+				t.Errorf("orientación = %d, se esperaba %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrientImage(t *testing.T) {
+	// Imagen de 2x1: rojo a la izquierda, azul a la derecha
+	src := resampleImage(testImage(2, 1), 2, 1)
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+
+	tests := []struct {
+		orientation   int
+		width, height int
+		topLeft       color.RGBA
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{6, 1, 2, red},  // Girada en sentido horario: el rojo queda arriba
+		{8, 1, 2, blue}, // En sentido antihorario: el azul queda arriba
+	}
+	for _, tt := range tests {
+		got := orientImage(src, tt.orientation)
+		bounds := got.Bounds()
+		if bounds.Dx() != tt.width || bounds.Dy() != tt.height || got.RGBAAt(0, 0) != tt.topLeft {
+			t.Errorf("orientación %d: %dx%d, arriba a la izquierda %v", tt.orientation, bounds.Dx(), bounds.Dy(), got.RGBAAt(0, 0))
+		}
+	}
+}
+
+func TestLoadPageImageFitsPage(t *testing.T) {
+	a4 := imagePageSizes["a4"]
+
+	t.Run("foto girada", func(t *testing.T) {
+		path := writeInput(t, encodeJPEG(t, testImage(40, 20), 6, binary.LittleEndian))
+		page, err := loadPageImage(path, a4, 200)
+		if err != nil {
+			t.Fatalf("loadPageImage: %v", err)
+		}
+		config, _, err := image.DecodeConfig(bytes.NewReader(page.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.landscape || page.imageType != "JPG" || config.Width != 20 || config.Height != 40 {
+			t.Errorf("landscape=%v tipo=%s tamaño=%dx%d", page.landscape, page.imageType, config.Width, config.Height)
+		}
+		// Ocupa el alto útil de la página vertical, centrada
+		if page.height != a4.Ht-2*imageMargin || page.x != (a4.Wd-page.width)/2 {
+			t.Errorf("posición inesperada: %+v", page)
+		}
+	})
+
+	t.Run("escaneo con más resolución que la máxima", func(t *testing.T) {
+		path := writeInput(t, encodePNG(t, testImage(3000, 1000)))
+		page, err := loadPageImage(path, a4, 72)
+		if err != nil {
+			t.Fatalf("loadPageImage: %v", err)
+		}
+		config, _, err := image.DecodeConfig(bytes.NewReader(page.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Página horizontal: 277 mm útiles a 72 DPI son 785 píxeles
+		if !page.landscape || page.imageType != "PNG" || config.Width != 785 || config.Height != 262 {
+			t.Errorf("landscape=%v tipo=%s tamaño=%dx%d", page.landscape, page.imageType, config.Width, config.Height)
+		}
+	})
+
+	t.Run("jpeg sin cambios", func(t *testing.T) {
+		content := encodeJPEG(t, testImage(20, 40), 1, binary.BigEndian)
+		page, err := loadPageImage(writeInput(t, content), a4, 200)
+		if err != nil {
+			t.Fatalf("loadPageImage: %v", err)
+		}
+		if !bytes.Equal(page.data, content) {
+			t.Error("un JPEG derecho y con la resolución permitida debe usarse tal cual")
+		}
+	})
+}
+
+func TestConvertImagesToPDF(t *testing.T) {
+	dir := t.TempDir()
+	pages := []string{filepath.Join(dir, "001.jpg"), filepath.Join(dir, "002.png")}
+	os.WriteFile(pages[0], encodeJPEG(t, testImage(60, 80), 6, binary.LittleEndian), 0o600)
+	os.WriteFile(pages[1], encodePNG(t, testImage(80, 60)), 0o600)
+
+	result, err := ConvertImagesToPDF(context.Background(), pages, "foto.jpg")
+	if err != nil {
+		t.Fatalf("ConvertImagesToPDF: %v", err)
+	}
+	defer result.Close()
+	pdf, err := io.ReadAll(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Filename != "foto.pdf" || pdfPageCount(pdf) != 2 {
+		t.Errorf("filename=%q páginas=%d", result.Filename, pdfPageCount(pdf))
+	}
+
+	// Una sola imagen pasa por el registro de formatos
+	single, err := ConvertToPDF(context.Background(), pages[1], "escaneo.png")
+	if err != nil {
+		t.Fatalf("ConvertToPDF: %v", err)
+	}
+	defer single.Close()
+	if pdf, _ := io.ReadAll(single); pdfPageCount(pdf) != 1 {
+		t.Errorf("páginas = %d, se esperaba 1", pdfPageCount(pdf))
+	}
+
+	if _, err := ConvertImagesToPDF(context.Background(), []string{writeInput(t, []byte("no es una imagen"))}, "cv.jpg"); err == nil {
+		t.Error("se esperaba error para un archivo que no es imagen")
+	}
+}
+
+func TestSetImageOptionsValidates(t *testing.T) {
+	previous := imageOptions
+	defer func() { imageOptions = previous }()
+
+	if err := SetImageOptions(ImageOptions{MaxDPI: 10, PageSize: "A4"}); err == nil {
+		t.Error("se esperaba error para una resolución menor a 72 DPI")
+	}
+	if err := SetImageOptions(ImageOptions{MaxDPI: 150, PageSize: "A3"}); err == nil {
+		t.Error("se esperaba error para un tamaño de página no soportado")
+	}
+	if err := SetImageOptions(ImageOptions{MaxDPI: 150, PageSize: "letter"}); err != nil || imageOptions.MaxDPI != 150 {
+		t.Errorf("SetImageOptions: %v (opciones: %+v)", err, imageOptions)
+	}
+}
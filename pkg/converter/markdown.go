@@ -0,0 +1,222 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdownParser interpreta CommonMark con tablas, tachado y enlaces automáticos
+// de GitHub
+var markdownParser = goldmark.New(goldmark.WithExtensions(
+	extension.Table, extension.Strikethrough, extension.Linkify,
+)).Parser()
+
+// ParseMarkdown lee un documento Markdown y construye su modelo. El texto puede
+// venir en UTF-8, UTF-16, Windows-1252 o ISO-8859-1 (ver DetectTextEncoding).
+func ParseMarkdown(r io.ReaderAt, size int64) (*Document, error) {
+	raw, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo Markdown: %w", err)
+	}
+	source, err := decodeText(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo Markdown: %w", err)
+	}
+
+	root := markdownParser.Parse(text.NewReader(source))
+	b := &markdownBuilder{source: source}
+	b.blocks(root, -1, &b.out)
+	return &Document{Body: b.out}, nil
+}
+
+// decodeText convierte el texto a UTF-8 desde la codificación detectada (ver
+// DetectTextEncoding)
+func decodeText(raw []byte) ([]byte, error) {
+	return textEncoding(detectTextEncoding(raw, false)).NewDecoder().Bytes(raw)
+}
+
+// markdownBuilder recorre el árbol de goldmark
+type markdownBuilder struct {
+	source []byte
+	out    []Block
+}
+
+// markdownFormat es el formato heredado por los nodos en línea
+type markdownFormat struct {
+	bold, italic bool
+	link         string
+}
+
+// blocks convierte los bloques hijos de n. listLevel es el nivel de la lista que
+// los contiene (-1 fuera de listas).
+func (b *markdownBuilder) blocks(n ast.Node, listLevel int, out *[]Block) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch node := c.(type) {
+		case *ast.Heading:
+			*out = append(*out, b.paragraph(node, listLevel, node.Level))
+		case *ast.Paragraph, *ast.TextBlock:
+			*out = append(*out, b.paragraph(node, listLevel, 0))
+		case *ast.List:
+			b.list(node, listLevel+1, out)
+		case *ast.Blockquote:
+			b.blocks(node, listLevel, out)
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			*out = append(*out, b.codeBlock(node, listLevel))
+		case *extast.Table:
+			*out = append(*out, b.table(node))
+		case *ast.ThematicBreak, *ast.HTMLBlock:
+			// Sin equivalente en el modelo; el HTML incrustado no se interpreta
+		default:
+			b.blocks(node, listLevel, out)
+		}
+	}
+}
+
+// list convierte una lista. El primer bloque de cada elemento lleva la viñeta o el
+// número; los siguientes quedan con la misma sangría.
+func (b *markdownBuilder) list(list *ast.List, level int, out *[]Block) {
+	number := list.Start
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := &ListItem{Level: level, Ordered: list.IsOrdered(), Marker: "•"}
+		if list.IsOrdered() {
+			marker.Marker = fmt.Sprintf("%d%c", number, list.Marker)
+			number++
+		}
+
+		var blocks []Block
+		b.blocks(item, level, &blocks)
+		first := true
+		for _, block := range blocks {
+			para, ok := block.(*Paragraph)
+			if !ok || (para.List != nil && para.List.Level > level) {
+				// Tablas y elementos de listas anidadas
+				continue
+			}
+			if first {
+				para.List = marker
+				first = false
+			} else {
+				para.List = &ListItem{Level: level, Ordered: marker.Ordered}
+			}
+		}
+		if first {
+			// Elemento vacío: se conserva la viñeta
+			blocks = append([]Block{&Paragraph{List: marker}}, blocks...)
+		}
+		*out = append(*out, blocks...)
+	}
+}
+
+// paragraph convierte un párrafo o título con su contenido en línea
+func (b *markdownBuilder) paragraph(n ast.Node, listLevel, heading int) *Paragraph {
+	para := &Paragraph{HeadingLevel: heading}
+	if listLevel >= 0 {
+		para.List = &ListItem{Level: listLevel}
+	}
+	b.inline(n, markdownFormat{}, para)
+	trimParagraph(para)
+	return para
+}
+
+// codeBlock convierte un bloque de código conservando sus líneas
+func (b *markdownBuilder) codeBlock(n ast.Node, listLevel int) *Paragraph {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		sb.Write(segment.Value(b.source))
+	}
+	para := &Paragraph{Runs: []Run{{Text: strings.TrimRight(sb.String(), "\n")}}}
+	if listLevel >= 0 {
+		para.List = &ListItem{Level: listLevel}
+	}
+	return para
+}
+
+// table convierte una tabla; la fila de encabezado va en negrita
+func (b *markdownBuilder) table(n *extast.Table) *Table {
+	table := &Table{}
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*extast.TableHeader)
+		var cells []TableCell
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			para := &Paragraph{}
+			b.inline(cell, markdownFormat{bold: header}, para)
+			trimParagraph(para)
+			cells = append(cells, TableCell{Blocks: []Block{para}})
+		}
+		table.Rows = append(table.Rows, TableRow{Cells: cells})
+	}
+	return table
+}
+
+// inline agrega al párrafo el texto de los nodos en línea hijos de n
+func (b *markdownBuilder) inline(n ast.Node, format markdownFormat, para *Paragraph) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch node := c.(type) {
+		case *ast.Text:
+			value := node.Value(b.source)
+			if !node.IsRaw() {
+				value = unescapeMarkdown(value)
+			}
+			b.appendText(para, format, string(value))
+			switch {
+			case node.HardLineBreak():
+				b.appendText(para, format, "\n")
+			case node.SoftLineBreak():
+				b.appendText(para, format, " ")
+			}
+		case *ast.String:
+			b.appendText(para, format, string(node.Value))
+		case *ast.Emphasis:
+			nested := format
+			if node.Level >= 2 {
+				nested.bold = true
+			} else {
+				nested.italic = true
+			}
+			b.inline(node, nested, para)
+		case *ast.Link:
+			nested := format
+			nested.link = string(node.Destination)
+			b.inline(node, nested, para)
+		case *ast.AutoLink:
+			nested := format
+			nested.link = string(node.URL(b.source))
+			b.appendText(para, nested, string(node.Label(b.source)))
+		case *ast.Image:
+			// Sin imágenes: el texto alternativo las reemplaza
+			b.inline(node, format, para)
+		case *ast.RawHTML:
+			// El HTML en línea no se interpreta
+		default:
+			// Código en línea, tachado y otros nodos: se conserva su texto
+			b.inline(node, format, para)
+		}
+	}
+}
+
+func (b *markdownBuilder) appendText(para *Paragraph, format markdownFormat, text string) {
+	if text == "" {
+		return
+	}
+	para.Runs = appendRun(para.Runs, Run{Text: text, Bold: format.bold, Italic: format.italic, Link: format.link})
+}
+
+// unescapeMarkdown resuelve los escapes con barra invertida y las entidades HTML
+// (&amp;, &#233;), como lo hace el renderizador HTML de goldmark
+func unescapeMarkdown(value []byte) []byte {
+	if bytes.IndexByte(value, '\\') < 0 && bytes.IndexByte(value, '&') < 0 {
+		return value
+	}
+	return util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+}
@@ -0,0 +1,717 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Namespaces de OpenDocument
+const (
+	odfTextNamespace  = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odfTableNamespace = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odfDrawNamespace  = "urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+	odfStyleNamespace = "urn:oasis:names:tc:opendocument:xmlns:style:1.0"
+	odfOfficeNS       = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+)
+
+// ParseODT lee un documento OpenDocument de texto (.odt) y construye su modelo.
+// content.xml y styles.xml (estilos, encabezado y pie de la página maestra) se
+// recorren en streaming con encoding/xml.
+func ParseODT(r io.ReaderAt, size int64) (*Document, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo ODT: %w", err)
+	}
+
+	p := &odtParser{
+		pkg:        newZipPackage(archive),
+		styles:     make(map[string]*odtStyle),
+		listStyles: make(map[string]map[int]odtListLevel),
+		lastLists:  make(map[string][]int),
+	}
+	if !p.pkg.has("content.xml") {
+		return nil, fmt.Errorf("error al leer archivo ODT: no contiene content.xml")
+	}
+
+	doc := &Document{}
+	// styles.xml primero: define estilos con nombre y la página maestra
+	if err := p.parsePart("styles.xml", doc); err != nil {
+		return nil, err
+	}
+	if err := p.parsePart("content.xml", doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// odtParser mantiene estilos, estilos de lista y contadores durante el recorrido
+type odtParser struct {
+	pkg        zipPackage
+	styles     map[string]*odtStyle
+	listStyles map[string]map[int]odtListLevel
+	// lastLists guarda los contadores de la última lista de cada estilo, para las
+	// listas con text:continue-numbering
+	lastLists map[string][]int
+	// masterPage indica que ya se leyó el encabezado y pie de la primera página maestra
+	masterPage bool
+}
+
+// odtStyle es un estilo de párrafo o de texto (con nombre o automático)
+type odtStyle struct {
+	Name         string
+	DisplayName  string
+	Parent       string
+	OutlineLevel int
+	Bold         *bool
+	Italic       *bool
+}
+
+// odtListLevel es un nivel de un estilo de lista (text:list-style)
+type odtListLevel struct {
+	Bullet        bool
+	Format        string // style:num-format: "1", "a", "A", "i", "I" o "" (sin número)
+	Prefix        string
+	Suffix        string
+	Start         int
+	DisplayLevels int
+}
+
+// odtContext es el estado heredado por los elementos en línea
+type odtContext struct {
+	paraStyle  string
+	spanStyles []string
+	link       string
+}
+
+// parsePart recorre styles.xml o content.xml
+func (p *odtParser) parsePart(name string, doc *Document) error {
+	rc, err := p.pkg.open(name, "ODT")
+	if err != nil || rc == nil {
+		return err
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error al leer %s del ODT: %w", name, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case isODF(start.Name, odfStyleNamespace, "style"):
+			err = p.parseStyle(d, start)
+		case isODF(start.Name, odfTextNamespace, "list-style"):
+			err = p.parseListStyle(d, start)
+		case isODF(start.Name, odfStyleNamespace, "master-page"):
+			err = p.parseMasterPage(d, doc)
+		case isODF(start.Name, odfOfficeNS, "text"):
+			var blocks []Block
+			blocks, err = p.parseBlocks(d, "", 0, nil)
+			doc.Body = append(doc.Body, blocks...)
+		}
+		// Los demás elementos (document-content, styles, body...) se recorren
+		if err != nil {
+			return fmt.Errorf("error al leer %s del ODT: %w", name, err)
+		}
+	}
+}
+
+// parseMasterPage lee el encabezado y el pie de la primera página maestra
+func (p *odtParser) parseMasterPage(d *xml.Decoder, doc *Document) error {
+	if p.masterPage {
+		return d.Skip()
+	}
+	p.masterPage = true
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			switch {
+			case isODF(t.Name, odfStyleNamespace, "header"):
+				blocks, err := p.parseBlocks(d, "", 0, nil)
+				if err != nil {
+					return err
+				}
+				doc.Header = append(doc.Header, blocks...)
+			case isODF(t.Name, odfStyleNamespace, "footer"):
+				blocks, err := p.parseBlocks(d, "", 0, nil)
+				if err != nil {
+					return err
+				}
+				doc.Footer = append(doc.Footer, blocks...)
+			default:
+				// Encabezados de páginas pares o primera página: se usa el principal
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// --- Estilos ---
+
+func (p *odtParser) parseStyle(d *xml.Decoder, start xml.StartElement) error {
+	style := &odtStyle{
+		Name:        attrValue(start, "name"),
+		DisplayName: attrValue(start, "display-name"),
+		Parent:      attrValue(start, "parent-style-name"),
+	}
+	if level, err := strconv.Atoi(attrValue(start, "default-outline-level")); err == nil {
+		style.OutlineLevel = level
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if style.Name != "" {
+				p.styles[style.Name] = style
+			}
+			return nil
+		case xml.StartElement:
+			if isODF(t.Name, odfStyleNamespace, "text-properties") {
+				if weight := attrValue(t, "font-weight"); weight != "" {
+					style.Bold = boolPtr(isBoldWeight(weight))
+				}
+				if fontStyle := attrValue(t, "font-style"); fontStyle != "" {
+					style.Italic = boolPtr(fontStyle == "italic" || fontStyle == "oblique")
+				}
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *odtParser) parseListStyle(d *xml.Decoder, start xml.StartElement) error {
+	levels := make(map[int]odtListLevel)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if name := attrValue(start, "name"); name != "" {
+				p.listStyles[name] = levels
+			}
+			return nil
+		case xml.StartElement:
+			level, _ := strconv.Atoi(attrValue(t, "level"))
+			switch {
+			case isODF(t.Name, odfTextNamespace, "list-level-style-bullet"):
+				levels[level] = odtListLevel{Bullet: true}
+			case isODF(t.Name, odfTextNamespace, "list-level-style-number"):
+				def := odtListLevel{
+					Format: attrValue(t, "num-format"),
+					Prefix: attrValue(t, "num-prefix"),
+					Suffix: attrValue(t, "num-suffix"),
+					Start:  1,
+				}
+				if start, err := strconv.Atoi(attrValue(t, "start-value")); err == nil {
+					def.Start = start
+				}
+				def.DisplayLevels, _ = strconv.Atoi(attrValue(t, "display-levels"))
+				levels[level] = def
+			}
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// headingLevel retorna el nivel de título de un párrafo por su estilo: el nivel de
+// esquema del estilo o de sus padres, o 1 para el estilo "Title"
+func (p *odtParser) headingLevel(name string) int {
+	for depth := 0; name != "" && depth < 10; depth++ {
+		style, ok := p.styles[name]
+		if !ok {
+			return 0
+		}
+		if style.OutlineLevel > 0 {
+			return style.OutlineLevel
+		}
+		if style.Name == "Title" || style.DisplayName == "Title" {
+			return 1
+		}
+		name = style.Parent
+	}
+	return 0
+}
+
+// resolveFormat determina negrita o cursiva: estilos de los spans (del más interno
+// al más externo) y luego el estilo del párrafo, cada uno con sus padres
+func (p *odtParser) resolveFormat(ctx odtContext, get func(*odtStyle) *bool) bool {
+	names := make([]string, 0, len(ctx.spanStyles)+1)
+	for i := len(ctx.spanStyles) - 1; i >= 0; i-- {
+		names = append(names, ctx.spanStyles[i])
+	}
+	names = append(names, ctx.paraStyle)
+
+	for _, name := range names {
+		for depth := 0; name != "" && depth < 10; depth++ {
+			style, ok := p.styles[name]
+			if !ok {
+				break
+			}
+			if v := get(style); v != nil {
+				return *v
+			}
+			name = style.Parent
+		}
+	}
+	return false
+}
+
+// --- Contenido ---
+
+// parseBlocks lee los bloques de un contenedor (office:text, celda, cuadro de
+// texto, encabezado...) hasta su cierre. listStyle, level y counters describen la
+// lista que contiene al contenedor, si la hay.
+func (p *odtParser) parseBlocks(d *xml.Decoder, listStyle string, level int, counters []int) ([]Block, error) {
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return blocks, nil
+		case xml.StartElement:
+			var parsed []Block
+			switch {
+			case isODF(t.Name, odfTextNamespace, "p"), isODF(t.Name, odfTextNamespace, "h"):
+				parsed, err = p.parseParagraph(d, t)
+			case isODF(t.Name, odfTextNamespace, "list"):
+				parsed, err = p.parseList(d, t, listStyle, level, counters)
+			case isODF(t.Name, odfTableNamespace, "table"):
+				var table *Table
+				table, err = p.parseTable(d)
+				if table != nil {
+					parsed = []Block{table}
+				}
+			case isODF(t.Name, odfDrawNamespace, "frame"):
+				parsed, err = p.findTextBoxes(d)
+			case isODF(t.Name, odfTextNamespace, "section"), isODF(t.Name, odfTextNamespace, "index-body"),
+				isODF(t.Name, odfTextNamespace, "table-of-content"):
+				parsed, err = p.parseBlocks(d, listStyle, level, counters)
+			default:
+				// Declaraciones, formularios, cambios registrados, fuente del índice...
+				err = d.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, parsed...)
+		}
+	}
+}
+
+// parseParagraph lee un text:p o text:h. Los cuadros de texto anclados al párrafo se
+// retornan después del párrafo.
+func (p *odtParser) parseParagraph(d *xml.Decoder, start xml.StartElement) ([]Block, error) {
+	ctx := odtContext{paraStyle: attrValue(start, "style-name")}
+	para := &Paragraph{HeadingLevel: p.headingLevel(ctx.paraStyle)}
+	if isODF(start.Name, odfTextNamespace, "h") {
+		para.HeadingLevel = 1
+		if level, err := strconv.Atoi(attrValue(start, "outline-level")); err == nil && level > 0 {
+			para.HeadingLevel = level
+		}
+	}
+
+	var extra []Block
+	if err := p.parseInline(d, ctx, para, &extra); err != nil {
+		return nil, err
+	}
+	trimParagraph(para)
+	return append([]Block{para}, extra...), nil
+}
+
+// parseInline procesa el contenido en línea hasta el cierre del elemento actual
+func (p *odtParser) parseInline(d *xml.Decoder, ctx odtContext, para *Paragraph, extra *[]Block) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.CharData:
+			p.appendText(para, ctx, collapseWhitespace(string(t)))
+		case xml.StartElement:
+			switch {
+			case isODF(t.Name, odfTextNamespace, "span"):
+				nested := ctx
+				nested.spanStyles = append(append([]string(nil), ctx.spanStyles...), attrValue(t, "style-name"))
+				err = p.parseInline(d, nested, para, extra)
+			case isODF(t.Name, odfTextNamespace, "a"):
+				nested := ctx
+				nested.link = attrValue(t, "href")
+				err = p.parseInline(d, nested, para, extra)
+			case isODF(t.Name, odfTextNamespace, "s"):
+				count, convErr := strconv.Atoi(attrValue(t, "c"))
+				if convErr != nil || count < 1 {
+					count = 1
+				}
+				p.appendText(para, ctx, strings.Repeat(" ", min(count, 100)))
+				err = d.Skip()
+			case isODF(t.Name, odfTextNamespace, "tab"):
+				p.appendText(para, ctx, "\t")
+				err = d.Skip()
+			case isODF(t.Name, odfTextNamespace, "line-break"):
+				p.appendText(para, ctx, "\n")
+				err = d.Skip()
+			case isODF(t.Name, odfDrawNamespace, "frame"):
+				var boxes []Block
+				boxes, err = p.findTextBoxes(d)
+				*extra = append(*extra, boxes...)
+			case isODF(t.Name, odfTextNamespace, "note"), isODF(t.Name, odfOfficeNS, "annotation"),
+				isODF(t.Name, odfTextNamespace, "tracked-changes"), isODF(t.Name, odfTextNamespace, "ruby-text"):
+				// Notas, comentarios y cambios eliminados no forman parte del texto
+				err = d.Skip()
+			default:
+				// Campos (número de página, fecha...), marcadores y metadatos: su texto cuenta
+				err = p.parseInline(d, ctx, para, extra)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// appendText agrega texto al párrafo con el formato del contexto
+func (p *odtParser) appendText(para *Paragraph, ctx odtContext, text string) {
+	if text == "" {
+		return
+	}
+	// Los espacios entre elementos se colapsan como en el texto
+	if text == " " || strings.HasPrefix(text, " ") {
+		if n := len(para.Runs); n == 0 || strings.HasSuffix(para.Runs[n-1].Text, " ") {
+			text = strings.TrimPrefix(text, " ")
+			if text == "" {
+				return
+			}
+		}
+	}
+	para.Runs = appendRun(para.Runs, Run{
+		Text:   text,
+		Bold:   p.resolveFormat(ctx, func(s *odtStyle) *bool { return s.Bold }),
+		Italic: p.resolveFormat(ctx, func(s *odtStyle) *bool { return s.Italic }),
+		Link:   ctx.link,
+	})
+}
+
+// parseList lee un text:list. Los contadores se comparten entre los niveles de una
+// misma lista; una lista anidada continúa los de su lista padre.
+func (p *odtParser) parseList(d *xml.Decoder, start xml.StartElement, parentStyle string, parentLevel int, parentCounters []int) ([]Block, error) {
+	style := parentStyle
+	if name := attrValue(start, "style-name"); name != "" {
+		style = name
+	}
+	level := parentLevel + 1
+
+	counters := parentCounters
+	if counters == nil {
+		counters = make([]int, 10)
+		if attrValue(start, "continue-numbering") == "true" {
+			if last, ok := p.lastLists[style]; ok {
+				copy(counters, last)
+			}
+		}
+		defer func() { p.lastLists[style] = counters }()
+	}
+	if parentCounters != nil && level < len(counters) {
+		// Una lista anidada nueva reinicia la numeración de su nivel
+		counters[level] = 0
+	}
+
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return blocks, nil
+		case xml.StartElement:
+			var item []Block
+			switch {
+			case isODF(t.Name, odfTextNamespace, "list-item"):
+				var marker *ListItem
+				marker = p.nextListItem(style, level, counters, attrValue(t, "start-value"))
+				item, err = p.parseListItem(d, style, level, counters, marker)
+			case isODF(t.Name, odfTextNamespace, "list-header"):
+				// Encabezado de lista: sin viñeta ni número
+				item, err = p.parseListItem(d, style, level, counters, &ListItem{Level: level - 1})
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, item...)
+		}
+	}
+}
+
+// parseListItem lee un elemento de lista. El primer párrafo lleva la viñeta; los
+// siguientes quedan con la misma sangría y sin viñeta.
+func (p *odtParser) parseListItem(d *xml.Decoder, style string, level int, counters []int, marker *ListItem) ([]Block, error) {
+	blocks, err := p.parseBlocks(d, style, level, counters)
+	if err != nil {
+		return nil, err
+	}
+	first := true
+	for _, block := range blocks {
+		para, ok := block.(*Paragraph)
+		if !ok || para.List != nil {
+			// Tablas y elementos de listas anidadas conservan su formato
+			continue
+		}
+		if first {
+			para.List = marker
+			first = false
+		} else {
+			para.List = &ListItem{Level: level - 1, Ordered: marker.Ordered}
+		}
+	}
+	return blocks, nil
+}
+
+// nextListItem avanza el contador del nivel y formatea la viñeta o el número
+func (p *odtParser) nextListItem(style string, level int, counters []int, startValue string) *ListItem {
+	def, ok := p.listStyles[style][level]
+	if !ok {
+		def = odtListLevel{Bullet: true}
+	}
+	item := &ListItem{Level: level - 1}
+	if level >= len(counters) {
+		item.Marker = "•"
+		return item
+	}
+
+	if start, err := strconv.Atoi(startValue); err == nil {
+		counters[level] = start
+	} else if counters[level] == 0 {
+		counters[level] = max(def.Start, 1)
+	} else {
+		counters[level]++
+	}
+	for i := level + 1; i < len(counters); i++ {
+		counters[i] = 0
+	}
+
+	if def.Bullet {
+		item.Marker = "•"
+		return item
+	}
+	if def.Format == "" {
+		// Lista numerada sin número visible
+		return item
+	}
+
+	item.Ordered = true
+	var numbers []string
+	for l := max(1, level-max(def.DisplayLevels, 1)+1); l <= level; l++ {
+		format := def.Format
+		if parentDef, ok := p.listStyles[style][l]; ok && l != level && parentDef.Format != "" {
+			format = parentDef.Format
+		}
+		numbers = append(numbers, formatNumberStyle(max(counters[l], 1), format))
+	}
+	item.Marker = def.Prefix + strings.Join(numbers, ".") + def.Suffix
+	return item
+}
+
+// parseTable lee un table:table; las filas pueden estar agrupadas (encabezado, grupos)
+func (p *odtParser) parseTable(d *xml.Decoder) (*Table, error) {
+	rows, err := p.parseRows(d)
+	if err != nil {
+		return nil, err
+	}
+	return &Table{Rows: rows}, nil
+}
+
+func (p *odtParser) parseRows(d *xml.Decoder) ([]TableRow, error) {
+	var rows []TableRow
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return rows, nil
+		case xml.StartElement:
+			switch {
+			case isODF(t.Name, odfTableNamespace, "table-row"):
+				cells, err := p.parseCells(d)
+				if err != nil {
+					return nil, err
+				}
+				rows = append(rows, TableRow{Cells: cells})
+			case isODF(t.Name, odfTableNamespace, "table-header-rows"), isODF(t.Name, odfTableNamespace, "table-rows"),
+				isODF(t.Name, odfTableNamespace, "table-row-group"):
+				nested, err := p.parseRows(d)
+				if err != nil {
+					return nil, err
+				}
+				rows = append(rows, nested...)
+			default:
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+func (p *odtParser) parseCells(d *xml.Decoder) ([]TableCell, error) {
+	var cells []TableCell
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return cells, nil
+		case xml.StartElement:
+			if isODF(t.Name, odfTableNamespace, "table-cell") {
+				blocks, err := p.parseBlocks(d, "", 0, nil)
+				if err != nil {
+					return nil, err
+				}
+				cells = append(cells, TableCell{Blocks: blocks})
+				continue
+			}
+			// Celdas cubiertas por una combinada
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// findTextBoxes busca draw:text-box dentro de un marco (draw:frame)
+func (p *odtParser) findTextBoxes(d *xml.Decoder) ([]Block, error) {
+	var blocks []Block
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return blocks, nil
+		case xml.StartElement:
+			var found []Block
+			if isODF(t.Name, odfDrawNamespace, "text-box") {
+				found, err = p.parseBlocks(d, "", 0, nil)
+			} else {
+				found, err = p.findTextBoxes(d)
+			}
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, found...)
+		}
+	}
+}
+
+// --- Funciones auxiliares ---
+
+func isODF(name xml.Name, namespace, local string) bool {
+	return name.Local == local && name.Space == namespace
+}
+
+// isBoldWeight interpreta fo:font-weight ("bold" o un peso numérico de 600 o más)
+func isBoldWeight(weight string) bool {
+	if weight == "bold" {
+		return true
+	}
+	n, err := strconv.Atoi(weight)
+	return err == nil && n >= 600
+}
+
+// collapseWhitespace colapsa cada secuencia de espacios, tabulaciones y saltos de
+// línea en un solo espacio, como en OpenDocument y HTML
+func collapseWhitespace(text string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range text {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// trimParagraph quita los espacios al inicio y al final del párrafo
+func trimParagraph(para *Paragraph) {
+	for len(para.Runs) > 0 {
+		para.Runs[0].Text = strings.TrimLeft(para.Runs[0].Text, " ")
+		if para.Runs[0].Text != "" {
+			break
+		}
+		para.Runs = para.Runs[1:]
+	}
+	for n := len(para.Runs); n > 0; n = len(para.Runs) {
+		para.Runs[n-1].Text = strings.TrimRight(para.Runs[n-1].Text, " ")
+		if para.Runs[n-1].Text != "" {
+			break
+		}
+		para.Runs = para.Runs[:n-1]
+	}
+}
+
+// formatNumberStyle formatea un número según style:num-format de ODF o el
+// atributo type de <ol>, que usan los mismos valores ("1", "a", "A", "i", "I")
+func formatNumberStyle(n int, format string) string {
+	switch format {
+	case "a":
+		return formatListNumber(n, "lowerLetter")
+	case "A":
+		return formatListNumber(n, "upperLetter")
+	case "i":
+		return formatListNumber(n, "lowerRoman")
+	case "I":
+		return formatListNumber(n, "upperRoman")
+	default:
+		return strconv.Itoa(n)
+	}
+}
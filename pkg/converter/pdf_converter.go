@@ -1,203 +1,205 @@
 package converter
 
 import (
-	"bytes"
+	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"time"
 
 	"github.com/jung-kurt/gofpdf"
-	"github.com/nguyenthenguyen/docx"
+	"golang.org/x/text/transform"
 )
 
-// ConvertToPDF convierte archivos .txt, .doc, .docx a PDF.
-// Si el archivo ya es PDF, lo retorna sin modificaciones.
-// Retorna los bytes del PDF y el nombre del archivo resultante.
-func ConvertToPDF(fileHeader *multipart.FileHeader) ([]byte, string, error) {
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-
-	// Si ya es PDF, solo leer y retornar
-	if ext == ".pdf" {
-		pdfBytes, err := readFileToBytes(fileHeader)
-		if err != nil {
-			return nil, "", fmt.Errorf("error al leer archivo PDF: %w", err)
+// maxTextLineLength es el largo máximo de una línea en archivos .txt
+const maxTextLineLength = 1024 * 1024
+
+// PDFFile es el PDF resultante de la conversión, listo para leerse como stream.
+// Size se conoce de antemano para enviarlo como Content-Length. Close libera el
+// archivo original o el temporal de la conversión.
+type PDFFile struct {
+	Filename string
+	Size     int64
+
+	file    *os.File
+	cleanup func() error
+}
+
+// Read lee el contenido del PDF
+func (f *PDFFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+// ReadAt lee el contenido del PDF desde off; permite releerlo para reintentar una subida
+func (f *PDFFile) ReadAt(p []byte, off int64) (int, error) {
+	return f.file.ReadAt(p, off)
+}
+
+// Seek mueve la posición de lectura del PDF
+func (f *PDFFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// Close cierra el PDF y elimina el archivo temporal si lo hubo
+func (f *PDFFile) Close() error {
+	err := f.file.Close()
+	if f.cleanup != nil {
+		if cleanupErr := f.cleanup(); err == nil {
+			err = cleanupErr
 		}
-		return pdfBytes, fileHeader.Filename, nil
 	}
+	return err
+}
 
-	// Convertir según el formato
-	switch ext {
-	case ".txt":
-		return convertTextToPDF(fileHeader)
-	case ".docx":
-		return convertDocxToPDF(fileHeader)
-	case ".doc":
-		// Formato .doc (antiguo) no soportado sin LibreOffice
-		return nil, "", fmt.Errorf("formato .doc no soportado. Por favor, use .docx, .txt o .pdf")
-	default:
-		return nil, "", fmt.Errorf("formato de archivo no soportado: %s", ext)
+// ConvertToPDF convierte el archivo en inputPath a PDF. filename es el nombre original:
+// su extensión, junto con el tipo detectado en el contenido, elige el formato del
+// registro (ver LookupFormat) y define el nombre del PDF resultante.
+// Si el archivo ya es PDF, se lee directamente sin copiarlo.
+// Los PDFs generados se escriben en un archivo temporal, así el contenido nunca se
+// guarda completo en memoria fuera del generador. El llamador debe cerrar el PDFFile.
+// Si ctx se cancela, la conversión se interrumpe y se retorna ctx.Err().
+func ConvertToPDF(ctx context.Context, inputPath, filename string) (*PDFFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-}
+	ext := strings.ToLower(filepath.Ext(filename))
 
-// convertTextToPDF convierte un archivo de texto plano a PDF usando gofpdf
-func convertTextToPDF(fileHeader *multipart.FileHeader) ([]byte, string, error) {
-	// Leer contenido del archivo
-	file, err := fileHeader.Open()
+	file, err := os.Open(inputPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("error al abrir archivo de texto: %w", err)
+		return nil, fmt.Errorf("error al abrir archivo: %w", err)
 	}
-	defer file.Close()
-
-	content, err := io.ReadAll(file)
+	info, err := file.Stat()
 	if err != nil {
-		return nil, "", fmt.Errorf("error al leer contenido del texto: %w", err)
+		file.Close()
+		return nil, fmt.Errorf("error al leer archivo: %w", err)
 	}
 
-	// Crear PDF
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "", 12)
+	// No confiar en la extensión: el contenido debe corresponder al formato declarado
+	mimeType := DetectMIMEType(file, info.Size())
+	format, ok := LookupFormat(ext, mimeType)
+	if !ok {
+		file.Close()
+		return nil, fmt.Errorf("el contenido del archivo (%s) no corresponde a la extensión %s", mimeType, ext)
+	}
 
-	// Escribir contenido línea por línea
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		pdf.MultiCell(0, 10, line, "", "", false)
+	// Si ya es PDF, pasarlo tal cual
+	if format.passthrough {
+		return &PDFFile{Filename: filename, Size: info.Size(), file: file}, nil
 	}
 
-	// Generar bytes del PDF
-	var buf bytes.Buffer
-	err = pdf.Output(&buf)
+	// Convertir según el formato
+	var pdf *gofpdf.Fpdf
+	if format.convert != nil {
+		file.Close()
+		pdf, err = format.convert(ctx, inputPath)
+	} else {
+		pdf, err = parseAndRender(ctx, format, file, info.Size())
+		file.Close()
+	}
 	if err != nil {
-		return nil, "", fmt.Errorf("error al generar PDF desde texto: %w", err)
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Generar nuevo nombre de archivo
-	newFilename := strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename)) + ".pdf"
+	newFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".pdf"
 
-	return buf.Bytes(), newFilename, nil
+	return writeTempPDF(pdf, newFilename)
 }
 
-// convertDocxToPDF convierte archivos .docx a PDF usando la librería docx para extraer texto
-// y gofpdf para generar el PDF. No requiere LibreOffice.
-func convertDocxToPDF(fileHeader *multipart.FileHeader) ([]byte, string, error) {
-	// Crear archivo temporal para leer con la librería docx
-	tempDir, err := os.MkdirTemp("", "docx-to-pdf-*")
+// parseAndRender construye el modelo del documento con el parser del formato y lo
+// renderiza a PDF
+func parseAndRender(ctx context.Context, format *Format, r io.ReaderAt, size int64) (*gofpdf.Fpdf, error) {
+	doc, err := format.Parse(r, size)
 	if err != nil {
-		return nil, "", fmt.Errorf("error al crear directorio temporal: %w", err)
+		return nil, err
 	}
-	defer os.RemoveAll(tempDir)
-
-	// Guardar archivo temporal
-	inputPath := filepath.Join(tempDir, fileHeader.Filename)
-	if err := saveMultipartFile(fileHeader, inputPath); err != nil {
-		return nil, "", fmt.Errorf("error al guardar archivo temporal: %w", err)
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	return renderDocument(doc), nil
+}
 
-	// Leer el archivo .docx
-	doc, err := docx.ReadDocxFile(inputPath)
+// convertTextToPDF convierte un archivo de texto plano a PDF usando gofpdf.
+// El texto se lee línea por línea, sin cargar el archivo completo, y se decodifica
+// a UTF-8 desde la codificación detectada (ver DetectTextEncoding). Se aceptan
+// finales de línea de Windows, Unix y Mac clásico. ctx se revisa en cada línea.
+func convertTextToPDF(ctx context.Context, inputPath string) (*gofpdf.Fpdf, error) {
+	file, err := os.Open(inputPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("error al leer archivo DOCX: %w", err)
+		return nil, fmt.Errorf("error al abrir archivo de texto: %w", err)
 	}
-	defer doc.Close()
-
-	// Extraer contenido y limpiar XML
-	xmlContent := doc.Editable().GetContent()
-	text := cleanXMLContent(xmlContent)
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo de texto: %w", err)
+	}
+	decoder := textEncoding(DetectTextEncoding(file, info.Size())).NewDecoder()
 
-	// Crear PDF con el contenido extraído
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	// Crear PDF con la fuente Unicode
+	pdf := newPDF(false)
 	pdf.AddPage()
-	pdf.SetFont("Arial", "", 12)
+	pdf.SetFont(pdfFontFamily, "", 12)
 
 	// Escribir contenido línea por línea
-	lines := strings.Split(text, "\n")
-	for _, line := range lines {
-		// Manejar líneas vacías
-		if strings.TrimSpace(line) == "" {
-			pdf.Ln(5) // Salto de línea
-			continue
+	scanner := bufio.NewScanner(transform.NewReader(file, decoder))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTextLineLength)
+	scanner.Split(scanTextLines)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		pdf.MultiCell(0, 10, line, "", "", false)
+		line := normalizeTextLine(scanner.Text())
+		pdf.MultiCell(0, 10, pdfText(expandTabs(line), false), "", "", false)
 	}
-
-	// Generar bytes del PDF
-	var buf bytes.Buffer
-	err = pdf.Output(&buf)
-	if err != nil {
-		return nil, "", fmt.Errorf("error al generar PDF desde DOCX: %w", err)
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer contenido del texto: %w", err)
 	}
 
-	// Generar nuevo nombre de archivo
-	newFilename := strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename)) + ".pdf"
-
-	return buf.Bytes(), newFilename, nil
+	return pdf, nil
 }
 
 // --- Funciones auxiliares ---
 
-// readFileToBytes lee un multipart.FileHeader y retorna sus bytes
-func readFileToBytes(fileHeader *multipart.FileHeader) ([]byte, error) {
-	file, err := fileHeader.Open()
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
+// reproducibleDate es la fecha de creación y modificación de los PDF generados
+var reproducibleDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
 
-	return io.ReadAll(file)
-}
+// writeTempPDF escribe el PDF generado en un archivo temporal y lo deja abierto para
+// leerlo desde el inicio. El archivo se elimina al cerrar el PDFFile.
+//
+// El PDF es reproducible: el mismo archivo de entrada genera siempre los mismos bytes
+// (fechas fijas y recursos en orden), así su hash identifica subidas repetidas.
+func writeTempPDF(pdf *gofpdf.Fpdf, filename string) (*PDFFile, error) {
+	pdf.SetCatalogSort(true)
+	pdf.SetCreationDate(reproducibleDate)
+	pdf.SetModificationDate(reproducibleDate)
 
-// saveMultipartFile guarda un multipart.FileHeader en una ruta del sistema de archivos
-func saveMultipartFile(fileHeader *multipart.FileHeader, destPath string) error {
-	src, err := fileHeader.Open()
+	tempFile, err := os.CreateTemp("", "converted-*.pdf")
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("error al crear archivo temporal: %w", err)
 	}
-	defer src.Close()
+	remove := func() error { return os.Remove(tempFile.Name()) }
 
-	dst, err := os.Create(destPath)
-	if err != nil {
-		return err
+	if err := pdf.Output(tempFile); err != nil {
+		tempFile.Close()
+		remove()
+		return nil, fmt.Errorf("error al generar PDF: %w", err)
 	}
-	defer dst.Close()
-
-	_, err = io.Copy(dst, src)
-	return err
-}
-
-// cleanXMLContent extrae solo el texto de un contenido XML de DOCX
-func cleanXMLContent(xmlContent string) string {
-	// Dividir por párrafos <w:p>...</w:p>
-	paragraphRe := regexp.MustCompile(`<w:p[^>]*>(.*?)</w:p>`)
-	paragraphs := paragraphRe.FindAllStringSubmatch(xmlContent, -1)
-
-	var textBuilder strings.Builder
-	textRe := regexp.MustCompile(`<w:t[^>]*>([^<]*)</w:t>`)
-
-	for _, para := range paragraphs {
-		if len(para) < 2 {
-			continue
-		}
-
-		// Extraer texto del párrafo
-		textMatches := textRe.FindAllStringSubmatch(para[1], -1)
-		if len(textMatches) == 0 {
-			continue
-		}
 
-		// Concatenar texto del párrafo
-		for _, match := range textMatches {
-			if len(match) > 1 {
-				textBuilder.WriteString(match[1])
-			}
-		}
-
-		// Agregar salto de línea después de cada párrafo
-		textBuilder.WriteString("\n")
+	size, err := tempFile.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = tempFile.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tempFile.Close()
+		remove()
+		return nil, fmt.Errorf("error al preparar PDF generado: %w", err)
 	}
 
-	return strings.TrimSpace(textBuilder.String())
+	return &PDFFile{Filename: filename, Size: size, file: tempFile, cleanup: remove}, nil
 }
@@ -0,0 +1,175 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeInput guarda el contenido en un archivo temporal, como el spool de la cola de ingesta
+func writeInput(tb testing.TB, content []byte) string {
+	tb.Helper()
+
+	path := filepath.Join(tb.TempDir(), "input")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+func fakePDF(size int) []byte {
+	content := bytes.Repeat([]byte("0"), size)
+	copy(content, "%PDF-1.4\n")
+	return content
+}
+
+func TestConvertToPDFPassesPDFThrough(t *testing.T) {
+	content := fakePDF(64 * 1024)
+	pdfFile, err := ConvertToPDF(context.Background(), writeInput(t, content), "cv.pdf")
+	if err != nil {
+		t.Fatalf("ConvertToPDF: %v", err)
+	}
+	defer pdfFile.Close()
+
+	if pdfFile.Filename != "cv.pdf" || pdfFile.Size != int64(len(content)) {
+		t.Errorf("got filename=%q size=%d", pdfFile.Filename, pdfFile.Size)
+	}
+	got, err := io.ReadAll(pdfFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Error("el PDF debe pasar sin modificaciones")
+	}
+}
+
+func TestConvertToPDFWritesTextToTempFile(t *testing.T) {
+	content := []byte("Juan Pérez\r\nDesarrollador Go\n\nExperiencia: 5 años\n")
+	pdfFile, err := ConvertToPDF(context.Background(), writeInput(t, content), "cv.txt")
+	if err != nil {
+		t.Fatalf("ConvertToPDF: %v", err)
+	}
+
+	if pdfFile.Filename != "cv.pdf" {
+		t.Errorf("filename = %q, se esperaba cv.pdf", pdfFile.Filename)
+	}
+	got, err := io.ReadAll(pdfFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(got)) != pdfFile.Size || !bytes.HasPrefix(got, []byte("%PDF-")) {
+		t.Errorf("PDF inválido: size=%d leídos=%d", pdfFile.Size, len(got))
+	}
+
+	tempPath := pdfFile.file.Name()
+	if err := pdfFile.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(tempPath); !os.IsNotExist(err) {
+		t.Errorf("el archivo temporal %s debe eliminarse al cerrar", tempPath)
+	}
+}
+
+func TestConvertToPDFIsReproducible(t *testing.T) {
+	inputPath := writeInput(t, []byte("# Juan Pérez\n\nDesarrollador **Go** con 5 años de experiencia.\n"))
+
+	var outputs [][]byte
+	for i := 0; i < 2; i++ {
+		pdfFile, err := ConvertToPDF(context.Background(), inputPath, "cv.md")
+		if err != nil {
+			t.Fatalf("ConvertToPDF: %v", err)
+		}
+		got, err := io.ReadAll(pdfFile)
+		pdfFile.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, got)
+	}
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Error("el mismo archivo debe generar el mismo PDF")
+	}
+}
+
+func TestConvertToPDFPassthroughMemoryIsBounded(t *testing.T) {
+	const size = 10 * 1024 * 1024
+	inputPath := writeInput(t, fakePDF(size))
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	pdfFile, err := ConvertToPDF(context.Background(), inputPath, "cv.pdf")
+	if err != nil {
+		t.Fatalf("ConvertToPDF: %v", err)
+	}
+	if _, err := io.Copy(io.Discard, pdfFile); err != nil {
+		t.Fatal(err)
+	}
+	pdfFile.Close()
+
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > size/10 {
+		t.Errorf("se asignaron %d bytes para un PDF de %d bytes; el contenido no debe cargarse en memoria", allocated, size)
+	}
+}
+
+// Los benchmarks miden la memoria por solicitud (B/op) del camino de subida
+func BenchmarkConvertToPDFPassthrough(b *testing.B) {
+	const size = 10 * 1024 * 1024
+	inputPath := writeInput(b, fakePDF(size))
+
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pdfFile, err := ConvertToPDF(context.Background(), inputPath, "cv.pdf")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, pdfFile); err != nil {
+			b.Fatal(err)
+		}
+		pdfFile.Close()
+	}
+}
+
+func BenchmarkConvertToPDFText(b *testing.B) {
+	content := []byte(strings.Repeat("Desarrollador backend con experiencia en Go y PostgreSQL.\n", 2000))
+	inputPath := writeInput(b, content)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pdfFile, err := ConvertToPDF(context.Background(), inputPath, "cv.txt")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, pdfFile); err != nil {
+			b.Fatal(err)
+		}
+		pdfFile.Close()
+	}
+}
+
+func TestConvertStopsWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ConvertToPDF(ctx, writeInput(t, []byte("Juan Pérez\n")), "cv.txt"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ConvertToPDF: err = %v, se esperaba context.Canceled", err)
+	}
+	// Los conversores revisan ctx durante la conversión, no solo al empezar
+	if _, err := convertTextToPDF(ctx, writeInput(t, []byte("Juan Pérez\n"))); !errors.Is(err, context.Canceled) {
+		t.Errorf("convertTextToPDF: err = %v, se esperaba context.Canceled", err)
+	}
+	if _, err := renderImages(ctx, []string{writeInput(t, encodePNG(t, testImage(80, 60)))}); !errors.Is(err, context.Canceled) {
+		t.Errorf("renderImages: err = %v, se esperaba context.Canceled", err)
+	}
+}
@@ -0,0 +1,359 @@
+package converter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Medidas del documento generado (mm y puntos)
+const (
+	pdfMargin        = 15.0
+	pdfBodyFontSize  = 11.0
+	pdfSmallFontSize = 9.0
+	pdfListIndent    = 6.0
+	pdfCellPadding   = 2.0
+	maxWriteChunk    = 256
+	pdfLinkColorR    = 5
+	pdfLinkColorG    = 99
+	pdfLinkColorB    = 193
+)
+
+// headingFontSizes define el tamaño de fuente por nivel de título (1, 2 y 3 o más)
+var headingFontSizes = []float64{16, 14, 12}
+
+// pdfWriter renderiza el modelo de documento con gofpdf
+type pdfWriter struct {
+	pdf *gofpdf.Fpdf
+	// baseSize es el tamaño de fuente del texto normal (menor en encabezados y pies)
+	baseSize float64
+}
+
+// renderDocument genera el PDF del documento: encabezado y pie en cada página,
+// títulos, listas, hipervínculos y tablas
+func renderDocument(doc *Document) *gofpdf.Fpdf {
+	pdf := newPDF(true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetFont(pdfFontFamily, "", pdfBodyFontSize)
+
+	w := &pdfWriter{
+		pdf:      pdf,
+		baseSize: pdfBodyFontSize,
+	}
+
+	if hasContent(doc.Header) {
+		pdf.SetHeaderFunc(func() {
+			w.renderPageRegion(doc.Header, pdfMargin)
+			pdf.Ln(2)
+		})
+	}
+	if hasContent(doc.Footer) {
+		footerHeight := w.withBaseSize(pdfSmallFontSize, func() float64 {
+			return w.blocksHeight(doc.Footer, w.contentWidth())
+		})
+		pdf.SetAutoPageBreak(true, pdfMargin+footerHeight+2)
+		pdf.SetFooterFunc(func() {
+			_, pageHeight := pdf.GetPageSize()
+			w.renderPageRegion(doc.Footer, pageHeight-pdfMargin-footerHeight)
+		})
+	} else {
+		pdf.SetAutoPageBreak(true, pdfMargin)
+	}
+
+	pdf.AddPage()
+	w.renderBlocks(doc.Body)
+	return pdf
+}
+
+// renderPageRegion renderiza el encabezado o el pie de página a partir de y. Se usan
+// los márgenes de la página aunque el salto haya ocurrido dentro de una tabla.
+func (w *pdfWriter) renderPageRegion(blocks []Block, y float64) {
+	left, _, right, _ := w.pdf.GetMargins()
+	autoBreak, breakMargin := w.pdf.GetAutoPageBreak()
+	w.pdf.SetAutoPageBreak(false, breakMargin)
+	w.pdf.SetLeftMargin(pdfMargin)
+	w.pdf.SetRightMargin(pdfMargin)
+	w.pdf.SetXY(pdfMargin, y)
+
+	w.withBaseSize(pdfSmallFontSize, func() float64 {
+		w.renderBlocks(blocks)
+		return 0
+	})
+
+	w.pdf.SetLeftMargin(left)
+	w.pdf.SetRightMargin(right)
+	w.pdf.SetAutoPageBreak(autoBreak, breakMargin)
+}
+
+// withBaseSize ejecuta fn con otro tamaño de texto normal
+func (w *pdfWriter) withBaseSize(size float64, fn func() float64) float64 {
+	previous := w.baseSize
+	w.baseSize = size
+	defer func() { w.baseSize = previous }()
+	return fn()
+}
+
+func (w *pdfWriter) renderBlocks(blocks []Block) {
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *Paragraph:
+			w.renderParagraph(b)
+		case *Table:
+			w.renderTable(b)
+		}
+	}
+}
+
+// fontSize retorna el tamaño de fuente de un párrafo según su nivel de título
+func (w *pdfWriter) fontSize(p *Paragraph) float64 {
+	if p.HeadingLevel == 0 {
+		return w.baseSize
+	}
+	size := headingFontSizes[min(p.HeadingLevel, len(headingFontSizes))-1]
+	// Mantener la proporción en encabezados y pies de página
+	return size * w.baseSize / pdfBodyFontSize
+}
+
+// lineHeight retorna el alto de línea para un tamaño de fuente en puntos
+func lineHeight(size float64) float64 {
+	return size * 0.5
+}
+
+// listIndent retorna la sangría de un elemento de lista
+func listIndent(item *ListItem) float64 {
+	return pdfListIndent * float64(item.Level+1)
+}
+
+func (w *pdfWriter) renderParagraph(p *Paragraph) {
+	pdf := w.pdf
+	size := w.fontSize(p)
+	lh := lineHeight(size)
+
+	if p.IsEmpty() {
+		pdf.Ln(lh / 2)
+		return
+	}
+	if p.HeadingLevel > 0 {
+		pdf.Ln(lh / 3)
+	}
+
+	left, _, _, _ := pdf.GetMargins()
+	pdf.SetX(left)
+
+	// Viñeta o número a la izquierda; el texto se alinea con la sangría
+	if p.List != nil {
+		indent := listIndent(p.List)
+		pdf.SetFont(pdfFontFamily, "", size)
+		pdf.SetX(left + indent - pdfListIndent)
+		pdf.CellFormat(pdfListIndent, lh, pdfText(p.List.Marker, false), "", 0, "L", false, 0, "")
+		pdf.SetLeftMargin(left + indent)
+		defer pdf.SetLeftMargin(left)
+	}
+
+	for _, run := range p.Runs {
+		style := w.runStyle(p, run)
+		pdf.SetFont(pdfFontFamily, style, size)
+		text := pdfText(expandTabs(run.Text), strings.Contains(style, "B"))
+		if run.Link != "" {
+			pdf.SetTextColor(pdfLinkColorR, pdfLinkColorG, pdfLinkColorB)
+		}
+		for _, chunk := range writeChunks(text) {
+			if run.Link != "" {
+				pdf.WriteLinkString(lh, chunk, run.Link)
+			} else {
+				pdf.Write(lh, chunk)
+			}
+		}
+		if run.Link != "" {
+			pdf.SetTextColor(0, 0, 0)
+		}
+	}
+	pdf.Ln(lh)
+	if p.HeadingLevel > 0 {
+		pdf.Ln(lh / 3)
+	}
+}
+
+// runStyle retorna el estilo de fuente de gofpdf ("", "B", "BU"...) de un run. La
+// fuente embebida no tiene cursiva, así que Italic no cambia el estilo.
+func (w *pdfWriter) runStyle(p *Paragraph, run Run) string {
+	style := ""
+	if run.Bold || p.HeadingLevel > 0 {
+		style += "B"
+	}
+	if run.Link != "" {
+		style += "U"
+	}
+	return style
+}
+
+// renderTable dibuja cada fila con columnas de igual ancho. Si una fila no cabe en
+// una página, sus celdas se dibujan una debajo de otra (por ejemplo, los CV con
+// diseño de dos columnas hechos con una tabla de una sola fila).
+func (w *pdfWriter) renderTable(t *Table) {
+	columns := t.ColumnCount()
+	if columns == 0 {
+		return
+	}
+	pdf := w.pdf
+	left, top, right, _ := pdf.GetMargins()
+	autoBreak, breakMargin := pdf.GetAutoPageBreak()
+	_, pageHeight := pdf.GetPageSize()
+	pageSpace := pageHeight - breakMargin - top
+
+	width := w.contentWidth()
+	columnWidth := width / float64(columns)
+
+	for _, row := range t.Rows {
+		height := 0.0
+		for _, cell := range row.Cells {
+			height = max(height, w.blocksHeight(cell.Blocks, columnWidth-2*pdfCellPadding))
+		}
+
+		if height > pageSpace {
+			for _, cell := range row.Cells {
+				w.renderBlocks(cell.Blocks)
+			}
+			continue
+		}
+		// Sin saltos automáticos (encabezado, pie o fila de otra tabla) se dibuja en el lugar
+		if autoBreak && pdf.GetY()+height > pageHeight-breakMargin {
+			pdf.AddPage()
+		}
+
+		// La fila cabe en la página: sin saltos automáticos mientras se dibujan las celdas
+		pdf.SetAutoPageBreak(false, breakMargin)
+		rowTop := pdf.GetY()
+		rowBottom := rowTop
+		for i, cell := range row.Cells {
+			cellLeft := left + float64(i)*columnWidth + pdfCellPadding
+			pdf.SetLeftMargin(cellLeft)
+			pdf.SetRightMargin(right + width - float64(i+1)*columnWidth + pdfCellPadding)
+			pdf.SetXY(cellLeft, rowTop)
+			w.renderBlocks(cell.Blocks)
+			rowBottom = max(rowBottom, pdf.GetY())
+		}
+		pdf.SetLeftMargin(left)
+		pdf.SetRightMargin(right)
+		pdf.SetAutoPageBreak(autoBreak, breakMargin)
+		pdf.SetXY(left, rowBottom)
+	}
+}
+
+// contentWidth retorna el ancho disponible entre los márgenes actuales
+func (w *pdfWriter) contentWidth() float64 {
+	pageWidth, _ := w.pdf.GetPageSize()
+	left, _, right, _ := w.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// blocksHeight estima el alto que ocuparán los bloques con el ancho dado
+func (w *pdfWriter) blocksHeight(blocks []Block, width float64) float64 {
+	height := 0.0
+	for _, block := range blocks {
+		switch b := block.(type) {
+		case *Paragraph:
+			height += w.paragraphHeight(b, width)
+		case *Table:
+			columns := b.ColumnCount()
+			if columns == 0 {
+				continue
+			}
+			columnWidth := width / float64(columns)
+			for _, row := range b.Rows {
+				rowHeight := 0.0
+				for _, cell := range row.Cells {
+					rowHeight = max(rowHeight, w.blocksHeight(cell.Blocks, columnWidth-2*pdfCellPadding))
+				}
+				height += rowHeight
+			}
+		}
+	}
+	return height
+}
+
+// paragraphHeight estima el alto de un párrafo midiendo su texto con la fuente
+// del párrafo (sin distinguir el estilo de cada run)
+func (w *pdfWriter) paragraphHeight(p *Paragraph, width float64) float64 {
+	size := w.fontSize(p)
+	lh := lineHeight(size)
+	if p.IsEmpty() {
+		return lh / 2
+	}
+	if p.List != nil {
+		width -= listIndent(p.List)
+	}
+	style := ""
+	if p.HeadingLevel > 0 {
+		style = "B"
+	}
+	w.pdf.SetFont(pdfFontFamily, style, size)
+
+	lines := 0
+	for _, segment := range strings.Split(pdfText(expandTabs(p.Text()), style == "B"), "\n") {
+		lines += w.wrappedLines(segment, width)
+	}
+	height := float64(lines) * lh
+	if p.HeadingLevel > 0 {
+		height += 2 * lh / 3
+	}
+	return height
+}
+
+// wrappedLines cuenta las líneas que ocupa el texto al cortarlo por palabras con la
+// fuente actual (SplitLines de gofpdf no mide bien el texto UTF-8)
+func (w *pdfWriter) wrappedLines(text string, width float64) int {
+	lines := 1
+	lineWidth := 0.0
+	space := w.pdf.GetStringWidth(" ")
+	for _, word := range strings.Fields(text) {
+		wordWidth := w.pdf.GetStringWidth(word)
+		switch {
+		case lineWidth == 0:
+			lineWidth = wordWidth
+		case lineWidth+space+wordWidth <= width:
+			lineWidth += space + wordWidth
+		default:
+			lines++
+			lineWidth = wordWidth
+		}
+		// Palabras más largas que la línea se cortan en varias
+		for lineWidth > width && width > 0 {
+			lines++
+			lineWidth -= width
+		}
+	}
+	return lines
+}
+
+// writeChunks divide el texto en partes de hasta maxWriteChunk bytes, cortando
+// después de un espacio. Write de gofpdf es cuadrático en el largo del texto UTF-8,
+// así que un run muy largo se escribe en varias llamadas que continúan la línea.
+func writeChunks(text string) []string {
+	var chunks []string
+	for len(text) > maxWriteChunk {
+		cut := strings.LastIndexAny(text[:maxWriteChunk], " \n") + 1
+		if cut <= 0 {
+			// Sin espacios: cortar en un límite de carácter
+			cut = maxWriteChunk
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	return append(chunks, text)
+}
+
+// expandTabs reemplaza tabulaciones por espacios; las posiciones de tabulación del
+// documento original no se conservan
+func expandTabs(text string) string {
+	return strings.ReplaceAll(text, "\t", "    ")
+}
+
+// hasContent indica si algún bloque tiene texto visible
+func hasContent(blocks []Block) bool {
+	return strings.TrimSpace(PlainText(blocks)) != ""
+}
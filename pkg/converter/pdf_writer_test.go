@@ -0,0 +1,38 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteChunksSplitsAtSpacesAndRunes(t *testing.T) {
+	text := strings.Repeat("Señora ", 1000) + strings.Repeat("ñ", 3000)
+	chunks := writeChunks(text)
+	if strings.Join(chunks, "") != text {
+		t.Fatal("las partes no reconstruyen el texto original")
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > maxWriteChunk || !utf8.ValidString(chunk) {
+			t.Errorf("parte %d inválida: %d bytes", i, len(chunk))
+		}
+		if i < len(chunks)-1 && strings.Contains(chunk, " ") && !strings.HasSuffix(chunk, " ") {
+			t.Errorf("parte %d no termina en un espacio", i)
+		}
+	}
+}
+
+func TestRenderDocumentWritesLongRuns(t *testing.T) {
+	// Un run de ~1 MB no debe tardar de forma cuadrática
+	run := Run{Text: strings.Repeat("Experiencia en sistemas distribuidos. ", 25000)}
+	doc := &Document{Body: []Block{&Paragraph{Runs: []Run{run}}}}
+
+	start := time.Now()
+	if err := renderDocument(doc).Output(&strings.Builder{}); err != nil {
+		t.Fatalf("error al generar PDF: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Errorf("renderizar un run largo tardó %v", elapsed)
+	}
+}
@@ -0,0 +1,123 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ParseFunc construye el modelo de documento a partir del contenido del archivo
+type ParseFunc func(r io.ReaderAt, size int64) (*Document, error)
+
+// Format es un formato de entrada soportado. El registro se consulta por el tipo
+// detectado en el contenido (DetectMIMEType) y la extensión declarada desambigua
+// los formatos que comparten tipo, como texto plano y Markdown.
+type Format struct {
+	Name       string   // Nombre corto (ej: "docx")
+	Extensions []string // Extensiones aceptadas, en minúsculas y con punto
+	MIMETypes  []string // Tipos detectados por contenido aceptados
+	Parse      ParseFunc
+
+	// convert reemplaza a Parse en los formatos internos que no pasan por el
+	// modelo: el texto plano se escribe línea a línea, sin cargarlo completo, y
+	// las imágenes ocupan una página cada una
+	convert func(ctx context.Context, inputPath string) (*gofpdf.Fpdf, error)
+	// passthrough indica que el archivo ya es PDF y se entrega tal cual
+	passthrough bool
+}
+
+// textMIMETypes son los tipos detectados para texto en cualquier codificación
+var textMIMETypes = []string{MIMETextUTF8, MIMETextUTF16LE, MIMETextUTF16BE, MIMEText}
+
+// formatsByMIME es el registro de formatos por tipo detectado
+var formatsByMIME = map[string][]*Format{}
+
+func init() {
+	for _, format := range []Format{
+		{Name: "pdf", Extensions: []string{".pdf"}, MIMETypes: []string{MIMEPDF}, passthrough: true},
+		{Name: "docx", Extensions: []string{".docx"}, MIMETypes: []string{MIMEDocx}, Parse: ParseDocx},
+		{Name: "doc", Extensions: []string{".doc"}, MIMETypes: []string{MIMEDoc}, Parse: ParseDoc},
+		{Name: "odt", Extensions: []string{".odt"}, MIMETypes: []string{MIMEODT}, Parse: ParseODT},
+		{Name: "jpeg", Extensions: []string{".jpg", ".jpeg"}, MIMETypes: []string{MIMEJPEG}, convert: convertImageToPDF},
+		{Name: "png", Extensions: []string{".png"}, MIMETypes: []string{MIMEPNG}, convert: convertImageToPDF},
+		{Name: "rtf", Extensions: []string{".rtf"}, MIMETypes: []string{MIMERTF}, Parse: ParseRTF},
+		{Name: "html", Extensions: []string{".html", ".htm"}, MIMETypes: append([]string{MIMEHTML}, textMIMETypes...), Parse: ParseHTML},
+		{Name: "markdown", Extensions: []string{".md", ".markdown"}, MIMETypes: append([]string{MIMEHTML}, textMIMETypes...), Parse: ParseMarkdown},
+		// Un .txt que empieza con una etiqueta HTML se sigue tratando como texto
+		{Name: "txt", Extensions: []string{".txt"}, MIMETypes: append([]string{MIMEHTML}, textMIMETypes...), convert: convertTextToPDF},
+	} {
+		if err := RegisterFormat(format); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterFormat agrega un formato al registro. Debe llamarse durante la
+// inicialización del programa; falla si alguna combinación de tipo y extensión ya
+// está registrada.
+func RegisterFormat(format Format) error {
+	if format.Name == "" || len(format.Extensions) == 0 || len(format.MIMETypes) == 0 {
+		return fmt.Errorf("formato inválido: nombre, extensiones y tipos son obligatorios")
+	}
+	if format.Parse == nil && format.convert == nil && !format.passthrough {
+		return fmt.Errorf("formato %s sin parser", format.Name)
+	}
+
+	registered := format
+	registered.Extensions = make([]string, len(format.Extensions))
+	for i, ext := range format.Extensions {
+		registered.Extensions[i] = strings.ToLower(ext)
+	}
+	for _, mimeType := range registered.MIMETypes {
+		for _, ext := range registered.Extensions {
+			if existing, ok := LookupFormat(ext, mimeType); ok {
+				return fmt.Errorf("el tipo %s con extensión %s ya está registrado por %s", mimeType, ext, existing.Name)
+			}
+		}
+	}
+	for _, mimeType := range registered.MIMETypes {
+		formatsByMIME[mimeType] = append(formatsByMIME[mimeType], &registered)
+	}
+	return nil
+}
+
+// LookupFormat retorna el formato para el tipo detectado y la extensión declarada
+func LookupFormat(ext, mimeType string) (*Format, bool) {
+	ext = strings.ToLower(ext)
+	for _, format := range formatsByMIME[mimeType] {
+		for _, candidate := range format.Extensions {
+			if candidate == ext {
+				return format, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// MatchesExtension indica si el contenido detectado corresponde a la extensión declarada
+func MatchesExtension(ext, mimeType string) bool {
+	_, ok := LookupFormat(ext, mimeType)
+	return ok
+}
+
+// SupportedExtensions retorna las extensiones registradas, ordenadas
+func SupportedExtensions() []string {
+	seen := map[string]bool{}
+	var extensions []string
+	for _, formats := range formatsByMIME {
+		for _, format := range formats {
+			for _, ext := range format.Extensions {
+				if !seen[ext] {
+					seen[ext] = true
+					extensions = append(extensions, ext)
+				}
+			}
+		}
+	}
+	sort.Strings(extensions)
+	return extensions
+}
@@ -0,0 +1,600 @@
+package converter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// maxRTFDepth limita el anidamiento de grupos: un RTF real rara vez pasa de 20
+const maxRTFDepth = 1000
+
+// rtfCodePages son las páginas de códigos (\ansicpgN) para decodificar \'hh. Las
+// páginas multibyte (japonés, chino) no se soportan; Word escribe esos caracteres
+// con \uN de todas formas.
+var rtfCodePages = map[int]*charmap.Charmap{
+	437:   charmap.CodePage437,
+	850:   charmap.CodePage850,
+	866:   charmap.CodePage866,
+	874:   charmap.Windows874,
+	1250:  charmap.Windows1250,
+	1251:  charmap.Windows1251,
+	1252:  charmap.Windows1252,
+	1253:  charmap.Windows1253,
+	1254:  charmap.Windows1254,
+	1255:  charmap.Windows1255,
+	1256:  charmap.Windows1256,
+	1257:  charmap.Windows1257,
+	1258:  charmap.Windows1258,
+	10000: charmap.Macintosh,
+}
+
+// rtfSymbols son las palabras de control que equivalen a un carácter
+var rtfSymbols = map[string]string{
+	"tab":       "\t",
+	"line":      "\n",
+	"emdash":    "—",
+	"endash":    "–",
+	"emspace":   " ",
+	"enspace":   " ",
+	"qmspace":   " ",
+	"bullet":    "•",
+	"lquote":    "‘",
+	"rquote":    "’",
+	"ldblquote": "“",
+	"rdblquote": "”",
+}
+
+// rtfSkippedDestinations son los grupos sin texto del documento (tablas de
+// fuentes, estilos, metadatos, imágenes...)
+var rtfSkippedDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true, "pict": true,
+	"object": true, "themedata": true, "colorschememapping": true, "latentstyles": true,
+	"datastore": true, "listtable": true, "listoverridetable": true, "rsidtbl": true,
+	"generator": true, "xmlnstbl": true, "filetbl": true, "revtbl": true, "pgdsctbl": true,
+	"nonshppict": true, "shppict": true, "sp": true, "footnote": true, "annotation": true,
+	"headerl": true, "headerf": true, "footerl": true, "footerf": true,
+}
+
+// hyperlinkRe extrae la URL de una instrucción de campo HYPERLINK
+var hyperlinkRe = regexp.MustCompile(`(?i)HYPERLINK\s+"([^"]*)"`)
+
+// Destinos de texto de un grupo
+const (
+	rtfDestText      = iota // Texto del documento (cuerpo, encabezado o pie)
+	rtfDestSkip             // Grupo ignorado
+	rtfDestListText         // Viñeta o número de un elemento de lista (\listtext, \pntext)
+	rtfDestFieldInst        // Instrucción de un campo (\fldinst)
+)
+
+// rtfState es el estado de un grupo; se restaura al cerrar el grupo
+type rtfState struct {
+	dest   int
+	out    *rtfOutput
+	bold   bool
+	italic bool
+	hidden bool
+	link   string
+	uc     int // Caracteres de reemplazo que siguen a \uN
+
+	// Propiedades de párrafo
+	inTable      bool
+	outlineLevel int // 0 si no es título; N+1 para \outlinelevelN
+	listLevel    int
+}
+
+// rtfOutput acumula los bloques de un destino (cuerpo, encabezado o pie)
+type rtfOutput struct {
+	blocks []Block
+	para   *Paragraph
+	// Tabla en construcción: filas, celdas de la fila actual y bloques de la celda
+	rows  []TableRow
+	cells []TableCell
+	cell  []Block
+}
+
+// ParseRTF lee un documento RTF y construye su modelo. El archivo se recorre en
+// streaming; se interpretan párrafos, formato de caracteres, listas, tablas,
+// hipervínculos, encabezado y pie de página.
+func ParseRTF(r io.ReaderAt, size int64) (*Document, error) {
+	p := &rtfParser{
+		r:        bufio.NewReader(io.NewSectionReader(r, 0, size)),
+		codePage: charmap.Windows1252,
+		body:     &rtfOutput{},
+	}
+	p.state = rtfState{out: p.body, uc: 1}
+
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("error al leer archivo RTF: %w", err)
+	}
+
+	doc := &Document{Body: p.body.finish()}
+	if p.header != nil {
+		doc.Header = p.header.finish()
+	}
+	if p.footer != nil {
+		doc.Footer = p.footer.finish()
+	}
+	return doc, nil
+}
+
+type rtfParser struct {
+	r        *bufio.Reader
+	codePage *charmap.Charmap
+
+	state rtfState
+	stack []rtfState
+
+	body, header, footer *rtfOutput
+
+	// text acumula los caracteres con el formato actual hasta el siguiente cambio
+	text strings.Builder
+	// skipChars son los caracteres de reemplazo pendientes después de \uN
+	skipChars int
+	// highSurrogate guarda la primera mitad de un par sustituto escrito con \uN
+	highSurrogate rune
+	// ignorable indica que el grupo empezó con \*: se ignora si el destino es desconocido
+	ignorable bool
+
+	listText  strings.Builder
+	marker    string
+	hasMarker bool
+	fieldInst strings.Builder
+}
+
+func (p *rtfParser) parse() error {
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(p.r, head); err != nil || string(head) != `{\rtf` {
+		return errors.New("no es un documento RTF")
+	}
+	// Versión de \rtfN
+	for {
+		c, err := p.r.ReadByte()
+		if err != nil {
+			return nil
+		}
+		if c < '0' || c > '9' {
+			if c != ' ' {
+				p.r.UnreadByte()
+			}
+			break
+		}
+	}
+	p.stack = append(p.stack, p.state)
+
+	for {
+		c, err := p.r.ReadByte()
+		if err == io.EOF {
+			// Documentos truncados o sin la llave final: se usa lo leído
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch c {
+		case '{':
+			p.flushText()
+			if len(p.stack) >= maxRTFDepth {
+				return errors.New("anidación de grupos excesiva")
+			}
+			p.stack = append(p.stack, p.state)
+			p.ignorable = false
+			p.skipChars = 0
+		case '}':
+			p.flushText()
+			if len(p.stack) == 0 {
+				return nil
+			}
+			p.closeGroup()
+			if len(p.stack) == 0 {
+				return nil
+			}
+		case '\\':
+			if err := p.readControl(); err != nil {
+				return err
+			}
+		case '\r', '\n':
+			// Los saltos de línea del archivo no son texto
+		default:
+			p.writeByte(c)
+		}
+	}
+}
+
+// closeGroup restaura el estado del grupo padre y cierra el destino que terminó
+func (p *rtfParser) closeGroup() {
+	closed := p.state
+	p.state = p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+	p.ignorable = false
+	p.skipChars = 0
+
+	switch closed.dest {
+	case rtfDestListText:
+		if p.state.dest != rtfDestListText {
+			p.marker = listMarker(p.listText.String())
+			p.hasMarker = true
+			p.listText.Reset()
+		}
+	case rtfDestFieldInst:
+		if p.state.dest != rtfDestFieldInst {
+			// El enlace aplica al resultado del campo (\fldrslt), grupo hermano
+			if m := hyperlinkRe.FindStringSubmatch(p.fieldInst.String()); m != nil {
+				p.state.link = m[1]
+			}
+			p.fieldInst.Reset()
+		}
+	}
+}
+
+// readControl lee lo que sigue a una barra invertida: símbolo o palabra de control
+func (p *rtfParser) readControl() error {
+	c, err := p.r.ReadByte()
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case c == '\\' || c == '{' || c == '}':
+		p.writeByte(c)
+		return nil
+	case c == '\'':
+		hex := make([]byte, 2)
+		if _, err := io.ReadFull(p.r, hex); err != nil {
+			return nil
+		}
+		if b, err := strconv.ParseUint(string(hex), 16, 8); err == nil {
+			p.writeByte(byte(b))
+		}
+		return nil
+	case c == '*':
+		p.ignorable = true
+		return nil
+	case c == '~':
+		p.writeString(" ")
+		return nil
+	case c == '_':
+		p.writeString("-")
+		return nil
+	case c == '\r' || c == '\n':
+		// Barra invertida seguida de salto de línea equivale a \par
+		p.flushText()
+		p.endParagraph()
+		return nil
+	case !isASCIILetter(c):
+		// Otros símbolos de control (\-, \|, \:) no tienen texto
+		return nil
+	}
+
+	word := []byte{c}
+	for {
+		c, err = p.r.ReadByte()
+		if err != nil {
+			break
+		}
+		if !isASCIILetter(c) || len(word) >= 32 {
+			p.r.UnreadByte()
+			break
+		}
+		word = append(word, c)
+	}
+
+	param, hasParam := 0, false
+	negative := false
+	if c, err = p.r.ReadByte(); err == nil {
+		if c == '-' {
+			negative = true
+			c, err = p.r.ReadByte()
+		}
+		for err == nil && c >= '0' && c <= '9' {
+			hasParam = true
+			if param < 1<<20 {
+				param = param*10 + int(c-'0')
+			}
+			c, err = p.r.ReadByte()
+		}
+		// El espacio que delimita la palabra de control es parte de ella
+		if err == nil && c != ' ' {
+			p.r.UnreadByte()
+		}
+	}
+	if negative {
+		param = -param
+	}
+
+	return p.controlWord(string(word), param, hasParam)
+}
+
+func (p *rtfParser) controlWord(word string, param int, hasParam bool) error {
+	// Los caracteres de reemplazo de \uN pueden ser \'hh u otro símbolo
+	if p.skipChars > 0 && word != "u" {
+		if _, ok := rtfSymbols[word]; ok {
+			p.skipChars--
+			return nil
+		}
+	}
+	p.flushText()
+
+	ignorable := p.ignorable
+	p.ignorable = false
+	if ignorable {
+		switch word {
+		case "fldinst":
+			p.state.dest = rtfDestFieldInst
+		case "shpinst", "shptxt":
+			// Formas: sus propiedades (\sp) se ignoran, el texto de los cuadros no
+		default:
+			p.state.dest = rtfDestSkip
+			return nil
+		}
+	}
+
+	switch {
+	case rtfSkippedDestinations[word]:
+		p.state.dest = rtfDestSkip
+	case word == "header" || word == "headerr" || word == "footer" || word == "footerr":
+		p.startPageRegion(word)
+	case word == "shptxt":
+		// El cuadro de texto va después del párrafo en que está anclado
+		if para := p.state.out.para; p.state.dest == rtfDestText && para != nil && !para.IsEmpty() {
+			p.endParagraph()
+		}
+	case word == "listtext" || word == "pntext":
+		if p.state.dest == rtfDestText {
+			p.state.dest = rtfDestListText
+		}
+	case word == "ansicpg":
+		if cp, ok := rtfCodePages[param]; ok {
+			p.codePage = cp
+		}
+	case word == "bin":
+		// Datos binarios dentro del texto (imágenes): se descartan
+		if param > 0 {
+			if _, err := p.r.Discard(param); err != nil {
+				return nil
+			}
+		}
+	case word == "u":
+		p.writeUnicode(param)
+	case word == "uc":
+		p.state.uc = max(param, 0)
+	case word == "par" || word == "sect" || word == "nestcell":
+		p.endParagraph()
+	case word == "cell":
+		p.endCell()
+	case word == "row":
+		p.endRow()
+	case word == "pard":
+		p.state.inTable = false
+		p.state.outlineLevel = 0
+		p.state.listLevel = 0
+	case word == "intbl":
+		p.state.inTable = !hasParam || param != 0
+	case word == "outlinelevel":
+		p.state.outlineLevel = 0
+		if param >= 0 && param < 9 {
+			p.state.outlineLevel = param + 1
+		}
+	case word == "ilvl":
+		p.state.listLevel = min(max(param, 0), 8)
+	case word == "plain":
+		p.state.bold, p.state.italic, p.state.hidden = false, false, false
+	case word == "b":
+		p.state.bold = !hasParam || param != 0
+	case word == "i":
+		p.state.italic = !hasParam || param != 0
+	case word == "v":
+		p.state.hidden = !hasParam || param != 0
+	default:
+		if symbol, ok := rtfSymbols[word]; ok {
+			p.writeString(symbol)
+		}
+	}
+	return nil
+}
+
+// startPageRegion dirige el grupo al encabezado o al pie. Solo se usa el primero
+// de cada uno: las secciones siguientes suelen repetirlo.
+func (p *rtfParser) startPageRegion(word string) {
+	if p.state.dest != rtfDestText {
+		return
+	}
+	target := &p.header
+	if strings.HasPrefix(word, "footer") {
+		target = &p.footer
+	}
+	if *target != nil {
+		p.state.dest = rtfDestSkip
+		return
+	}
+	*target = &rtfOutput{}
+	p.state.out = *target
+	p.state.dest = rtfDestText
+	p.state.inTable, p.state.outlineLevel, p.state.listLevel = false, 0, 0
+}
+
+// --- Texto ---
+
+// writeByte agrega un byte de texto, decodificado con la página de códigos
+func (p *rtfParser) writeByte(b byte) {
+	if p.skipChars > 0 {
+		p.skipChars--
+		return
+	}
+	if b < 0x80 {
+		p.writeRune(rune(b))
+		return
+	}
+	p.writeRune(p.codePage.DecodeByte(b))
+}
+
+func (p *rtfParser) writeString(s string) {
+	if p.skipChars > 0 {
+		p.skipChars--
+		return
+	}
+	for _, r := range s {
+		p.writeRune(r)
+	}
+}
+
+// writeUnicode agrega el carácter de \uN (con signo, en UTF-16) y prepara el salto
+// de sus caracteres de reemplazo
+func (p *rtfParser) writeUnicode(param int) {
+	if param < 0 {
+		param += 0x10000
+	}
+	r := rune(param)
+	switch {
+	case utf16.IsSurrogate(r) && r < 0xDC00:
+		p.highSurrogate = r
+	case utf16.IsSurrogate(r):
+		p.writeRune(utf16.DecodeRune(p.highSurrogate, r))
+		p.highSurrogate = 0
+	default:
+		p.writeRune(r)
+	}
+	p.skipChars = p.state.uc
+}
+
+func (p *rtfParser) writeRune(r rune) {
+	switch {
+	case p.state.hidden:
+	case p.state.dest == rtfDestText:
+		p.text.WriteRune(r)
+	case p.state.dest == rtfDestListText:
+		p.listText.WriteRune(r)
+	case p.state.dest == rtfDestFieldInst:
+		p.fieldInst.WriteRune(r)
+	}
+}
+
+// flushText pasa el texto acumulado al párrafo actual con el formato del grupo.
+// Se llama antes de cada cambio de estado.
+func (p *rtfParser) flushText() {
+	if p.text.Len() == 0 {
+		return
+	}
+	out := p.state.out
+	if out.para == nil {
+		out.para = &Paragraph{}
+	}
+	out.para.Runs = appendRun(out.para.Runs, Run{
+		Text:   p.text.String(),
+		Bold:   p.state.bold,
+		Italic: p.state.italic,
+		Link:   p.state.link,
+	})
+	p.text.Reset()
+}
+
+// --- Párrafos y tablas ---
+
+// endParagraph cierra el párrafo actual con las propiedades del grupo
+func (p *rtfParser) endParagraph() {
+	if p.state.dest != rtfDestText {
+		return
+	}
+	out := p.state.out
+	para := out.para
+	if para == nil {
+		para = &Paragraph{}
+	}
+	out.para = nil
+
+	para.HeadingLevel = p.state.outlineLevel
+	if p.hasMarker {
+		para.List = &ListItem{Level: p.state.listLevel, Ordered: isOrderedMarker(p.marker), Marker: p.marker}
+		p.marker, p.hasMarker = "", false
+	}
+
+	if p.state.inTable {
+		out.cell = append(out.cell, para)
+		return
+	}
+	out.flushTable()
+	out.blocks = append(out.blocks, para)
+}
+
+// endCell cierra la celda actual; su último párrafo termina con \cell y no con \par
+func (p *rtfParser) endCell() {
+	if p.state.dest != rtfDestText {
+		return
+	}
+	out := p.state.out
+	if out.para != nil || len(out.cell) == 0 {
+		inTable := p.state.inTable
+		p.state.inTable = true
+		p.endParagraph()
+		p.state.inTable = inTable
+	}
+	out.cells = append(out.cells, TableCell{Blocks: out.cell})
+	out.cell = nil
+}
+
+func (p *rtfParser) endRow() {
+	if p.state.dest != rtfDestText {
+		return
+	}
+	out := p.state.out
+	if len(out.cells) > 0 {
+		out.rows = append(out.rows, TableRow{Cells: out.cells})
+	}
+	out.cells = nil
+}
+
+// flushTable agrega la tabla en construcción a los bloques
+func (o *rtfOutput) flushTable() {
+	if len(o.cells) > 0 {
+		o.rows = append(o.rows, TableRow{Cells: o.cells})
+		o.cells = nil
+	}
+	if len(o.rows) > 0 {
+		o.blocks = append(o.blocks, &Table{Rows: o.rows})
+		o.rows = nil
+	}
+}
+
+// finish cierra el último párrafo (un RTF puede terminar sin \par) y la tabla
+func (o *rtfOutput) finish() []Block {
+	if len(o.cell) > 0 {
+		o.cells = append(o.cells, TableCell{Blocks: o.cell})
+		o.cell = nil
+	}
+	o.flushTable()
+	if o.para != nil && !o.para.IsEmpty() {
+		o.blocks = append(o.blocks, o.para)
+	}
+	return o.blocks
+}
+
+// --- Funciones auxiliares ---
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// listMarker limpia el texto de \listtext. Las viñetas de la fuente Symbol llegan
+// como "·" o como caracteres del área privada.
+func listMarker(text string) string {
+	marker := strings.TrimSpace(strings.ReplaceAll(text, "\t", " "))
+	switch marker {
+	case "·", "", "", "§", "o":
+		return "•"
+	}
+	return marker
+}
+
+// isOrderedMarker indica si la viñeta es un número o letra (1., a), iv.)
+func isOrderedMarker(marker string) bool {
+	return strings.HasSuffix(marker, ".") || strings.HasSuffix(marker, ")") ||
+		strings.IndexAny(marker, "0123456789") >= 0
+}
@@ -0,0 +1,206 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tipos MIME que reconoce DetectMIMEType
+const (
+	MIMEPDF         = "application/pdf"
+	MIMEDocx        = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEODT         = "application/vnd.oasis.opendocument.text"
+	MIMEDoc         = "application/msword"
+	MIMEZip         = "application/zip"
+	MIMEOLE2        = "application/x-ole-storage"
+	MIMERTF         = "application/rtf"
+	MIMEHTML        = "text/html"
+	MIMEJPEG        = "image/jpeg"
+	MIMEPNG         = "image/png"
+	MIMEText        = "text/plain" // Texto de 8 bits que no es UTF-8 (ej: Latin-1)
+	MIMETextUTF8    = "text/plain; charset=utf-8"
+	MIMETextUTF16LE = "text/plain; charset=utf-16le"
+	MIMETextUTF16BE = "text/plain; charset=utf-16be"
+	MIMEUnknown     = "application/octet-stream"
+)
+
+// sniffLength es la cantidad de bytes iniciales que se examinan
+const sniffLength = 8 * 1024
+
+var (
+	pdfMagic      = []byte("%PDF-")
+	zipMagic      = []byte("PK\x03\x04")
+	ole2Magic     = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+	rtfMagic      = []byte(`{\rtf`)
+	jpegMagic     = []byte{0xFF, 0xD8, 0xFF}
+	pngMagic      = []byte("\x89PNG\r\n\x1a\n")
+	utf8BOM       = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM    = []byte{0xFF, 0xFE}
+	utf16BEBOM    = []byte{0xFE, 0xFF}
+	docxMainPart  = "word/document.xml"
+	odfMIMEPart   = "mimetype"
+	docMainStream = "WordDocument"
+)
+
+// htmlPrefixes son los inicios de documento HTML reconocidos, sin distinguir
+// mayúsculas y después de espacios y BOM
+var htmlPrefixes = []string{"<!doctype html", "<html", "<head", "<body", "<meta", "<title"}
+
+// DetectMIMEType identifica el tipo real del archivo por su contenido (magic bytes),
+// sin considerar la extensión. Un ZIP se reporta como DOCX u ODT solo si contiene el
+// documento principal de Word o la declaración de tipo de OpenDocument, y un
+// contenedor OLE2 como DOC solo si tiene el stream WordDocument. El texto UTF-16 se
+// reconoce con o sin BOM, y el texto que empieza con una etiqueta HTML se reporta
+// como HTML. Retorna MIMEUnknown si no
+// reconoce el contenido.
+func DetectMIMEType(r io.ReaderAt, size int64) string {
+	head := make([]byte, sniffLength)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return MIMEUnknown
+	}
+	head = head[:n]
+
+	switch {
+	case len(head) == 0:
+		return MIMEUnknown
+	case hasPDFHeader(head):
+		return MIMEPDF
+	case bytes.HasPrefix(head, zipMagic):
+		return detectZipType(r, size)
+	case bytes.HasPrefix(head, ole2Magic):
+		return detectOLE2Type(r, size)
+	case bytes.HasPrefix(head, rtfMagic):
+		return MIMERTF
+	case bytes.HasPrefix(head, jpegMagic):
+		return MIMEJPEG
+	case bytes.HasPrefix(head, pngMagic):
+		return MIMEPNG
+	case bytes.HasPrefix(head, utf16LEBOM) || detectUTF16(head) == EncodingUTF16LE:
+		return MIMETextUTF16LE
+	case bytes.HasPrefix(head, utf16BEBOM) || detectUTF16(head) == EncodingUTF16BE:
+		return MIMETextUTF16BE
+	case isUTF8Text(bytes.TrimPrefix(head, utf8BOM), int64(n) < size):
+		if looksLikeHTML(head) {
+			return MIMEHTML
+		}
+		return MIMETextUTF8
+	case !hasBinaryControls(head):
+		if looksLikeHTML(head) {
+			return MIMEHTML
+		}
+		return MIMEText
+	}
+
+	return MIMEUnknown
+}
+
+// hasPDFHeader busca la firma %PDF- en el primer KB, como hacen los lectores de PDF
+func hasPDFHeader(head []byte) bool {
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.Contains(head, pdfMagic)
+}
+
+// detectZipType distingue DOCX y ODT de un ZIP genérico. En OpenDocument la parte
+// "mimetype" contiene el tipo del documento.
+func detectZipType(r io.ReaderAt, size int64) string {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return MIMEZip
+	}
+	for _, file := range archive.File {
+		switch file.Name {
+		case docxMainPart:
+			return MIMEDocx
+		case odfMIMEPart:
+			if readSmallPart(file) == MIMEODT {
+				return MIMEODT
+			}
+		}
+	}
+	return MIMEZip
+}
+
+// detectOLE2Type distingue un documento de Word 97-2003 de otros archivos OLE2
+// (Excel, PowerPoint, MSI)
+func detectOLE2Type(r io.ReaderAt, size int64) string {
+	cfb, err := openCFB(r, size)
+	if err != nil || !cfb.hasStream(docMainStream) {
+		return MIMEOLE2
+	}
+	return MIMEDoc
+}
+
+// readSmallPart lee una parte corta del ZIP (como "mimetype"); retorna "" si no se
+// puede leer o si es más larga de lo esperado
+func readSmallPart(file *zip.File) string {
+	rc, err := file.Open()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rc, 256))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// looksLikeHTML indica si el texto empieza con una declaración o etiqueta HTML.
+// En XHTML se omite primero la declaración XML.
+func looksLikeHTML(head []byte) bool {
+	lower := strings.ToLower(string(bytes.TrimPrefix(head, utf8BOM)))
+	lower = strings.TrimLeft(lower, " \t\r\n\f")
+	if strings.HasPrefix(lower, "<?xml") {
+		end := strings.Index(lower, "?>")
+		if end < 0 {
+			return false
+		}
+		lower = strings.TrimLeft(lower[end+2:], " \t\r\n\f")
+	}
+	for _, prefix := range htmlPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUTF8Text indica si los bytes son UTF-8 válido sin caracteres de control binarios.
+// Si el contenido sigue después de head (truncated), se tolera una runa cortada al final.
+func isUTF8Text(head []byte, truncated bool) bool {
+	for len(head) > 0 {
+		r, size := utf8.DecodeRune(head)
+		if r == utf8.RuneError && size <= 1 {
+			if truncated && len(head) < utf8.UTFMax && !utf8.FullRune(head) {
+				return true
+			}
+			return false
+		}
+		if isBinaryControl(r) {
+			return false
+		}
+		head = head[size:]
+	}
+	return true
+}
+
+// hasBinaryControls indica si hay bytes de control que no aparecen en texto plano
+func hasBinaryControls(head []byte) bool {
+	for _, b := range head {
+		if isBinaryControl(rune(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBinaryControl(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f'
+}
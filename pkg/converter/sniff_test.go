@@ -0,0 +1,104 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func zipWith(t *testing.T, names ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, name := range names {
+		part, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("<xml/>"))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// odtWithMimetype arma un ODT mínimo con la parte "mimetype"
+func odtWithMimetype(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	part, err := writer.Create("mimetype")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(MIMEODT))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectMIMEType(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"pdf", []byte("%PDF-1.7\n%âãÏÓ\n"), MIMEPDF},
+		{"pdf con basura inicial", append([]byte("\r\n\r\n"), "%PDF-1.4"...), MIMEPDF},
+		{"docx", zipWith(t, "[Content_Types].xml", "word/document.xml"), MIMEDocx},
+		{"odt", odtWithMimetype(t), MIMEODT},
+		{"zip genérico", zipWith(t, "notas.txt"), MIMEZip},
+		{"ole2", []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0, 0}, MIMEOLE2},
+		{"rtf", []byte(`{\rtf1\ansi Hola}`), MIMERTF},
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0x10, 'J', 'F', 'I', 'F'}, MIMEJPEG},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), MIMEPNG},
+		{"html", []byte("<!DOCTYPE html>\n<html><body>CV</body></html>"), MIMEHTML},
+		{"html con declaración xml", []byte("<?xml version=\"1.0\"?>\n<HTML lang=\"es\">"), MIMEHTML},
+		{"html latin-1", []byte("<html><p>Jos\xe9</p>"), MIMEHTML},
+		{"markdown", []byte("# Juan Pérez\n<b>no es html</b>\n"), MIMETextUTF8},
+		{"texto utf-8", []byte("Juan Pérez\nDesarrollador\n"), MIMETextUTF8},
+		{"texto utf-8 con BOM", append([]byte{0xEF, 0xBB, 0xBF}, "Hola"...), MIMETextUTF8},
+		{"texto utf-16le", []byte{0xFF, 0xFE, 'H', 0, 'o', 0}, MIMETextUTF16LE},
+		{"texto utf-16be", []byte{0xFE, 0xFF, 0, 'H', 0, 'o'}, MIMETextUTF16BE},
+		{"ejecutable", []byte("MZ\x90\x00\x03\x00\x00\x00"), MIMEUnknown},
+		{"texto latin-1", []byte("Jos\xe9 P\xe9rez\n"), MIMEText},
+		{"vacío", nil, MIMEUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectMIMEType(bytes.NewReader(tt.content), int64(len(tt.content)))
+			if got != tt.want {
+				t.Errorf("DetectMIMEType = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectMIMETypeToleratesRuneCutAtSniffLimit(t *testing.T) {
+	content := bytes.Repeat([]byte("a"), sniffLength-1)
+	content = append(content, "ñandú"...)
+
+	if got := DetectMIMEType(bytes.NewReader(content), int64(len(content))); got != MIMETextUTF8 {
+		t.Errorf("DetectMIMEType = %q, se esperaba %q", got, MIMETextUTF8)
+	}
+}
+
+func TestMatchesExtension(t *testing.T) {
+	if !MatchesExtension(".PDF", MIMEPDF) {
+		t.Error(".PDF debe aceptar application/pdf")
+	}
+	if !MatchesExtension(".txt", MIMETextUTF16LE) {
+		t.Error(".txt debe aceptar texto UTF-16")
+	}
+	if MatchesExtension(".pdf", MIMEDocx) {
+		t.Error(".pdf no debe aceptar un DOCX")
+	}
+	if MatchesExtension(".docx", MIMEZip) {
+		t.Error(".docx no debe aceptar un ZIP sin documento de Word")
+	}
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxPartSize limita el tamaño descomprimido de cada parte XML de un documento
+// empaquetado (DOCX, ODT), para no procesar archivos comprimidos maliciosamente
+// (zip bombs)
+const maxPartSize = 64 * 1024 * 1024
+
+var errPartTooLarge = errors.New("parte del documento demasiado grande")
+
+// zipPackage da acceso por nombre a las partes de un documento empaquetado en ZIP
+type zipPackage struct {
+	files map[string]*zip.File
+}
+
+func newZipPackage(archive *zip.Reader) zipPackage {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return zipPackage{files: files}
+}
+
+func (p zipPackage) has(name string) bool {
+	return p.files[name] != nil
+}
+
+// open abre una parte limitando su tamaño descomprimido. Retorna nil si la parte no
+// existe. kind es el formato del documento, para los mensajes de error.
+func (p zipPackage) open(name, kind string) (io.ReadCloser, error) {
+	f := p.files[name]
+	if f == nil {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error al leer %s del %s: %w", name, kind, err)
+	}
+	return &limitedPart{ReadCloser: rc, remaining: maxPartSize}, nil
+}
+
+// limitedPart falla en lugar de truncar cuando la parte supera maxPartSize
+type limitedPart struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedPart) Read(b []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, errPartTooLarge
+	}
+	if int64(len(b)) > l.remaining {
+		b = b[:l.remaining]
+	}
+	n, err := l.ReadCloser.Read(b)
+	l.remaining -= int64(n)
+	return n, err
+}
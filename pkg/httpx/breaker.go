@@ -0,0 +1,116 @@
+package httpx
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen indica que el circuito está abierto: el destino falló varias veces
+// seguidas y las llamadas se rechazan sin intentarse hasta que pase el enfriamiento
+var ErrCircuitOpen = errors.New("circuito abierto: el servicio no está respondiendo")
+
+// Estados del circuit breaker
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+// Breaker es un circuit breaker por fallas consecutivas. Tras threshold fallas se abre
+// y rechaza las llamadas durante cooldown; después deja pasar una sola llamada de
+// prueba (semiabierto): si funciona se cierra y si falla vuelve a abrirse.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker crea el breaker. threshold 0 lo deshabilita (nunca se abre).
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     breakerClosed,
+	}
+}
+
+// Allow indica si se puede hacer una llamada. En estado semiabierto solo se permite
+// una a la vez; quien la obtiene debe informar el resultado con Success o Failure.
+func (b *Breaker) Allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Success registra una llamada exitosa y cierra el circuito
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure registra una llamada fallida; abre el circuito al llegar al umbral o si
+// falló la llamada de prueba
+func (b *Breaker) Failure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Release libera la llamada de prueba sin registrar resultado (p. ej. la canceló
+// quien la hizo), para que la próxima llamada pueda probar el destino
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State retorna el estado actual: closed, open o half_open
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return breakerHalfOpen
+	}
+	return b.state
+}
@@ -0,0 +1,82 @@
+package httpx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	breaker := NewBreaker(3, 30*time.Second)
+	now := time.Unix(1_700_000_000, 0)
+	breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		breaker.Allow()
+		breaker.Failure()
+	}
+	// Un éxito reinicia la cuenta de fallas seguidas
+	breaker.Allow()
+	breaker.Success()
+	for i := 0; i < 3; i++ {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("intento %d rechazado antes del umbral: %v", i+1, err)
+		}
+		breaker.Failure()
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("se esperaba ErrCircuitOpen, se obtuvo %v", err)
+	}
+
+	// Semiabierto: una sola llamada de prueba a la vez
+	now = now.Add(30 * time.Second)
+	if state := breaker.State(); state != breakerHalfOpen {
+		t.Errorf("estado = %s", state)
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("la llamada de prueba debe permitirse: %v", err)
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Error("solo se permite una llamada de prueba")
+	}
+
+	// Si la prueba falla se vuelve a abrir por otro enfriamiento completo
+	breaker.Failure()
+	now = now.Add(10 * time.Second)
+	if err := breaker.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Error("la prueba fallida debe reabrir el circuito")
+	}
+
+	now = now.Add(30 * time.Second)
+	breaker.Allow()
+	breaker.Success()
+	if state := breaker.State(); state != breakerClosed {
+		t.Errorf("estado = %s", state)
+	}
+}
+
+func TestBreakerReleaseAllowsAnotherProbe(t *testing.T) {
+	breaker := NewBreaker(1, time.Second)
+	now := time.Unix(1_700_000_000, 0)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Allow()
+	breaker.Failure()
+	now = now.Add(time.Second)
+
+	breaker.Allow()
+	breaker.Release()
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("tras liberar la prueba se debe permitir otra: %v", err)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	breaker := NewBreaker(0, time.Second)
+	for i := 0; i < 10; i++ {
+		breaker.Failure()
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("con umbral 0 nunca se abre: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package httpx es la capa común de las llamadas HTTP salientes: timeout por intento,
+// reintentos con backoff exponencial y jitter ante errores de red y respuestas 5xx, y
+// un circuit breaker que falla rápido cuando el destino no responde.
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Options configura un Client
+type Options struct {
+	// Timeout es el tiempo máximo de cada intento, incluida la lectura del cuerpo de
+	// la respuesta (0 = sin límite propio, solo el del contexto de la llamada)
+	Timeout time.Duration
+	// MaxAttempts es la cantidad total de intentos (1 = sin reintentos)
+	MaxAttempts int
+	// BaseDelay y MaxDelay acotan el backoff: BaseDelay * 2^(intento-1), hasta MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// BreakerThreshold es la cantidad de llamadas fallidas seguidas que abren el
+	// circuito (0 = sin circuit breaker) y BreakerCooldown el tiempo que queda abierto
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Client hace llamadas HTTP con reintentos y circuit breaker. Cada destino debe tener
+// su propio Client, así un servicio caído no abre el circuito de los demás.
+type Client struct {
+	name       string
+	httpClient *http.Client
+	options    Options
+	breaker    *Breaker
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// New crea un cliente. name identifica al destino en los logs (ej: "presign").
+func New(name string, options Options) *Client {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	return &Client{
+		name:       name,
+		httpClient: &http.Client{},
+		options:    options,
+		breaker:    NewBreaker(options.BreakerThreshold, options.BreakerCooldown),
+		sleep:      sleepContext,
+	}
+}
+
+// WithHTTPClient reemplaza el http.Client usado en cada intento (ej: uno que no sigue
+// redirecciones). Su Timeout no se usa: el de cada intento es Options.Timeout.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	copied := *httpClient
+	copied.Timeout = 0
+	c.httpClient = &copied
+	return c
+}
+
+// Breaker retorna el circuit breaker del cliente
+func (c *Client) Breaker() *Breaker {
+	return c.breaker
+}
+
+// Do ejecuta la petición. Los errores de red y las respuestas 5xx se reintentan si el
+// cuerpo de la petición se puede volver a leer (req.GetBody, que http.NewRequest arma
+// para bytes.Reader, bytes.Buffer y strings.Reader). Si se agotan los intentos con una
+// respuesta 5xx, se retorna esa respuesta sin error, como http.Client.
+//
+// El contexto de req acota la llamada completa, reintentos incluidos. Con el circuito
+// abierto se retorna ErrCircuitOpen sin intentar la llamada.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(req, attempt)
+		retryable := isRetryable(ctx, resp, err)
+
+		if !retryable {
+			if err != nil && ctx.Err() != nil {
+				// La canceló quien llamó: no dice nada del destino
+				c.breaker.Release()
+			} else {
+				c.breaker.Success()
+			}
+			return resp, err
+		}
+
+		canRetry := attempt < c.options.MaxAttempts && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+		if !canRetry {
+			c.breaker.Failure()
+			if c.breaker.State() == breakerOpen {
+				log.Printf("🔌 Circuito abierto para %s tras fallas consecutivas", c.name)
+			}
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		if err != nil {
+			log.Printf("⚠️  %s: intento %d/%d fallido (%v), se reintenta en %s", c.name, attempt, c.options.MaxAttempts, err, delay)
+		} else {
+			log.Printf("⚠️  %s: intento %d/%d respondió %d, se reintenta en %s", c.name, attempt, c.options.MaxAttempts, resp.StatusCode, delay)
+			drain(resp)
+		}
+
+		if err := c.sleep(ctx, delay); err != nil {
+			c.breaker.Release()
+			return nil, err
+		}
+	}
+}
+
+// attempt hace un intento con su propio timeout. El contexto del intento se cancela
+// al cerrar el cuerpo de la respuesta, así el timeout cubre también su lectura.
+func (c *Client) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if c.options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("error al releer el cuerpo de la petición: %w", err)
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := c.httpClient.Do(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff retorna la espera antes del siguiente intento: la mitad fija y la otra
+// mitad aleatoria, para que los clientes que fallaron juntos no reintenten juntos
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.options.BaseDelay << (attempt - 1)
+	if delay <= 0 || (c.options.MaxDelay > 0 && delay > c.options.MaxDelay) {
+		delay = c.options.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// isRetryable indica si el resultado de un intento amerita reintentar: errores de red
+// (salvo que el contexto de la llamada se haya cancelado) y respuestas 5xx
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode >= 500
+}
+
+// drain descarta el cuerpo de una respuesta que se reintenta, para reusar la conexión
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelBody cancela el contexto del intento al cerrar el cuerpo
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// IsCircuitOpen indica si el error se debe a un circuito abierto
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
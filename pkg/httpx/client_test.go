@@ -0,0 +1,220 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient crea un cliente que no espera entre reintentos y registra las esperas
+func newTestClient(options Options) (*Client, *[]time.Duration) {
+	client := New("prueba", options)
+	var delays []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return client, &delays
+}
+
+// statusServer responde con los status indicados, uno por llamada (el último se repite)
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+		io.WriteString(w, "respuesta")
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDoRetries5xxUntilSuccess(t *testing.T) {
+	var bodies []string
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, delays := newTestClient(Options{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"a":1}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("status=%d llamadas=%d", resp.StatusCode, calls)
+	}
+	for i, body := range bodies {
+		if body != `{"a":1}` {
+			t.Errorf("intento %d: body = %q, se debe reenviar completo", i+1, body)
+		}
+	}
+	// Backoff exponencial con jitter: entre la mitad y el total de 100ms y 200ms
+	if len(*delays) != 2 {
+		t.Fatalf("esperas = %v", *delays)
+	}
+	for i, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if d := (*delays)[i]; d < base/2 || d > base {
+			t.Errorf("espera %d = %s, fuera de [%s, %s]", i+1, d, base/2, base)
+		}
+	}
+}
+
+func TestDoDoesNotRetry4xx(t *testing.T) {
+	server, calls := statusServer(t, http.StatusBadRequest)
+	client, _ := newTestClient(Options{MaxAttempts: 3})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest || *calls != 1 {
+		t.Errorf("status=%d llamadas=%d", resp.StatusCode, *calls)
+	}
+}
+
+func TestDoReturnsLast5xxWhenAttemptsRunOut(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	client, _ := newTestClient(Options{MaxAttempts: 2})
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || string(body) != "respuesta" || *calls != 2 {
+		t.Errorf("status=%d body=%q llamadas=%d", resp.StatusCode, body, *calls)
+	}
+}
+
+func TestDoDoesNotRetryUnrereadableBody(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError, http.StatusOK)
+	client, _ := newTestClient(Options{MaxAttempts: 3})
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("x")))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	if *calls != 1 {
+		t.Errorf("llamadas = %d: un body que no se puede releer no se reintenta", *calls)
+	}
+}
+
+func TestDoAppliesTimeoutPerAttempt(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// El primer intento se cuelga hasta que venza su timeout
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := newTestClient(Options{Timeout: 50 * time.Millisecond, MaxAttempts: 2})
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("status=%d llamadas=%d", resp.StatusCode, calls)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("la llamada tardó %s: el timeout por intento no se aplicó", elapsed)
+	}
+}
+
+func TestDoStopsWhenContextIsCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(Options{MaxAttempts: 5, BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("se esperaba DeadlineExceeded, se obtuvo %v", err)
+	}
+	// La cancelación de quien llama no cuenta como falla del destino
+	if state := client.Breaker().State(); state != breakerClosed {
+		t.Errorf("estado del circuito = %s", state)
+	}
+}
+
+func TestDoFailsFastWhenCircuitIsOpen(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError)
+	client, _ := newTestClient(Options{MaxAttempts: 2, BreakerThreshold: 2, BreakerCooldown: time.Minute})
+	now := time.Unix(1_700_000_000, 0)
+	client.Breaker().now = func() time.Time { return now }
+
+	do := func() (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	// Dos llamadas fallidas (con sus reintentos) abren el circuito
+	do()
+	do()
+	if *calls != 4 {
+		t.Fatalf("llamadas = %d", *calls)
+	}
+	if _, err := do(); !errors.Is(err, ErrCircuitOpen) || !IsCircuitOpen(err) {
+		t.Fatalf("se esperaba ErrCircuitOpen, se obtuvo %v", err)
+	}
+	if *calls != 4 {
+		t.Errorf("con el circuito abierto no se debe llamar al servidor (llamadas = %d)", *calls)
+	}
+
+	// Pasado el enfriamiento, una llamada exitosa cierra el circuito
+	now = now.Add(time.Minute)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	if resp, err := do(); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("llamada de prueba: %v", err)
+	}
+	if state := client.Breaker().State(); state != breakerClosed {
+		t.Errorf("estado del circuito = %s", state)
+	}
+}
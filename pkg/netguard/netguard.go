@@ -0,0 +1,106 @@
+// Package netguard evita que las llamadas salientes a URLs elegidas por los usuarios (los
+// webhooks) alcancen la red interna: loopback, redes privadas, link-local (incluido el
+// servicio de metadata de la nube) y demás rangos no públicos.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrDisallowedAddress indica que el destino resuelve a una dirección no pública
+var ErrDisallowedAddress = errors.New("dirección de destino no permitida")
+
+// blockedPrefixes son los rangos no públicos que no cubren los métodos de netip.Addr
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "Esta" red
+	netip.MustParsePrefix("100.64.0.0/10"),  // NAT de operador (CGNAT)
+	netip.MustParsePrefix("192.0.0.0/24"),   // Asignaciones de protocolo IETF
+	netip.MustParsePrefix("198.18.0.0/15"),  // Pruebas de rendimiento
+	netip.MustParsePrefix("240.0.0.0/4"),    // Reservado (incluye broadcast)
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64: puede apuntar a una IPv4 interna
+	netip.MustParsePrefix("64:ff9b:1::/48"), // NAT64 local
+	netip.MustParsePrefix("2002::/16"),      // 6to4: embebe una IPv4 arbitraria
+	netip.MustParsePrefix("fec0::/10"),      // Site-local (obsoleto)
+}
+
+// internalHostSuffixes son sufijos de nombres que solo resuelven dentro de la red interna
+var internalHostSuffixes = []string{".localhost", ".local", ".localdomain", ".internal", ".intranet", ".lan", ".home.arpa"}
+
+// IsPublicAddr indica si addr es una dirección unicast pública. Rechaza loopback, redes
+// privadas (10/8, 172.16/12, 192.168/16, fc00::/7), link-local (169.254/16, donde vive
+// la metadata 169.254.169.254, y fe80::/10), la dirección no especificada y multicast.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsInternalHostname indica si host es un nombre reservado para la red interna
+// (localhost, *.internal como metadata.google.internal, *.local, ...) o un nombre sin
+// dominio, que el resolver completaría con los dominios de búsqueda locales
+func IsInternalHostname(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHost valida que host (nombre o IP literal) sea público y que todas las
+// direcciones a las que resuelve también lo sean
+func CheckHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		if !IsPublicAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrDisallowedAddress, addr)
+		}
+		return nil
+	}
+
+	if IsInternalHostname(host) {
+		return fmt.Errorf("%w: %s es un nombre interno", ErrDisallowedAddress, host)
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("error al resolver %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return fmt.Errorf("%w: %s resuelve a %s", ErrDisallowedAddress, host, addr)
+		}
+	}
+	return nil
+}
+
+// DialControl se usa como net.Dialer.Control: revisa la IP ya resuelta justo antes de
+// conectar, así un cambio de DNS posterior a la validación (DNS rebinding) no permite
+// alcanzar la red interna
+func DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.168.1.10", false},
+		{"169.254.169.254", false},
+		{"fd00:ec2::254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"172.32.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.expected {
+				t.Errorf("IsPublicAddr(%s) = %v, expected %v", tt.addr, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"[2606:4700::1111]", true},
+		{"169.254.169.254", false},
+		{"[::1]", false},
+		{"localhost", false},
+		{"api.localhost", false},
+		{"metadata.google.internal", false},
+		{"printer.local", false},
+		{"intranet", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := CheckHost(context.Background(), net.DefaultResolver, tt.host)
+			if tt.allowed && err != nil {
+				t.Errorf("CheckHost(%s) = %v, se esperaba permitido", tt.host, err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrDisallowedAddress) {
+				t.Errorf("CheckHost(%s) = %v, se esperaba ErrDisallowedAddress", tt.host, err)
+			}
+		})
+	}
+}
+
+func TestDialControlRejectsInternalAddresses(t *testing.T) {
+	dialer := &net.Dialer{Control: DialControl}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	_, err = dialer.Dial("tcp", listener.Addr().String())
+	if !errors.Is(err, ErrDisallowedAddress) {
+		t.Errorf("se esperaba ErrDisallowedAddress al conectar a loopback, se obtuvo %v", err)
+	}
+}
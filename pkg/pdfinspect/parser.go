@@ -0,0 +1,367 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Tipos de objeto PDF. Los enteros son int64, los reales float64, los booleanos
+// bool y null es nil.
+type (
+	name   string
+	array  []object
+	dict   map[name]object
+	object interface{}
+)
+
+// pdfString es el contenido de una cadena literal o hexadecimal, sin decodificar
+type pdfString []byte
+
+// ref es una referencia indirecta ("12 0 R")
+type ref struct {
+	num, gen int64
+}
+
+// stream es un objeto stream: su diccionario y los datos sin decodificar
+type stream struct {
+	dict dict
+	data []byte
+}
+
+// maxNesting limita la anidación de arreglos y diccionarios
+const maxNesting = 100
+
+// parser lee objetos PDF desde una posición de los datos
+type parser struct {
+	data  []byte
+	pos   int
+	depth int
+	// streamLength resuelve el /Length de un stream cuando es una referencia
+	streamLength func(object) (int64, bool)
+}
+
+func isWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace salta espacios y comentarios
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\r' && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		if !isWhitespace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// keyword lee una palabra (número, palabra clave) hasta el próximo espacio o delimitador
+func (p *parser) keyword() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// hasKeyword indica si lo siguiente es la palabra dada; si lo es, la consume
+func (p *parser) hasKeyword(word string) bool {
+	start := p.pos
+	if p.keyword() == word {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// parseObject lee el siguiente objeto. Los enteros seguidos de "gen R" forman una
+// referencia y los diccionarios seguidos de "stream", un stream.
+func (p *parser) parseObject() (object, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: fin inesperado de los datos", ErrMalformed)
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		p.pos++
+		return p.parseName(), nil
+	case c == '(':
+		p.pos++
+		return p.parseLiteralString()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		p.pos += 2
+		return p.parseDict()
+	case c == '<':
+		p.pos++
+		return p.parseHexString()
+	case c == '[':
+		p.pos++
+		return p.parseArray()
+	}
+
+	word := p.keyword()
+	switch word {
+	case "":
+		return nil, fmt.Errorf("%w: carácter inesperado %q en la posición %d", ErrMalformed, p.data[p.pos], p.pos)
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+		// Una referencia son dos enteros seguidos de "R"
+		start := p.pos
+		if gen, err := strconv.ParseInt(p.keyword(), 10, 64); err == nil && p.hasKeyword("R") {
+			return ref{num: n, gen: gen}, nil
+		}
+		p.pos = start
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("%w: palabra inesperada %q en la posición %d", ErrMalformed, word, p.pos)
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxNesting {
+		return fmt.Errorf("%w: anidación excesiva", ErrMalformed)
+	}
+	return nil
+}
+
+func (p *parser) parseArray() (object, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	var arr array
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, fmt.Errorf("%w: arreglo sin cerrar", ErrMalformed)
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return arr, nil
+		}
+		value, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, value)
+	}
+}
+
+func (p *parser) parseDict() (object, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	d := dict{}
+	for {
+		p.skipSpace()
+		if p.pos+1 < len(p.data) && p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			break
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("%w: clave de diccionario inválida en la posición %d", ErrMalformed, p.pos)
+		}
+		p.pos++
+		key := p.parseName()
+		value, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		d[key] = value
+	}
+
+	start := p.pos
+	if !p.hasKeyword("stream") {
+		p.pos = start
+		return d, nil
+	}
+	return p.parseStreamData(d)
+}
+
+// parseStreamData lee los datos del stream que sigue a su diccionario. Si /Length
+// no es confiable se busca "endstream".
+func (p *parser) parseStreamData(d dict) (object, error) {
+	// La palabra stream va seguida de CRLF o LF
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+
+	length, ok := d["Length"].(int64)
+	if !ok && p.streamLength != nil {
+		length, ok = p.streamLength(d["Length"])
+	}
+	if ok && length >= 0 && int64(start)+length <= int64(len(p.data)) {
+		end := start + int(length)
+		check := &parser{data: p.data, pos: end}
+		if check.hasKeyword("endstream") {
+			p.pos = check.pos
+			return &stream{dict: d, data: p.data[start:end]}, nil
+		}
+	}
+
+	end := bytes.Index(p.data[start:], []byte("endstream"))
+	if end < 0 {
+		return nil, fmt.Errorf("%w: stream sin endstream", ErrMalformed)
+	}
+	p.pos = start + end + len("endstream")
+	data := bytes.TrimRight(p.data[start:start+end], "\r\n")
+	return &stream{dict: d, data: data}, nil
+}
+
+// parseName lee un nombre después de "/", resolviendo los escapes #xx
+func (p *parser) parseName() name {
+	var out []byte
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		out = append(out, c)
+		p.pos++
+	}
+	return name(out)
+}
+
+// parseLiteralString lee una cadena entre paréntesis (que pueden anidarse) con sus
+// escapes
+func (p *parser) parseLiteralString() (object, error) {
+	var out []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(out), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				continue
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Continuación de línea
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					// Octal de hasta tres dígitos
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return nil, fmt.Errorf("%w: cadena sin cerrar", ErrMalformed)
+}
+
+// parseHexString lee una cadena hexadecimal; un dígito final impar vale como si
+// le siguiera un 0
+func (p *parser) parseHexString() (object, error) {
+	var out []byte
+	var digits []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		if c == '>' {
+			if len(digits) == 1 {
+				digits = append(digits, '0')
+			}
+			if len(digits) == 2 {
+				v, _ := strconv.ParseUint(string(digits), 16, 8)
+				out = append(out, byte(v))
+			}
+			return pdfString(out), nil
+		}
+		if isWhitespace(c) {
+			continue
+		}
+		if _, err := strconv.ParseUint(string(c), 16, 8); err != nil {
+			return nil, fmt.Errorf("%w: cadena hexadecimal inválida", ErrMalformed)
+		}
+		digits = append(digits, c)
+		if len(digits) == 2 {
+			v, _ := strconv.ParseUint(string(digits), 16, 8)
+			out = append(out, byte(v))
+			digits = digits[:0]
+		}
+	}
+	return nil, fmt.Errorf("%w: cadena hexadecimal sin cerrar", ErrMalformed)
+}
+
+// parseIndirect lee un objeto indirecto ("12 0 obj ... endobj") y retorna su número
+func (p *parser) parseIndirect() (int64, object, error) {
+	num, err := strconv.ParseInt(p.keyword(), 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: se esperaba un objeto en la posición %d", ErrMalformed, p.pos)
+	}
+	if _, err := strconv.ParseInt(p.keyword(), 10, 64); err != nil || !p.hasKeyword("obj") {
+		return 0, nil, fmt.Errorf("%w: se esperaba un objeto en la posición %d", ErrMalformed, p.pos)
+	}
+	value, err := p.parseObject()
+	if err != nil {
+		return 0, nil, err
+	}
+	return num, value, nil
+}
@@ -0,0 +1,210 @@
+// Package pdfinspect revisa la estructura de un PDF sin depender de herramientas
+// externas: encabezado, trailer, cifrado, cantidad de páginas y metadatos. No
+// interpreta el contenido de las páginas; solo lo necesario para rechazar un
+// archivo dañado o protegido antes de enviarlo a procesar.
+package pdfinspect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+var (
+	// ErrNotPDF indica que el archivo no tiene el encabezado %PDF
+	ErrNotPDF = errors.New("el archivo no es un PDF")
+	// ErrTruncated indica que falta el final del archivo (startxref y %%EOF),
+	// típico de una descarga o subida interrumpida
+	ErrTruncated = errors.New("el PDF está incompleto")
+	// ErrMalformed indica que la estructura del PDF está dañada
+	ErrMalformed = errors.New("el PDF está dañado")
+)
+
+// Info es lo que se obtiene al inspeccionar un PDF
+type Info struct {
+	Version   string // Versión del encabezado, ej: "1.7"
+	Pages     int
+	Encrypted bool
+	// Metadatos del diccionario /Info. En un PDF cifrado quedan vacíos, porque
+	// sus cadenas también están cifradas.
+	Title    string
+	Author   string
+	Creator  string
+	Producer string
+}
+
+const (
+	// headerWindow es dónde se busca el encabezado: la especificación tolera
+	// basura antes de %PDF
+	headerWindow = 1024
+	// trailerWindow es dónde se busca %%EOF y startxref al final del archivo
+	trailerWindow = 2048
+	// maxPageTreeDepth limita la recursión al contar páginas sin /Count
+	maxPageTreeDepth = 64
+)
+
+var (
+	headerPattern    = regexp.MustCompile(`%PDF-(\d\.\d)`)
+	startxrefPattern = regexp.MustCompile(`startxref\s+(\d+)`)
+)
+
+// Inspect lee el PDF completo y retorna su información. Los errores envuelven
+// ErrNotPDF, ErrTruncated o ErrMalformed. Un PDF cifrado no es un error: se
+// informa en Info.Encrypted y el llamador decide qué hacer.
+func Inspect(r io.ReaderAt, size int64) (*Info, error) {
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo PDF: %w", err)
+	}
+
+	head := data[:min(len(data), headerWindow)]
+	base := bytes.Index(head, []byte("%PDF-"))
+	if base < 0 {
+		return nil, ErrNotPDF
+	}
+	info := &Info{}
+	if m := headerPattern.FindSubmatch(head[base:]); m != nil {
+		info.Version = string(m[1])
+	}
+
+	// El final del archivo debe tener "startxref <offset>" y "%%EOF"
+	tail := data[max(0, len(data)-trailerWindow):]
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return nil, fmt.Errorf("%w: falta %%%%EOF", ErrTruncated)
+	}
+	matches := startxrefPattern.FindAllSubmatch(tail, -1)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: falta startxref", ErrTruncated)
+	}
+	startxref, err := strconv.ParseInt(string(matches[len(matches)-1][1]), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: startxref inválido", ErrMalformed)
+	}
+
+	doc := newDocument(data, int64(base))
+	if err := doc.loadXref(startxref); err != nil || doc.catalog() == nil {
+		// Tabla xref dañada: se reconstruye recorriendo los objetos
+		if err := doc.rebuildXref(); err != nil {
+			return nil, err
+		}
+	}
+	catalog := doc.catalog()
+	if catalog == nil {
+		return nil, fmt.Errorf("%w: no se encontró el catálogo (/Root)", ErrMalformed)
+	}
+
+	if doc.trailer["Encrypt"] != nil {
+		info.Encrypted = true
+	} else if meta, ok := doc.resolve(doc.trailer["Info"]).(dict); ok {
+		info.Title = doc.text(meta["Title"])
+		info.Author = doc.text(meta["Author"])
+		info.Creator = doc.text(meta["Creator"])
+		info.Producer = doc.text(meta["Producer"])
+	}
+
+	// /Count es un entero, que no se cifra; pero si el árbol de páginas está en un
+	// stream de objetos cifrado no se puede leer, y eso no es un daño del archivo
+	pages, ok := doc.resolve(catalog["Pages"]).(dict)
+	if !ok {
+		if info.Encrypted {
+			return info, nil
+		}
+		return nil, fmt.Errorf("%w: no se encontró el árbol de páginas", ErrMalformed)
+	}
+	info.Pages = doc.pageCount(pages)
+	return info, nil
+}
+
+// catalog retorna el diccionario /Root del trailer, o nil
+func (d *document) catalog() dict {
+	if d.trailer == nil {
+		return nil
+	}
+	catalog, _ := d.resolve(d.trailer["Root"]).(dict)
+	return catalog
+}
+
+// pageCount retorna el /Count de la raíz del árbol de páginas o, si falta o es
+// inválido, cuenta las hojas recorriendo /Kids
+func (d *document) pageCount(root dict) int {
+	if count, ok := d.resolve(root["Count"]).(int64); ok && count >= 0 {
+		return int(count)
+	}
+	return d.countLeaves(root, map[int64]bool{}, 0)
+}
+
+func (d *document) countLeaves(node dict, visited map[int64]bool, depth int) int {
+	if depth > maxPageTreeDepth {
+		return 0
+	}
+	if node["Type"] == name("Page") {
+		return 1
+	}
+	kids, _ := d.resolve(node["Kids"]).(array)
+	total := 0
+	for _, kid := range kids {
+		if r, ok := kid.(ref); ok {
+			if visited[r.num] {
+				continue
+			}
+			visited[r.num] = true
+		}
+		if child, ok := d.resolve(kid).(dict); ok {
+			total += d.countLeaves(child, visited, depth+1)
+		}
+	}
+	return total
+}
+
+// text decodifica una cadena de texto de los metadatos: UTF-16BE o UTF-8 si tiene
+// BOM, PDFDocEncoding si no
+func (d *document) text(value object) string {
+	s, ok := d.resolve(value).(pdfString)
+	if !ok {
+		return ""
+	}
+	var out string
+	switch {
+	case bytes.HasPrefix(s, []byte{0xFE, 0xFF}):
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		out = string(utf16.Decode(units))
+	case bytes.HasPrefix(s, []byte{0xEF, 0xBB, 0xBF}):
+		out = strings.ToValidUTF8(string(s[3:]), "")
+	default:
+		runes := make([]rune, len(s))
+		for i, b := range s {
+			runes[i] = pdfDocRune(b)
+		}
+		out = string(runes)
+	}
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7F || r == 0xFFFD {
+			return -1
+		}
+		return r
+	}, out))
+}
+
+// pdfDocDiffs son los caracteres de PDFDocEncoding que difieren de Latin-1
+var pdfDocDiffs = map[byte]rune{
+	0x18: '˘', 0x19: 'ˇ', 0x1A: 'ˆ', 0x1B: '˙', 0x1C: '˝', 0x1D: '˛', 0x1E: '˚', 0x1F: '˜',
+	0x80: '•', 0x81: '†', 0x82: '‡', 0x83: '…', 0x84: '—', 0x85: '–', 0x86: 'ƒ', 0x87: '⁄',
+	0x88: '‹', 0x89: '›', 0x8A: '−', 0x8B: '‰', 0x8C: '„', 0x8D: '“', 0x8E: '”', 0x8F: '‘',
+	0x90: '’', 0x91: '‚', 0x92: '™', 0x93: 'ﬁ', 0x94: 'ﬂ', 0x95: 'Ł', 0x96: 'Œ', 0x97: 'Š',
+	0x98: 'Ÿ', 0x99: 'Ž', 0x9A: 'ı', 0x9B: 'ł', 0x9C: 'œ', 0x9D: 'š', 0x9E: 'ž', 0xA0: '€',
+}
+
+func pdfDocRune(b byte) rune {
+	if r, ok := pdfDocDiffs[b]; ok {
+		return r
+	}
+	return rune(b)
+}
@@ -0,0 +1,189 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// generatePDF arma un PDF de pages páginas con gofpdf (tabla xref clásica)
+func generatePDF(tb testing.TB, pages int, protect bool) []byte {
+	tb.Helper()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetProducer("Generador de prueba", false)
+	pdf.SetTitle("Currículum de José", true)
+	if protect {
+		pdf.SetProtection(gofpdf.CnProtectPrint, "", "propietario")
+	}
+	pdf.SetFont("Helvetica", "", 12)
+	for i := 0; i < pages; i++ {
+		pdf.AddPage()
+		pdf.Cell(40, 10, fmt.Sprintf("Página %d", i+1))
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
+
+// compressedPDF arma a mano un PDF 1.5 con el catálogo, el árbol de páginas y los
+// metadatos dentro de un stream de objetos, y un stream xref con predictor PNG,
+// como los que exportan Word o LibreOffice
+func compressedPDF() []byte {
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.5\n%\xE2\xE3\xCF\xD3\n")
+	offsets := map[int]int{}
+
+	// Objetos 1 (catálogo), 2 (páginas), 4 (info) en el stream de objetos 5
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Producer <FEFF004C0069006200720065004F0066006600690063006500200037002E0035> /Title (CV \\(2024\\)) >>",
+	}
+	nums := []int{1, 2, 4}
+	var header, body bytes.Buffer
+	for i, obj := range objects {
+		fmt.Fprintf(&header, "%d %d ", nums[i], body.Len())
+		body.WriteString(obj + "\n")
+	}
+	objStm := deflate(append(header.Bytes(), body.Bytes()...))
+
+	offsets[3] = out.Len()
+	out.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] >>\nendobj\n")
+	offsets[5] = out.Len()
+	fmt.Fprintf(&out, "5 0 obj\n<< /Type /ObjStm /N 3 /First %d /Length %d /Filter /FlateDecode >>\nstream\n", header.Len(), len(objStm))
+	out.Write(objStm)
+	out.WriteString("\nendstream\nendobj\n")
+
+	// Filas de 1 + 4 + 2 bytes, codificadas con el predictor Up
+	xrefOffset := out.Len()
+	rows := [][3]int{{0, 0, 0}, {2, 5, 0}, {2, 5, 1}, {1, offsets[3], 0}, {2, 5, 2}, {1, offsets[5], 0}, {1, xrefOffset, 0}}
+	var raw []byte
+	prev := make([]byte, 7)
+	for _, row := range rows {
+		fields := []byte{byte(row[0])}
+		fields = binary.BigEndian.AppendUint32(fields, uint32(row[1]))
+		fields = binary.BigEndian.AppendUint16(fields, uint16(row[2]))
+		raw = append(raw, 2)
+		for i := range fields {
+			raw = append(raw, fields[i]-prev[i])
+		}
+		prev = fields
+	}
+	xref := deflate(raw)
+	fmt.Fprintf(&out, "6 0 obj\n<< /Type /XRef /Size 7 /W [1 4 2] /Root 1 0 R /Info 4 0 R /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 7 >> /Length %d >>\nstream\n", len(xref))
+	out.Write(xref)
+	fmt.Fprintf(&out, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return out.Bytes()
+}
+
+func inspect(content []byte) (*Info, error) {
+	return Inspect(bytes.NewReader(content), int64(len(content)))
+}
+
+func TestInspectGeneratedPDF(t *testing.T) {
+	info, err := inspect(generatePDF(t, 3, false))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Pages != 3 || info.Encrypted || info.Version != "1.3" {
+		t.Errorf("info inesperada: %+v", info)
+	}
+	if info.Producer != "Generador de prueba" || info.Title != "Currículum de José" {
+		t.Errorf("metadatos: producer=%q title=%q", info.Producer, info.Title)
+	}
+}
+
+func TestInspectCompressedPDF(t *testing.T) {
+	info, err := inspect(compressedPDF())
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if info.Pages != 1 || info.Version != "1.5" || info.Producer != "LibreOffice 7.5" || info.Title != "CV (2024)" {
+		t.Errorf("info inesperada: %+v", info)
+	}
+}
+
+func TestInspectEncryptedPDF(t *testing.T) {
+	info, err := inspect(generatePDF(t, 2, true))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if !info.Encrypted || info.Pages != 2 || info.Producer != "" {
+		t.Errorf("info inesperada: %+v", info)
+	}
+}
+
+func TestInspectRebuildsBrokenXref(t *testing.T) {
+	content := generatePDF(t, 2, false)
+	// Una basura delante corre todos los offsets de la tabla xref
+	shifted := append([]byte("basura de un cliente de correo\n"), content...)
+	info, err := inspect(shifted)
+	if err != nil || info.Pages != 2 {
+		t.Errorf("con basura inicial: info=%+v err=%v", info, err)
+	}
+
+	// Un startxref que no apunta a la tabla obliga a reconstruirla
+	broken := bytes.Replace(content, []byte("startxref\n"), []byte("startxref\n9"), 1)
+	info, err = inspect(broken)
+	if err != nil || info.Pages != 2 || info.Producer != "Generador de prueba" {
+		t.Errorf("con startxref roto: info=%+v err=%v", info, err)
+	}
+}
+
+func TestInspectRejectsInvalidFiles(t *testing.T) {
+	content := generatePDF(t, 1, false)
+	tests := []struct {
+		name    string
+		content []byte
+		want    error
+	}{
+		{"no es PDF", []byte("Hola, soy un CV en texto plano"), ErrNotPDF},
+		{"vacío", nil, ErrNotPDF},
+		{"truncado", content[:len(content)/2], ErrTruncated},
+		{"sin startxref", bytes.Replace(content, []byte("startxref"), []byte("xxxxxxxxx"), 1), ErrTruncated},
+		{"sin catálogo", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Page >>\nendobj\nstartxref\n0\n%%EOF\n"), ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := inspect(tt.content); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspectCountsLeavesWithoutCount(t *testing.T) {
+	content := []byte("%PDF-1.4\n" +
+		"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+		"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R 2 0 R] >> endobj\n" +
+		"3 0 obj << /Type /Page /Parent 2 0 R >> endobj\n" +
+		"4 0 obj << /Type /Pages /Kids [5 0 R 6 0 R] >> endobj\n" +
+		"5 0 obj << /Type /Page >> endobj\n" +
+		"6 0 obj << /Type /Page >> endobj\n" +
+		"trailer << /Root 1 0 R >>\nstartxref\n0\n%%EOF\n")
+	info, err := inspect(content)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	// La referencia circular a 2 0 R no se cuenta
+	if info.Pages != 3 {
+		t.Errorf("páginas = %d, se esperaba 3", info.Pages)
+	}
+}
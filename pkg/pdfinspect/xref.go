@@ -0,0 +1,475 @@
+package pdfinspect
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// maxDecodedStream limita lo que se descomprime de un stream de xref u objetos,
+// para no caer en un PDF diseñado como bomba de compresión
+const maxDecodedStream = 32 << 20
+
+// maxResolveDepth limita las referencias encadenadas al resolver un objeto
+const maxResolveDepth = 32
+
+// xrefEntry ubica un objeto: por su offset en el archivo o, si inStream, por su
+// índice dentro de un stream de objetos
+type xrefEntry struct {
+	offset    int64
+	inStream  bool
+	streamNum int64
+	index     int
+}
+
+// document es un PDF cargado en memoria con su tabla de referencias cruzadas
+type document struct {
+	data    []byte
+	base    int64 // Posición del encabezado %PDF; algunos archivos traen basura antes
+	xref    map[int64]xrefEntry
+	trailer dict
+	cache   map[int64]object
+	objStms map[int64][]object
+	loading map[int64]bool
+}
+
+func newDocument(data []byte, base int64) *document {
+	return &document{
+		data:    data,
+		base:    base,
+		xref:    map[int64]xrefEntry{},
+		cache:   map[int64]object{},
+		objStms: map[int64][]object{},
+		loading: map[int64]bool{},
+	}
+}
+
+// loadXref recorre la cadena de tablas de referencias cruzadas desde la última
+// (startxref) hacia las anteriores (/Prev). Las entradas y el trailer más nuevos
+// tienen prioridad.
+func (d *document) loadXref(offset int64) error {
+	visited := map[int64]bool{}
+	for {
+		if visited[offset] {
+			return nil
+		}
+		visited[offset] = true
+
+		trailer, err := d.readXrefSection(offset)
+		if err != nil {
+			return err
+		}
+		if d.trailer == nil {
+			d.trailer = trailer
+		}
+		// Archivos híbridos: la tabla clásica apunta también a un stream de xref
+		if stm, ok := trailer["XRefStm"].(int64); ok && !visited[stm] {
+			visited[stm] = true
+			if _, err := d.readXrefSection(stm); err != nil {
+				return err
+			}
+		}
+
+		prev, ok := trailer["Prev"].(int64)
+		if !ok {
+			return nil
+		}
+		offset = prev
+	}
+}
+
+// readXrefSection lee la sección de xref en offset. Si no se encuentra ahí, se
+// prueba relativo al encabezado, como lo escriben los archivos con basura inicial.
+func (d *document) readXrefSection(offset int64) (dict, error) {
+	trailer, err := d.readXrefAt(offset)
+	if err != nil && d.base > 0 {
+		if relative, relErr := d.readXrefAt(offset + d.base); relErr == nil {
+			return relative, nil
+		}
+	}
+	return trailer, err
+}
+
+func (d *document) readXrefAt(offset int64) (dict, error) {
+	if offset < 0 || offset >= int64(len(d.data)) {
+		return nil, fmt.Errorf("%w: startxref fuera del archivo", ErrMalformed)
+	}
+	p := &parser{data: d.data, pos: int(offset)}
+	if p.hasKeyword("xref") {
+		return d.readXrefTable(p)
+	}
+	return d.readXrefStream(p)
+}
+
+// readXrefTable lee una tabla clásica: subsecciones "inicio cantidad" con entradas
+// "offset generación n|f", seguidas del trailer
+func (d *document) readXrefTable(p *parser) (dict, error) {
+	for !p.hasKeyword("trailer") {
+		start, err1 := strconv.ParseInt(p.keyword(), 10, 64)
+		count, err2 := strconv.ParseInt(p.keyword(), 10, 64)
+		if err1 != nil || err2 != nil || start < 0 || count < 0 {
+			return nil, fmt.Errorf("%w: tabla xref inválida", ErrMalformed)
+		}
+		for i := int64(0); i < count; i++ {
+			offset, err1 := strconv.ParseInt(p.keyword(), 10, 64)
+			_, err2 := strconv.ParseInt(p.keyword(), 10, 64)
+			kind := p.keyword()
+			if err1 != nil || err2 != nil || (kind != "n" && kind != "f") {
+				return nil, fmt.Errorf("%w: entrada xref inválida", ErrMalformed)
+			}
+			if _, seen := d.xref[start+i]; !seen && kind == "n" {
+				d.xref[start+i] = xrefEntry{offset: offset}
+			}
+		}
+	}
+
+	trailer, err := p.parseObject()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := trailer.(dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: trailer inválido", ErrMalformed)
+	}
+	return t, nil
+}
+
+// readXrefStream lee un stream de referencias cruzadas (PDF 1.5+). Su diccionario
+// hace también de trailer.
+func (d *document) readXrefStream(p *parser) (dict, error) {
+	_, value, err := p.parseIndirect()
+	if err != nil {
+		return nil, err
+	}
+	s, ok := value.(*stream)
+	if !ok || s.dict["Type"] != name("XRef") {
+		return nil, fmt.Errorf("%w: startxref no apunta a una tabla xref", ErrMalformed)
+	}
+	data, err := decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+
+	widths, ok := s.dict["W"].(array)
+	if !ok || len(widths) != 3 {
+		return nil, fmt.Errorf("%w: /W inválido en el stream xref", ErrMalformed)
+	}
+	var w [3]int
+	rowSize := 0
+	for i, v := range widths {
+		n, ok := v.(int64)
+		if !ok || n < 0 || n > 8 {
+			return nil, fmt.Errorf("%w: /W inválido en el stream xref", ErrMalformed)
+		}
+		w[i] = int(n)
+		rowSize += int(n)
+	}
+	if rowSize == 0 {
+		return nil, fmt.Errorf("%w: /W inválido en el stream xref", ErrMalformed)
+	}
+
+	// /Index lista pares "inicio cantidad"; por omisión es [0 Size]
+	index, _ := s.dict["Index"].(array)
+	if index == nil {
+		size, _ := s.dict["Size"].(int64)
+		index = array{int64(0), size}
+	}
+
+	row := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, ok1 := index[i].(int64)
+		count, ok2 := index[i+1].(int64)
+		if !ok1 || !ok2 || start < 0 || count < 0 {
+			return nil, fmt.Errorf("%w: /Index inválido en el stream xref", ErrMalformed)
+		}
+		for j := int64(0); j < count; j++ {
+			if (row+1)*rowSize > len(data) {
+				return s.dict, nil
+			}
+			fields := data[row*rowSize : (row+1)*rowSize]
+			row++
+
+			kind := int64(1) // Si el primer campo tiene ancho 0, el tipo es 1
+			if w[0] > 0 {
+				kind = readField(fields[:w[0]])
+			}
+			second := readField(fields[w[0] : w[0]+w[1]])
+			third := readField(fields[w[0]+w[1]:])
+
+			if _, seen := d.xref[start+j]; seen {
+				continue
+			}
+			switch kind {
+			case 1:
+				d.xref[start+j] = xrefEntry{offset: second}
+			case 2:
+				d.xref[start+j] = xrefEntry{inStream: true, streamNum: second, index: int(third)}
+			}
+		}
+	}
+	return s.dict, nil
+}
+
+// readField lee un entero big endian de ancho variable
+func readField(b []byte) int64 {
+	var v int64
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// objectPattern encuentra el inicio de los objetos al reconstruir la tabla xref
+var objectPattern = regexp.MustCompile(`(?m)(?:^|[\r\n\s])(\d+)\s+(\d+)\s+obj\b`)
+
+// rebuildXref reconstruye la tabla recorriendo los objetos del archivo, para PDFs
+// con una tabla xref dañada pero el contenido intacto. Los objetos que aparecen
+// después reemplazan a los anteriores, como en una actualización incremental.
+func (d *document) rebuildXref() error {
+	d.xref = map[int64]xrefEntry{}
+	d.cache = map[int64]object{}
+	d.objStms = map[int64][]object{}
+	d.trailer = nil
+
+	for _, m := range objectPattern.FindAllSubmatchIndex(d.data, -1) {
+		num, err := strconv.ParseInt(string(d.data[m[2]:m[3]]), 10, 64)
+		if err != nil {
+			continue
+		}
+		d.xref[num] = xrefEntry{offset: int64(m[2])}
+	}
+
+	// El último trailer clásico o, si no hay, el último stream xref
+	if i := bytes.LastIndex(d.data, []byte("trailer")); i >= 0 {
+		p := &parser{data: d.data, pos: i + len("trailer")}
+		if t, err := p.parseObject(); err == nil {
+			d.trailer, _ = t.(dict)
+		}
+	}
+	if d.trailer == nil {
+		lastXref := int64(-1)
+		for num, entry := range d.xref {
+			if s, ok := d.load(num).(*stream); ok && s.dict["Type"] == name("XRef") && entry.offset > lastXref {
+				d.trailer, lastXref = s.dict, entry.offset
+			}
+		}
+		if d.trailer != nil {
+			// Los objetos dentro de streams de objetos solo figuran en el stream xref
+			rebuilt := d.xref
+			d.xref = map[int64]xrefEntry{}
+			if _, err := d.readXrefStream(&parser{data: d.data, pos: int(lastXref)}); err == nil {
+				for num, entry := range d.xref {
+					if _, ok := rebuilt[num]; !ok || entry.inStream {
+						rebuilt[num] = entry
+					}
+				}
+			}
+			d.xref = rebuilt
+			d.cache = map[int64]object{}
+		}
+	}
+	if d.trailer == nil {
+		return fmt.Errorf("%w: no se encontró el trailer", ErrMalformed)
+	}
+	return nil
+}
+
+// resolve retorna el objeto al que apunta una referencia, o el mismo valor si no
+// es una referencia. Los objetos inexistentes valen null.
+func (d *document) resolve(value object) object {
+	for depth := 0; depth < maxResolveDepth; depth++ {
+		r, ok := value.(ref)
+		if !ok {
+			return value
+		}
+		value = d.load(r.num)
+	}
+	return nil
+}
+
+// load lee el objeto indirecto num, usando la caché
+func (d *document) load(num int64) object {
+	if value, ok := d.cache[num]; ok {
+		return value
+	}
+	entry, ok := d.xref[num]
+	if !ok || d.loading[num] {
+		return nil
+	}
+	d.loading[num] = true
+	defer delete(d.loading, num)
+
+	var value object
+	if entry.inStream {
+		objects := d.objectStream(entry.streamNum)
+		if entry.index >= 0 && entry.index < len(objects) {
+			value = objects[entry.index]
+		}
+	} else {
+		value = d.loadAt(num, entry.offset)
+		if value == nil && d.base > 0 {
+			value = d.loadAt(num, entry.offset+d.base)
+		}
+	}
+	d.cache[num] = value
+	return value
+}
+
+func (d *document) loadAt(num, offset int64) object {
+	if offset < 0 || offset >= int64(len(d.data)) {
+		return nil
+	}
+	p := &parser{data: d.data, pos: int(offset), streamLength: d.streamLength}
+	got, value, err := p.parseIndirect()
+	if err != nil || got != num {
+		return nil
+	}
+	return value
+}
+
+// streamLength resuelve un /Length indirecto
+func (d *document) streamLength(value object) (int64, bool) {
+	n, ok := d.resolve(value).(int64)
+	return n, ok
+}
+
+// objectStream decodifica un stream de objetos (/Type /ObjStm): /N objetos cuyos
+// números y offsets están al principio y los valores a partir de /First
+func (d *document) objectStream(num int64) []object {
+	if objects, ok := d.objStms[num]; ok {
+		return objects
+	}
+	d.objStms[num] = nil
+
+	s, ok := d.load(num).(*stream)
+	if !ok || s.dict["Type"] != name("ObjStm") {
+		return nil
+	}
+	data, err := decodeStream(s)
+	if err != nil {
+		return nil
+	}
+	n, _ := s.dict["N"].(int64)
+	first, _ := s.dict["First"].(int64)
+	if n <= 0 || first < 0 || first > int64(len(data)) {
+		return nil
+	}
+
+	header := &parser{data: data}
+	objects := make([]object, 0, min(n, 10000))
+	for i := int64(0); i < n; i++ {
+		_, err1 := strconv.ParseInt(header.keyword(), 10, 64)
+		offset, err2 := strconv.ParseInt(header.keyword(), 10, 64)
+		if err1 != nil || err2 != nil || first+offset >= int64(len(data)) {
+			break
+		}
+		p := &parser{data: data, pos: int(first + offset)}
+		value, err := p.parseObject()
+		if err != nil {
+			value = nil
+		}
+		objects = append(objects, value)
+	}
+	d.objStms[num] = objects
+	return objects
+}
+
+// decodeStream descomprime los datos de un stream. Solo hace falta FlateDecode
+// (con o sin predictor PNG), que es lo que usan los streams de xref y de objetos.
+func decodeStream(s *stream) ([]byte, error) {
+	filter := s.dict["Filter"]
+	if filters, ok := filter.(array); ok && len(filters) == 1 {
+		filter = filters[0]
+	}
+	switch filter {
+	case nil:
+		return s.data, nil
+	case name("FlateDecode"):
+	default:
+		return nil, fmt.Errorf("%w: filtro de stream no soportado: %v", ErrMalformed, filter)
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(s.data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: stream comprimido inválido: %v", ErrMalformed, err)
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(io.LimitReader(zr, maxDecodedStream+1))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: stream comprimido inválido: %v", ErrMalformed, err)
+	}
+	if len(data) > maxDecodedStream {
+		return nil, fmt.Errorf("%w: stream demasiado grande", ErrMalformed)
+	}
+
+	params, _ := s.dict["DecodeParms"].(dict)
+	if params == nil {
+		if list, ok := s.dict["DecodeParms"].(array); ok && len(list) == 1 {
+			params, _ = list[0].(dict)
+		}
+	}
+	predictor, _ := params["Predictor"].(int64)
+	if predictor < 10 {
+		return data, nil
+	}
+	columns, ok := params["Columns"].(int64)
+	if !ok {
+		columns = 1
+	}
+	return unpredictPNG(data, int(columns))
+}
+
+// unpredictPNG deshace el predictor PNG por filas: cada fila empieza con el tipo
+// de filtro seguido de columns bytes
+func unpredictPNG(data []byte, columns int) ([]byte, error) {
+	if columns <= 0 || columns > 1<<16 {
+		return nil, fmt.Errorf("%w: predictor con columnas inválidas", ErrMalformed)
+	}
+	rowSize := columns + 1
+	out := make([]byte, 0, len(data)/rowSize*columns)
+	prev := make([]byte, columns)
+	for i := 0; i+rowSize <= len(data); i += rowSize {
+		filter, row := data[i], append([]byte(nil), data[i+1:i+rowSize]...)
+		for j := range row {
+			var left, upLeft byte
+			if j > 0 {
+				left, upLeft = row[j-1], prev[j-1]
+			}
+			switch filter {
+			case 1: // Sub
+				row[j] += left
+			case 2: // Up
+				row[j] += prev[j]
+			case 3: // Average
+				row[j] += byte((int(left) + int(prev[j])) / 2)
+			case 4: // Paeth
+				row[j] += paeth(left, prev[j], upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"resume-backend-service/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalDownloadPath es la ruta del servicio que sirve las URLs firmadas del driver local
+const LocalDownloadPath = "/api/v1/storage/"
+
+// metaSuffix es la extensión del archivo con el tipo y los metadatos de cada objeto
+const metaSuffix = ".meta.json"
+
+// ErrInvalidSignature indica una URL de descarga con firma inválida o vencida
+var ErrInvalidSignature = errors.New("firma de URL inválida o vencida")
+
+// LocalStorage guarda los objetos en un directorio: cada clave es una ruta relativa
+// y sus metadatos van en un archivo <clave>.meta.json al lado. Las URLs firmadas
+// apuntan a este mismo servicio (LocalDownloadPath) con una firma HMAC.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+	secret  string
+	now     func() time.Time
+}
+
+// localObjectMeta es el contenido del archivo de metadatos
+type localObjectMeta struct {
+	ContentType string   `json:"content_type"`
+	Metadata    Metadata `json:"metadata,omitempty"`
+}
+
+// NewLocalStorage crea el directorio si no existe. baseURL es la URL pública del
+// servicio (ej: http://localhost:8080). Sin secret se genera uno aleatorio: las URLs
+// firmadas dejan de valer al reiniciar.
+func NewLocalStorage(dir, baseURL, secret string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error al crear directorio de almacenamiento: %w", err)
+	}
+
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("error al generar secreto de firma: %w", err)
+		}
+		secret = hex.EncodeToString(random)
+		log.Println("⚠️  STORAGE_LOCAL_SIGNING_SECRET no configurado: las URLs de descarga dejan de valer al reiniciar")
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  secret,
+		now:     time.Now,
+	}, nil
+}
+
+// path retorna la ruta en disco de la clave
+func (s *LocalStorage) path(key string) (string, string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	if strings.HasSuffix(key, metaSuffix) {
+		return "", "", ErrInvalidKey
+	}
+	return key, filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put escribe el objeto en un temporal y lo renombra al terminar, así una lectura
+// concurrente nunca ve un archivo a medias. Retorna la URL file:// del archivo.
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string, metadata Metadata) (string, error) {
+	_, objectPath, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0o700); err != nil {
+		return "", fmt.Errorf("error al crear directorio del objeto: %w", err)
+	}
+
+	meta, err := json.Marshal(localObjectMeta{ContentType: contentType, Metadata: metadata})
+	if err != nil {
+		return "", fmt.Errorf("error al serializar metadatos: %w", err)
+	}
+	if err := writeFileAtomic(objectPath+metaSuffix, strings.NewReader(string(meta)), int64(len(meta))); err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(objectPath, body, size); err != nil {
+		os.Remove(objectPath + metaSuffix)
+		return "", err
+	}
+
+	absPath, err := filepath.Abs(objectPath)
+	if err != nil {
+		absPath = objectPath
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String(), nil
+}
+
+// writeFileAtomic copia size bytes de body a un temporal junto a destPath y lo renombra
+func writeFileAtomic(destPath string, body io.Reader, size int64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error al crear archivo temporal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(body, size))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error al escribir objeto: %w", err)
+	}
+	if written != size {
+		return fmt.Errorf("error al escribir objeto: se recibieron %d de %d bytes", written, size)
+	}
+	if err := os.Rename(tmp.Name(), destPath); err != nil {
+		return fmt.Errorf("error al guardar objeto: %w", err)
+	}
+	return nil
+}
+
+// Get abre el archivo del objeto
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	_, objectPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(objectPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir objeto: %w", err)
+	}
+	return file, nil
+}
+
+// Delete borra el objeto y sus metadatos
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	_, objectPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{objectPath, objectPath + metaSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error al borrar objeto: %w", err)
+		}
+	}
+	return nil
+}
+
+// SignedURL retorna la URL de descarga del objeto en este servicio. La firma es la
+// misma del callback de Lambda (HMAC-SHA256 de "<vencimiento>.<clave>").
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	key, objectPath, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(objectPath); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+
+	expiresAt := s.now().Add(expires).Unix()
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expiresAt, 10)},
+		"signature": {utils.ComputeHMACSignature(s.secret, expiresAt, []byte(key))},
+	}
+	return s.baseURL + LocalDownloadPath + strings.Join(segments, "/") + "?" + query.Encode(), nil
+}
+
+// VerifySignature valida la firma y el vencimiento de una URL de SignedURL
+func (s *LocalStorage) VerifySignature(key, expires, signature string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+	if !utils.VerifyHMACSignature(s.secret, expiresAt, []byte(key), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ContentType retorna el tipo guardado con el objeto, o "" si no se conoce
+func (s *LocalStorage) ContentType(key string) string {
+	_, objectPath, err := s.path(key)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(objectPath + metaSuffix)
+	if err != nil {
+		return ""
+	}
+	var meta localObjectMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return ""
+	}
+	return meta.ContentType
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStoragePutGetDelete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStorage(dir, "http://localhost:8080/", "secreto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	const content = "%PDF-1.4 contenido"
+	location, err := store.Put(ctx, "req-1/cv.pdf", strings.NewReader(content), int64(len(content)), "application/pdf", Metadata{MetaRequestID: "req-1"})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if want := "file://" + filepath.ToSlash(filepath.Join(dir, "req-1", "cv.pdf")); location != want {
+		t.Errorf("URL = %q, se esperaba %q", location, want)
+	}
+	if store.ContentType("req-1/cv.pdf") != "application/pdf" {
+		t.Errorf("ContentType = %q", store.ContentType("req-1/cv.pdf"))
+	}
+
+	reader, err := store.Get(ctx, "req-1/cv.pdf")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(reader)
+	reader.Close()
+	if string(got) != content {
+		t.Errorf("contenido = %q", got)
+	}
+
+	if err := store.Delete(ctx, "req-1/cv.pdf"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "req-1/cv.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get después de Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "req-1/cv.pdf"); err != nil {
+		t.Errorf("borrar un objeto inexistente no es un error: %v", err)
+	}
+}
+
+func TestLocalStorageRejectsIncompleteAndInvalidPuts(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewLocalStorage(dir, "http://localhost:8080", "secreto")
+	ctx := context.Background()
+
+	if _, err := store.Put(ctx, "req-1/cv.pdf", strings.NewReader("corto"), 100, "application/pdf", nil); err == nil {
+		t.Error("se esperaba error si el contenido es más corto que size")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "req-1", "cv.pdf")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("una subida incompleta no debe dejar el objeto")
+	}
+
+	for _, key := range []string{"", "../fuera.pdf", "/abs.pdf", "a/../../fuera.pdf", `a\b.pdf`, "cv.pdf.meta.json"} {
+		if _, err := store.Put(ctx, key, strings.NewReader("x"), 1, "application/pdf", nil); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("clave %q: se esperaba ErrInvalidKey, se obtuvo %v", key, err)
+		}
+	}
+}
+
+func TestLocalStorageSignedURL(t *testing.T) {
+	store, _ := NewLocalStorage(t.TempDir(), "http://localhost:8080", "secreto")
+	now := time.Unix(1_700_000_000, 0)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := store.SignedURL(ctx, "req-1/mi cv.pdf", time.Minute); !errors.Is(err, ErrNotFound) {
+		t.Errorf("se esperaba ErrNotFound, se obtuvo %v", err)
+	}
+	store.Put(ctx, "req-1/mi cv.pdf", strings.NewReader("x"), 1, "application/pdf", nil)
+
+	signed, err := store.SignedURL(ctx, "req-1/mi cv.pdf", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	parsed, err := url.Parse(signed)
+	if err != nil || parsed.Host != "localhost:8080" || parsed.Path != LocalDownloadPath+"req-1/mi cv.pdf" {
+		t.Fatalf("URL inesperada: %s", signed)
+	}
+	expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+
+	if err := store.VerifySignature("req-1/mi cv.pdf", expires, signature); err != nil {
+		t.Errorf("VerifySignature: %v", err)
+	}
+	if err := store.VerifySignature("req-1/otro.pdf", expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Error("la firma no debe valer para otra clave")
+	}
+	if err := store.VerifySignature("req-1/mi cv.pdf", "1700000999", signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Error("la firma no debe valer con otro vencimiento")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := store.VerifySignature("req-1/mi cv.pdf", expires, signature); !errors.Is(err, ErrInvalidSignature) {
+		t.Error("una URL vencida no debe valer")
+	}
+}
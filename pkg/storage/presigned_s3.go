@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"resume-backend-service/pkg/client"
+	"resume-backend-service/pkg/httpx"
+	"strings"
+	"time"
+)
+
+// PresignedS3Storage sube los objetos a S3 con URLs firmadas por el servicio de
+// presigned URLs. Ese servicio solo firma subidas, así que Get, Delete y SignedURL
+// retornan ErrNotSupported.
+type PresignedS3Storage struct {
+	presignedURLClient *client.PresignedURLClient
+	httpClient         *httpx.Client
+}
+
+// NewPresignedS3Storage crea el driver sobre el cliente del servicio de presigned URLs.
+// uploadClient hace los PUT a S3 (timeout por intento y reintentos).
+func NewPresignedS3Storage(presignedURLClient *client.PresignedURLClient, uploadClient *httpx.Client) *PresignedS3Storage {
+	return &PresignedS3Storage{
+		presignedURLClient: presignedURLClient,
+		httpClient:         uploadClient,
+	}
+}
+
+// Put obtiene una URL firmada y sube el objeto con un PUT. El servicio de presigned
+// URLs arma la clave en S3 a partir del nombre del archivo (el último segmento de
+// key) y del request_id de los metadatos. Retorna la URL del objeto sin la firma.
+func (s *PresignedS3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string, metadata Metadata) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	// IMPORTANTE: el request_id y los demás metadatos se incluyen en la firma
+	presignedResp, err := s.presignedURLClient.GetUploadURL(
+		ctx,
+		path.Base(key),
+		contentType,
+		metadata[MetaRequestID],
+		metadata[MetaLanguage],
+		metadata[MetaInstructions],
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPresign, err)
+	}
+	log.Printf("URL firmada obtenida exitosamente (expira en: %s)", presignedResp.ExpiresIn)
+
+	if err := s.upload(ctx, presignedResp.URL, body, size, contentType, metadata); err != nil {
+		return "", err
+	}
+	// La URL del objeto es la firmada sin los query params
+	return strings.Split(presignedResp.URL, "?")[0], nil
+}
+
+// upload sube el contenido a la URL firmada como stream. S3 no acepta PUT con
+// transferencia chunked, por eso se requiere el tamaño (Content-Length). Los headers
+// de metadata DEBEN coincidir exactamente con los usados al generar la presigned URL.
+// La subida solo se reintenta si body se puede releer (io.ReaderAt e io.Seeker, como
+// un archivo): cada intento lee su propia sección desde la posición actual.
+func (s *PresignedS3Storage) upload(ctx context.Context, presignedURL string, body io.Reader, size int64, contentType string, metadata Metadata) error {
+	// NopCloser evita que el cliente HTTP cierre el archivo; lo cierra quien lo abrió
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, presignedURL, io.NopCloser(body))
+	if err != nil {
+		return fmt.Errorf("error al crear request de subida: %w", err)
+	}
+	req.ContentLength = size
+	if getBody := rereadableBody(body, size); getBody != nil {
+		req.Body, _ = getBody()
+		req.GetBody = getBody
+	}
+
+	// Headers requeridos - DEBEN coincidir con los metadatos de la presigned URL
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range metadata {
+		if value != "" {
+			req.Header.Set("x-amz-meta-"+name, value)
+		}
+	}
+
+	log.Printf("🔄 Subiendo a S3 - RequestID: %s, Size: %d bytes, Language: %s",
+		metadata[MetaRequestID], size, metadata[MetaLanguage])
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al ejecutar subida: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		log.Printf("❌ S3 Response Status: %d, Headers: %v", resp.StatusCode, resp.Header)
+		return fmt.Errorf("error al subir archivo a S3 (status %d)", resp.StatusCode)
+	}
+
+	log.Printf("✅ S3 Response Status: %d", resp.StatusCode)
+	return nil
+}
+
+// rereadableBody retorna una función que entrega el contenido desde el inicio en cada
+// llamada, o nil si body no se puede releer. Cada intento usa un SectionReader propio,
+// así un intento anterior que el transporte todavía no terminó de cerrar no le mueve
+// la posición al siguiente.
+func rereadableBody(body io.Reader, size int64) func() (io.ReadCloser, error) {
+	readerAt, ok := body.(io.ReaderAt)
+	seeker, isSeeker := body.(io.Seeker)
+	if !ok || !isSeeker {
+		return nil
+	}
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil
+	}
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(readerAt, offset, size)), nil
+	}
+}
+
+// Get no está soportado: el servicio de presigned URLs no firma descargas
+func (s *PresignedS3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+// Delete no está soportado: el servicio de presigned URLs no firma borrados
+func (s *PresignedS3Storage) Delete(ctx context.Context, key string) error {
+	return ErrNotSupported
+}
+
+// SignedURL no está soportado: el servicio de presigned URLs no firma descargas
+func (s *PresignedS3Storage) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return "", ErrNotSupported
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"resume-backend-service/internal/dto"
+	"resume-backend-service/pkg/client"
+	"resume-backend-service/pkg/httpx"
+	"strings"
+	"testing"
+)
+
+// fakeS3 simula el servicio de presigned URLs (POST /presign) y el bucket (PUT /bucket/...)
+func fakeS3(tb testing.TB, handleUpload http.HandlerFunc) *httptest.Server {
+	tb.Helper()
+
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/presign", func(w http.ResponseWriter, r *http.Request) {
+		var req dto.PresignedURLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(dto.PresignedURLResponse{
+			URL:       server.URL + "/bucket/" + req.Metadata.RequestID + "/" + req.Filename + "?X-Amz-Signature=firma",
+			ExpiresIn: "1 hour",
+		})
+	})
+	mux.HandleFunc("/bucket/", handleUpload)
+	server = httptest.NewServer(mux)
+	tb.Cleanup(server.Close)
+	return server
+}
+
+// newPresignedS3Storage crea el driver contra el servidor de prueba, con las mismas
+// opciones para el presign y la subida
+func newPresignedS3Storage(presignURL string, options httpx.Options) *PresignedS3Storage {
+	presignedURLClient := client.NewPresignedURLClient(presignURL, httpx.New("presign", options))
+	return NewPresignedS3Storage(presignedURLClient, httpx.New("s3-upload", options))
+}
+
+func TestPresignedS3PutStreamsWithContentLength(t *testing.T) {
+	const content = "%PDF-1.4 contenido de prueba"
+
+	server := fakeS3(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(content)) || len(r.TransferEncoding) > 0 {
+			t.Errorf("Content-Length=%d Transfer-Encoding=%v", r.ContentLength, r.TransferEncoding)
+		}
+		if string(body) != content {
+			t.Errorf("body = %q", body)
+		}
+		if r.Header.Get("x-amz-meta-request-id") != "req-1" || r.Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if _, ok := r.Header["X-Amz-Meta-Instructions"]; ok {
+			t.Error("las instrucciones vacías no deben enviarse")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	store := newPresignedS3Storage(server.URL+"/presign", httpx.Options{})
+	metadata := Metadata{MetaRequestID: "req-1", MetaLanguage: "esp", MetaInstructions: ""}
+	location, err := store.Put(context.Background(), "req-1/cv.pdf", strings.NewReader(content), int64(len(content)), "application/pdf", metadata)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if location != server.URL+"/bucket/req-1/cv.pdf" {
+		t.Errorf("URL = %q (debe ir sin la firma)", location)
+	}
+}
+
+func TestPresignedS3PutDistinguishesPresignErrors(t *testing.T) {
+	server := fakeS3(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	metadata := Metadata{MetaRequestID: "req-1"}
+
+	store := newPresignedS3Storage(server.URL+"/no-existe", httpx.Options{})
+	if _, err := store.Put(context.Background(), "req-1/cv.pdf", strings.NewReader("x"), 1, "application/pdf", metadata); !errors.Is(err, ErrPresign) {
+		t.Errorf("se esperaba ErrPresign, se obtuvo %v", err)
+	}
+
+	store = newPresignedS3Storage(server.URL+"/presign", httpx.Options{})
+	if _, err := store.Put(context.Background(), "req-1/cv.pdf", strings.NewReader("x"), 1, "application/pdf", metadata); err == nil || errors.Is(err, ErrPresign) {
+		t.Errorf("se esperaba un error de subida, se obtuvo %v", err)
+	}
+
+	if _, err := store.SignedURL(context.Background(), "req-1/cv.pdf", 0); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SignedURL: se esperaba ErrNotSupported, se obtuvo %v", err)
+	}
+}
+
+func TestPresignedS3PutRetriesWithFullBody(t *testing.T) {
+	const content = "%PDF-1.4 contenido de prueba"
+
+	attempts := 0
+	server := fakeS3(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != content {
+			t.Errorf("intento %d: body = %q", attempts, body)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	store := newPresignedS3Storage(server.URL+"/presign", httpx.Options{MaxAttempts: 3})
+	// El contenido se lee desde la posición actual del archivo, no desde el inicio
+	file := strings.NewReader("xx" + content)
+	file.Seek(2, io.SeekStart)
+	if _, err := store.Put(context.Background(), "req-1/cv.pdf", file, int64(len(content)), "application/pdf", Metadata{MetaRequestID: "req-1"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("intentos = %d, se esperaban 2", attempts)
+	}
+}
+
+// BenchmarkPresignedS3Put mide la memoria por subida (B/op): debe mantenerse acotada
+// sin importar el tamaño del archivo
+func BenchmarkPresignedS3Put(b *testing.B) {
+	const size = 10 * 1024 * 1024
+
+	server := fakeS3(b, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	store := newPresignedS3Storage(server.URL+"/presign", httpx.Options{})
+	metadata := Metadata{MetaRequestID: "req-1", MetaLanguage: "esp"}
+	content := strings.NewReader(strings.Repeat("0", size))
+
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		content.Seek(0, io.SeekStart)
+		if _, err := store.Put(context.Background(), "req-1/cv.pdf", content, size, "application/pdf", metadata); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package storage define dónde se guardan los PDF que procesa la Lambda. Hay dos
+// drivers: S3 a través del servicio de presigned URLs (producción) y un directorio
+// local (desarrollo, sin servicios externos).
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Drivers disponibles (STORAGE_DRIVER)
+const (
+	DriverPresignedS3 = "s3presign"
+	DriverLocal       = "local"
+)
+
+// Claves de metadatos que acompañan cada objeto. En S3 viajan como headers
+// x-amz-meta-<clave> y la Lambda los lee de ahí.
+const (
+	MetaRequestID    = "request-id"
+	MetaLanguage     = "language"
+	MetaInstructions = "instructions"
+)
+
+// Metadata son los metadatos de un objeto
+type Metadata map[string]string
+
+var (
+	// ErrNotFound indica que el objeto no existe
+	ErrNotFound = errors.New("objeto no encontrado")
+	// ErrNotSupported indica que el driver no soporta la operación
+	ErrNotSupported = errors.New("operación no soportada por el driver de almacenamiento")
+	// ErrInvalidKey indica una clave vacía o que sale del almacenamiento ("..", absoluta)
+	ErrInvalidKey = errors.New("clave de objeto inválida")
+	// ErrPresign indica que no se pudo obtener la autorización para subir el objeto
+	// (el servicio de presigned URLs falló), a diferencia de un error en la subida
+	ErrPresign = errors.New("error al obtener autorización de subida")
+)
+
+// Storage guarda y recupera objetos por clave (ej: "<request_id>/cv.pdf")
+type Storage interface {
+	// Put guarda size bytes de body con su tipo y metadatos, y retorna la URL del
+	// objeto guardado. body se lee como stream; no se cierra.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string, metadata Metadata) (string, error)
+	// Get abre el objeto para leerlo; quien lo llama debe cerrarlo
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete borra el objeto; borrar uno que no existe no es un error
+	Delete(ctx context.Context, key string) error
+	// SignedURL retorna una URL de descarga del objeto válida durante expires
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// cleanKey valida la clave y la normaliza con separadores "/"
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.Contains(key, "\\") || strings.HasPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
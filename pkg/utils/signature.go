@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// SignaturePrefix es el prefijo del esquema usado en los headers de firma
+const SignaturePrefix = "sha256="
+
+// ComputeHMACSignature calcula la firma HMAC-SHA256 de un payload.
+// El mensaje firmado es "<timestamp>.<body>", de modo que el timestamp
+// queda protegido por la firma y no puede reutilizarse con otro body.
+func ComputeHMACSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMACSignature compara en tiempo constante una firma recibida con la esperada
+func VerifyHMACSignature(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := ComputeHMACSignature(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
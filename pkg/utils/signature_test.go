@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyHMACSignature(t *testing.T) {
+	const secret = "secreto-actual"
+	const timestamp int64 = 1700000000
+	body := []byte(`{"request_id":"abc","status":"completed"}`)
+	valid := ComputeHMACSignature(secret, timestamp, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+		expected  bool
+	}{
+		{"Valid signature", secret, timestamp, body, valid, true},
+		{"Tampered body", secret, timestamp, []byte(`{"request_id":"abc","status":"failed"}`), valid, false},
+		{"Different timestamp", secret, timestamp + 1, body, valid, false},
+		{"Other secret", "secreto-anterior", timestamp, body, valid, false},
+		{"Missing prefix", secret, timestamp, body, strings.TrimPrefix(valid, SignaturePrefix), false},
+		{"Not hex", secret, timestamp, body, SignaturePrefix + "zz", false},
+		{"Uppercase hex", secret, timestamp, body, SignaturePrefix + strings.ToUpper(strings.TrimPrefix(valid, SignaturePrefix)), false},
+		{"Empty signature", secret, timestamp, body, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := VerifyHMACSignature(tt.secret, tt.timestamp, tt.body, tt.signature)
+			if result != tt.expected {
+				t.Errorf("VerifyHMACSignature(%q) = %v, expected %v", tt.signature, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComputeHMACSignatureFormat(t *testing.T) {
+	signature := ComputeHMACSignature("secreto", 1700000000, []byte("body"))
+	if !strings.HasPrefix(signature, SignaturePrefix) || len(signature) != len(SignaturePrefix)+64 {
+		t.Errorf("ComputeHMACSignature = %q, expected sha256=<64 hex>", signature)
+	}
+}
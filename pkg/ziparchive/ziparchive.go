@@ -0,0 +1,237 @@
+// Package ziparchive extrae de forma segura los archivos de un ZIP recibido de un
+// usuario: nunca usa las rutas del archivo para escribir en disco (path traversal) y
+// limita la cantidad de entradas, el tamaño descomprimido real y la tasa de compresión
+// (zip bombs).
+package ziparchive
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Errores que rechazan el ZIP completo
+var (
+	ErrNotZip           = errors.New("el archivo no es un ZIP válido")
+	ErrTooManyFiles     = errors.New("el ZIP tiene demasiados archivos")
+	ErrTooLarge         = errors.New("el contenido descomprimido del ZIP es demasiado grande")
+	ErrCompressionRatio = errors.New("el ZIP tiene una tasa de compresión sospechosa")
+	ErrUnsafePath       = errors.New("el ZIP contiene rutas inseguras")
+)
+
+// Limits acota lo que se extrae de un ZIP (0 = sin límite)
+type Limits struct {
+	// MaxFiles es la cantidad máxima de archivos extraíbles
+	MaxFiles int
+	// MaxFileSize es el tamaño descomprimido máximo de cada archivo; los más grandes se
+	// omiten (ver Result.Skipped) sin rechazar el ZIP
+	MaxFileSize int64
+	// MaxTotalSize es el tamaño descomprimido máximo de todos los archivos juntos
+	MaxTotalSize int64
+	// MaxCompressionRatio es la relación máxima entre el tamaño descomprimido y el
+	// comprimido de cada archivo
+	MaxCompressionRatio int64
+}
+
+// File es un archivo extraído
+type File struct {
+	// Name es el nombre del archivo sin directorios (ej: "cv.pdf")
+	Name string
+	// Path es la ruta en disco; no deriva del nombre dentro del ZIP
+	Path string
+	Size int64
+}
+
+// Skipped es un archivo del ZIP que no se extrajo
+type Skipped struct {
+	Name   string
+	Reason string
+}
+
+// Result son los archivos extraídos, en el orden del ZIP, y los omitidos
+type Result struct {
+	Files   []File
+	Skipped []Skipped
+}
+
+// Extract extrae los archivos del ZIP en destDir (que debe existir) como 001<ext>,
+// 002<ext>... Se ignoran los directorios y los archivos de sistema (__MACOSX, .DS_Store
+// y otros ocultos). Si el ZIP se rechaza, no queda nada extraído en destDir.
+func Extract(r io.ReaderAt, size int64, destDir string, limits Limits) (*Result, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrNotZip
+	}
+
+	entries, skipped, err := selectEntries(archive, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Skipped: skipped}
+	var total int64
+	for i, entry := range entries {
+		name := path.Base(entryName(entry))
+		destPath := filepath.Join(destDir, fmt.Sprintf("%03d%s", i+1, strings.ToLower(path.Ext(name))))
+
+		written, err := extractFile(entry, destPath, limits.MaxFileSize)
+		if errors.Is(err, errFileTooLarge) {
+			result.Skipped = append(result.Skipped, Skipped{Name: name, Reason: "supera el tamaño máximo por archivo"})
+			continue
+		}
+		if err != nil {
+			removeFiles(result.Files)
+			if errors.Is(err, zip.ErrChecksum) || errors.Is(err, zip.ErrAlgorithm) || errors.Is(err, zip.ErrFormat) {
+				return nil, ErrNotZip
+			}
+			return nil, err
+		}
+
+		total += written
+		if limits.MaxTotalSize > 0 && total > limits.MaxTotalSize {
+			os.Remove(destPath)
+			removeFiles(result.Files)
+			return nil, ErrTooLarge
+		}
+		result.Files = append(result.Files, File{Name: name, Path: destPath, Size: written})
+	}
+
+	return result, nil
+}
+
+// selectEntries valida las rutas y los tamaños declarados de todas las entradas antes
+// de extraer ninguna, y retorna las que se deben extraer
+func selectEntries(archive *zip.Reader, limits Limits) ([]*zip.File, []Skipped, error) {
+	var entries []*zip.File
+	var skipped []Skipped
+	var declaredTotal uint64
+
+	for _, entry := range archive.File {
+		name := entryName(entry)
+		if !safePath(name) {
+			return nil, nil, ErrUnsafePath
+		}
+		if entry.FileInfo().IsDir() || ignoredPath(name) {
+			continue
+		}
+
+		base := path.Base(name)
+		switch {
+		case !entry.Mode().IsRegular():
+			skipped = append(skipped, Skipped{Name: base, Reason: "no es un archivo regular"})
+			continue
+		case entry.Flags&0x1 != 0:
+			skipped = append(skipped, Skipped{Name: base, Reason: "está protegido con contraseña"})
+			continue
+		}
+
+		// Las tasas absurdas delatan una zip bomb aunque los tamaños declarados mientan
+		// poco; los tamaños reales se controlan de nuevo al extraer
+		if limits.MaxCompressionRatio > 0 && entry.UncompressedSize64 > 1024*1024 &&
+			entry.UncompressedSize64/max(entry.CompressedSize64, 1) > uint64(limits.MaxCompressionRatio) {
+			return nil, nil, ErrCompressionRatio
+		}
+
+		declaredTotal += entry.UncompressedSize64
+		entries = append(entries, entry)
+	}
+
+	if limits.MaxFiles > 0 && len(entries) > limits.MaxFiles {
+		return nil, nil, ErrTooManyFiles
+	}
+	if limits.MaxTotalSize > 0 && declaredTotal > uint64(limits.MaxTotalSize) {
+		return nil, nil, ErrTooLarge
+	}
+	return entries, skipped, nil
+}
+
+var errFileTooLarge = errors.New("archivo demasiado grande")
+
+// extractFile copia la entrada a destPath leyendo como máximo maxSize bytes: el tamaño
+// declarado en el ZIP no es confiable
+func extractFile(entry *zip.File, destPath string, maxSize int64) (int64, error) {
+	if maxSize > 0 && entry.UncompressedSize64 > uint64(maxSize) {
+		return 0, errFileTooLarge
+	}
+
+	src, err := entry.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("error al crear archivo extraído: %w", err)
+	}
+
+	var reader io.Reader = src
+	if maxSize > 0 {
+		reader = io.LimitReader(src, maxSize+1)
+	}
+	written, err := io.Copy(dst, reader)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && maxSize > 0 && written > maxSize {
+		err = errFileTooLarge
+	}
+	if err != nil {
+		os.Remove(destPath)
+		return 0, err
+	}
+	return written, nil
+}
+
+// entryName retorna el nombre de la entrada con separadores "/". Los ZIP sin la marca
+// UTF-8 (ej: los del Explorador de Windows) usan la codificación CP437.
+func entryName(entry *zip.File) string {
+	name := entry.Name
+	if !utf8.ValidString(name) {
+		if decoded, err := charmap.CodePage437.NewDecoder().String(name); err == nil {
+			name = decoded
+		}
+	}
+	return strings.ReplaceAll(name, "\\", "/")
+}
+
+// safePath rechaza las rutas absolutas, con unidad de Windows o que salen del
+// directorio con ".."
+func safePath(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.ContainsRune(name, 0) {
+		return false
+	}
+	if len(name) >= 2 && name[1] == ':' {
+		return false
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// ignoredPath indica los archivos que agregan los sistemas operativos al comprimir
+func ignoredPath(name string) bool {
+	for _, segment := range strings.Split(strings.TrimSuffix(name, "/"), "/") {
+		if (strings.HasPrefix(segment, ".") && segment != ".") || segment == "__MACOSX" || strings.EqualFold(segment, "Thumbs.db") {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFiles(files []File) {
+	for _, file := range files {
+		os.Remove(file.Path)
+	}
+}
@@ -0,0 +1,171 @@
+package ziparchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type zipEntry struct {
+	header  zip.FileHeader
+	content []byte
+}
+
+func entry(name, content string) zipEntry {
+	return zipEntry{header: zip.FileHeader{Name: name, Method: zip.Deflate}, content: []byte(content)}
+}
+
+// buildZIP arma un ZIP en memoria con las entradas indicadas
+func buildZIP(t *testing.T, entries ...zipEntry) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := e.header
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(e.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func extract(t *testing.T, archive *bytes.Reader, limits Limits) (*Result, string, error) {
+	t.Helper()
+	dir := t.TempDir()
+	result, err := Extract(archive, archive.Size(), dir, limits)
+	return result, dir, err
+}
+
+func TestExtractWritesFilesWithGeneratedNames(t *testing.T) {
+	archive := buildZIP(t,
+		entry("cvs/", ""),
+		entry("cvs/Ana Pérez.PDF", "%PDF-1.4 ana"),
+		entry("cvs/juan.docx", "docx de juan"),
+		entry("__MACOSX/cvs/._juan.docx", "metadatos"),
+		entry("cvs/.DS_Store", "metadatos"),
+		entry("./notas.txt", "texto"),
+	)
+
+	result, dir, err := extract(t, archive, Limits{MaxFiles: 3})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	want := []File{
+		{Name: "Ana Pérez.PDF", Path: filepath.Join(dir, "001.pdf"), Size: 12},
+		{Name: "juan.docx", Path: filepath.Join(dir, "002.docx"), Size: 12},
+		{Name: "notas.txt", Path: filepath.Join(dir, "003.txt"), Size: 5},
+	}
+	if len(result.Files) != len(want) {
+		t.Fatalf("archivos = %+v", result.Files)
+	}
+	for i, file := range result.Files {
+		if file != want[i] {
+			t.Errorf("archivo %d = %+v, se esperaba %+v", i, file, want[i])
+		}
+	}
+	if content, _ := os.ReadFile(want[1].Path); string(content) != "docx de juan" {
+		t.Errorf("contenido = %q", content)
+	}
+	if len(result.Skipped) != 0 {
+		t.Errorf("omitidos = %+v", result.Skipped)
+	}
+}
+
+func TestExtractRejectsUnsafePaths(t *testing.T) {
+	for _, name := range []string{"../cv.pdf", "cvs/../../cv.pdf", "/etc/cv.pdf", `..\cv.pdf`, "C:/cv.pdf"} {
+		t.Run(name, func(t *testing.T) {
+			archive := buildZIP(t, entry("cv.pdf", "%PDF"), entry(name, "%PDF"))
+			_, dir, err := extract(t, archive, Limits{})
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Fatalf("se esperaba ErrUnsafePath, se obtuvo %v", err)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("no se debe extraer nada de un ZIP rechazado: %v", entries)
+			}
+		})
+	}
+}
+
+func TestExtractRejectsZipBombs(t *testing.T) {
+	zeros := strings.Repeat("\x00", 4*1024*1024)
+
+	_, _, err := extract(t, buildZIP(t, entry("bomba.txt", zeros)), Limits{MaxCompressionRatio: 100})
+	if !errors.Is(err, ErrCompressionRatio) {
+		t.Errorf("tasa de compresión: se esperaba ErrCompressionRatio, se obtuvo %v", err)
+	}
+
+	_, _, err = extract(t, buildZIP(t, entry("a.txt", zeros), entry("b.txt", zeros)), Limits{MaxTotalSize: 6 * 1024 * 1024})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("tamaño total: se esperaba ErrTooLarge, se obtuvo %v", err)
+	}
+}
+
+func TestExtractRejectsTooManyFiles(t *testing.T) {
+	archive := buildZIP(t, entry("a.pdf", "a"), entry("b.pdf", "b"), entry("c.pdf", "c"))
+	if _, _, err := extract(t, archive, Limits{MaxFiles: 2}); !errors.Is(err, ErrTooManyFiles) {
+		t.Errorf("se esperaba ErrTooManyFiles, se obtuvo %v", err)
+	}
+}
+
+func TestExtractSkipsUnsupportedEntries(t *testing.T) {
+	encrypted := entry("privado/cifrado.pdf", "datos cifrados")
+	encrypted.header.Flags = 0x1
+	link := entry("enlace.pdf", "/etc/passwd")
+	link.header.SetMode(os.ModeSymlink | 0o777)
+
+	archive := buildZIP(t, entry("grande.pdf", strings.Repeat("x", 2048)), encrypted, link, entry("cv.pdf", "%PDF"))
+	result, dir, err := extract(t, archive, Limits{MaxFileSize: 1024})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	if len(result.Files) != 1 || result.Files[0].Name != "cv.pdf" {
+		t.Fatalf("archivos = %+v", result.Files)
+	}
+	skipped := map[string]bool{}
+	for _, s := range result.Skipped {
+		skipped[s.Name] = true
+	}
+	for _, name := range []string{"grande.pdf", "cifrado.pdf", "enlace.pdf"} {
+		if !skipped[name] {
+			t.Errorf("%s debía omitirse: %+v", name, result.Skipped)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("en el directorio solo debe quedar el archivo extraído: %v", entries)
+	}
+}
+
+func TestExtractDecodesCP437Names(t *testing.T) {
+	// "Año.pdf" en CP437 (ñ = 0xA4), sin la marca UTF-8
+	legacy := entry("A\xa4o.pdf", "%PDF")
+	legacy.header.NonUTF8 = true
+
+	result, _, err := extract(t, buildZIP(t, legacy), Limits{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if name := result.Files[0].Name; name != "Año.pdf" {
+		t.Errorf("nombre = %q", name)
+	}
+}
+
+func TestExtractRejectsInvalidArchive(t *testing.T) {
+	archive := bytes.NewReader([]byte("no es un zip"))
+	if _, err := Extract(archive, archive.Size(), t.TempDir(), Limits{}); !errors.Is(err, ErrNotZip) {
+		t.Errorf("se esperaba ErrNotZip, se obtuvo %v", err)
+	}
+}